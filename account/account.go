@@ -5,25 +5,23 @@ import (
 	"errors"
 	"time"
 
-	"github.com/NethermindEth/juno/core/crypto"
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/starknet.go/contracts"
 	"github.com/NethermindEth/starknet.go/hash"
 	"github.com/NethermindEth/starknet.go/rpc"
-	"github.com/NethermindEth/starknet.go/utils"
 )
 
 var (
-	ErrNotAllParametersSet   = errors.New("not all neccessary parameters have been set")
-	ErrTxnTypeUnSupported    = errors.New("unsupported transction type")
+	ErrNotAllParametersSet   = hash.ErrNotAllParametersSet
+	ErrTxnTypeUnSupported    = hash.ErrTxnTypeUnSupported
 	ErrTxnVersionUnSupported = errors.New("unsupported transction version")
 	ErrFeltToBigInt          = errors.New("felt to BigInt error")
 )
 
 var (
-	PREFIX_TRANSACTION    = new(felt.Felt).SetBytes([]byte("invoke"))
-	PREFIX_DECLARE        = new(felt.Felt).SetBytes([]byte("declare"))
-	PREFIX_DEPLOY_ACCOUNT = new(felt.Felt).SetBytes([]byte("deploy_account"))
+	PREFIX_TRANSACTION    = hash.PREFIX_INVOKE
+	PREFIX_DECLARE        = hash.PREFIX_DECLARE
+	PREFIX_DEPLOY_ACCOUNT = hash.PREFIX_DEPLOY_ACCOUNT
 )
 
 //go:generate mockgen -destination=../mocks/mock_account.go -package=mocks -source=account.go AccountInterface
@@ -37,6 +35,10 @@ type AccountInterface interface {
 	SignDeclareTransaction(ctx context.Context, tx *rpc.DeclareTxnV2) error
 	PrecomputeAccountAddress(salt *felt.Felt, classHash *felt.Felt, constructorCalldata []*felt.Felt) (*felt.Felt, error)
 	WaitForTransactionReceipt(ctx context.Context, transactionHash *felt.Felt, pollInterval time.Duration) (*rpc.TransactionReceiptWithBlockInfo, error)
+	Execute(ctx context.Context, calls []rpc.FunctionCall, opts ...ExecuteOption) (*ExecuteResult, error)
+	EstimateFeeForCalls(ctx context.Context, calls []rpc.FunctionCall, opts ...ExecuteOption) (*rpc.FeeEstimate, error)
+	Declare(ctx context.Context, sierraPath, casmPath string, opts ...ExecuteOption) (*DeclareResult, error)
+	DeployViaUDC(ctx context.Context, classHash *felt.Felt, constructorCalldata []*felt.Felt, salt *felt.Felt, unique bool, opts ...ExecuteOption) (*DeployResult, error)
 }
 
 var _ AccountInterface = &Account{}
@@ -46,9 +48,9 @@ type Account struct {
 	provider       rpc.RpcProvider
 	ChainId        *felt.Felt
 	AccountAddress *felt.Felt
-	publicKey      string
 	CairoVersion   int
-	ks             Keystore
+	signer         Signer
+	nonceManager   *NonceManager
 }
 
 // NewAccount creates a new Account instance.
@@ -62,11 +64,25 @@ type Account struct {
 // - *Account: a pointer to newly created Account
 // - error: an error if any
 func NewAccount(provider rpc.RpcProvider, accountAddress *felt.Felt, publicKey string, keystore Keystore, cairoVersion int) (*Account, error) {
+	return NewAccountWithSigner(provider, accountAddress, NewStarkSigner(keystore, publicKey), cairoVersion)
+}
+
+// NewAccountWithSigner creates a new Account instance signing its transactions with the given signer,
+// for the accounts whose signature is not a single Stark signature, such as the Argent accounts with a guardian.
+//
+// Parameters:
+// - provider: is the provider of type rpc.RpcProvider
+// - accountAddress: is the account address of type *felt.Felt
+// - signer: is the signer giving the signatures of the transactions
+// - cairoVersion: is the Cairo version of the account contract
+// It returns:
+// - *Account: a pointer to newly created Account
+// - error: an error if any
+func NewAccountWithSigner(provider rpc.RpcProvider, accountAddress *felt.Felt, signer Signer, cairoVersion int) (*Account, error) {
 	account := &Account{
 		provider:       provider,
 		AccountAddress: accountAddress,
-		publicKey:      publicKey,
-		ks:             keystore,
+		signer:         signer,
 		CairoVersion:   cairoVersion,
 	}
 
@@ -79,7 +95,20 @@ func NewAccount(provider rpc.RpcProvider, accountAddress *felt.Felt, publicKey s
 	return account, nil
 }
 
-// Sign signs the given felt message using the account's private key.
+// SetNonceManager makes the account allocate the nonces of the transactions sent by Execute
+// with the given manager, instead of reading them from the node at each transaction.
+//
+// Parameters:
+// - manager: the nonce manager of the account, nil to read the nonces from the node
+// Returns:
+//
+//	none
+func (account *Account) SetNonceManager(manager *NonceManager) {
+	account.nonceManager = manager
+}
+
+// Sign signs the given felt message using the signer of the account, which gives the signature
+// filled in the transactions of all the types.
 //
 // Parameters:
 // - ctx: is the context used for the signing operation
@@ -88,17 +117,7 @@ func NewAccount(provider rpc.RpcProvider, accountAddress *felt.Felt, publicKey s
 // - []*felt.Felt: an array of signed felt messages
 // - error: an error, if any
 func (account *Account) Sign(ctx context.Context, msg *felt.Felt) ([]*felt.Felt, error) {
-
-	msgBig := utils.FeltToBigInt(msg)
-
-	s1, s2, err := account.ks.Sign(ctx, account.publicKey, msgBig)
-	if err != nil {
-		return nil, err
-	}
-	s1Felt := utils.BigIntToFelt(s1)
-	s2Felt := utils.BigIntToFelt(s2)
-
-	return []*felt.Felt{s1Felt, s2Felt}, nil
+	return account.signer.Sign(ctx, msg)
 }
 
 // SignInvokeTransaction signs and invokes a transaction.
@@ -174,203 +193,20 @@ func (account *Account) SignDeclareTransaction(ctx context.Context, tx *rpc.Decl
 // - *felt.Felt: the calculated transaction hash
 // - error: an error if any
 func (account *Account) TransactionHashDeployAccount(tx rpc.DeployAccountType, contractAddress *felt.Felt) (*felt.Felt, error) {
-
-	// https://docs.starknet.io/documentation/architecture_and_concepts/Network_Architecture/transactions/#deploy_account_transaction
-	switch txn := tx.(type) {
-	case rpc.DeployAccountTxn:
-		calldata := []*felt.Felt{txn.ClassHash, txn.ContractAddressSalt}
-		calldata = append(calldata, txn.ConstructorCalldata...)
-		calldataHash, err := hash.ComputeHashOnElementsFelt(calldata)
-		if err != nil {
-			return nil, err
-		}
-
-		versionFelt, err := new(felt.Felt).SetString(string(txn.Version))
-		if err != nil {
-			return nil, err
-		}
-
-		// https://docs.starknet.io/documentation/architecture_and_concepts/Network_Architecture/transactions/#deploy_account_hash_calculation
-		return hash.CalculateTransactionHashCommon(
-			PREFIX_DEPLOY_ACCOUNT,
-			versionFelt,
-			contractAddress,
-			&felt.Zero,
-			calldataHash,
-			txn.MaxFee,
-			account.ChainId,
-			[]*felt.Felt{txn.Nonce},
-		)
-	case rpc.DeployAccountTxnV3:
-		if txn.Version == "" || txn.ResourceBounds == (rpc.ResourceBoundsMapping{}) || txn.Nonce == nil || txn.PayMasterData == nil {
-			return nil, ErrNotAllParametersSet
-		}
-		calldata := []*felt.Felt{txn.ClassHash, txn.ContractAddressSalt}
-		calldata = append(calldata, txn.ConstructorCalldata...) //nolint:all
-
-		txnVersionFelt, err := new(felt.Felt).SetString(string(txn.Version))
-		if err != nil {
-			return nil, err
-		}
-		DAUint64, err := dataAvailabilityMode(txn.FeeMode, txn.NonceDataMode)
-		if err != nil {
-			return nil, err
-		}
-		tipUint64, err := txn.Tip.ToUint64()
-		if err != nil {
-			return nil, err
-		}
-		tipAndResourceHash, err := tipAndResourcesHash(tipUint64, txn.ResourceBounds)
-		if err != nil {
-			return nil, err
-		}
-		// https://docs.starknet.io/documentation/architecture_and_concepts/Network_Architecture/transactions/#deploy_account_hash_calculation
-		return crypto.PoseidonArray(
-			PREFIX_DEPLOY_ACCOUNT,
-			txnVersionFelt,
-			contractAddress,
-			tipAndResourceHash,
-			crypto.PoseidonArray(txn.PayMasterData...),
-			account.ChainId,
-			txn.Nonce,
-			new(felt.Felt).SetUint64(DAUint64),
-			crypto.PoseidonArray(txn.ConstructorCalldata...),
-			txn.ClassHash,
-			txn.ContractAddressSalt,
-		), nil
-	}
-	return nil, ErrTxnTypeUnSupported
+	return hash.TransactionHashDeployAccount(tx, contractAddress, account.ChainId)
 }
 
 // TransactionHashInvoke calculates the transaction hash for the given invoke transaction.
 //
 // Parameters:
-// - tx: The invoke transaction to calculate the hash for.
-//     The transaction can be of type InvokeTxnV0 or InvokeTxnV1.
-//     For InvokeTxnV0:
-//         the function checks if all the required parameters are set and then computes the transaction hash using the provided data.
-//     For InvokeTxnV1:
-//         the function performs similar checks and computes the transaction hash using the provided data.
+// - tx: The invoke transaction to calculate the hash for, an InvokeTxnV0, InvokeTxnV1 or InvokeTxnV3
 // Returns:
 // - *felt.Felt: The calculated transaction hash as a *felt.Felt
 // - error: an error, if any
-
+//
 // If the transaction type is unsupported, the function returns an error.
 func (account *Account) TransactionHashInvoke(tx rpc.InvokeTxnType) (*felt.Felt, error) {
-
-	// https://docs.starknet.io/documentation/architecture_and_concepts/Network_Architecture/transactions/#v0_hash_calculation
-	switch txn := tx.(type) {
-	case rpc.InvokeTxnV0:
-		if txn.Version == "" || len(txn.Calldata) == 0 || txn.MaxFee == nil || txn.EntryPointSelector == nil {
-			return nil, ErrNotAllParametersSet
-		}
-
-		calldataHash, err := hash.ComputeHashOnElementsFelt(txn.Calldata)
-		if err != nil {
-			return nil, err
-		}
-
-		txnVersionFelt, err := new(felt.Felt).SetString(string(txn.Version))
-		if err != nil {
-			return nil, err
-		}
-		return hash.CalculateTransactionHashCommon(
-			PREFIX_TRANSACTION,
-			txnVersionFelt,
-			txn.ContractAddress,
-			txn.EntryPointSelector,
-			calldataHash,
-			txn.MaxFee,
-			account.ChainId,
-			[]*felt.Felt{},
-		)
-
-	case rpc.InvokeTxnV1:
-		if txn.Version == "" || len(txn.Calldata) == 0 || txn.Nonce == nil || txn.MaxFee == nil || txn.SenderAddress == nil {
-			return nil, ErrNotAllParametersSet
-		}
-
-		calldataHash, err := hash.ComputeHashOnElementsFelt(txn.Calldata)
-		if err != nil {
-			return nil, err
-		}
-		txnVersionFelt, err := new(felt.Felt).SetString(string(txn.Version))
-		if err != nil {
-			return nil, err
-		}
-		return hash.CalculateTransactionHashCommon(
-			PREFIX_TRANSACTION,
-			txnVersionFelt,
-			txn.SenderAddress,
-			&felt.Zero,
-			calldataHash,
-			txn.MaxFee,
-			account.ChainId,
-			[]*felt.Felt{txn.Nonce},
-		)
-	case rpc.InvokeTxnV3:
-		// https://github.com/starknet-io/SNIPs/blob/main/SNIPS/snip-8.md#protocol-changes
-		if txn.Version == "" || txn.ResourceBounds == (rpc.ResourceBoundsMapping{}) || len(txn.Calldata) == 0 || txn.Nonce == nil || txn.SenderAddress == nil || txn.PayMasterData == nil || txn.AccountDeploymentData == nil {
-			return nil, ErrNotAllParametersSet
-		}
-
-		txnVersionFelt, err := new(felt.Felt).SetString(string(txn.Version))
-		if err != nil {
-			return nil, err
-		}
-		DAUint64, err := dataAvailabilityMode(txn.FeeMode, txn.NonceDataMode)
-		if err != nil {
-			return nil, err
-		}
-		tipUint64, err := txn.Tip.ToUint64()
-		if err != nil {
-			return nil, err
-		}
-		tipAndResourceHash, err := tipAndResourcesHash(tipUint64, txn.ResourceBounds)
-		if err != nil {
-			return nil, err
-		}
-		return crypto.PoseidonArray(
-			PREFIX_TRANSACTION,
-			txnVersionFelt,
-			txn.SenderAddress,
-			tipAndResourceHash,
-			crypto.PoseidonArray(txn.PayMasterData...),
-			account.ChainId,
-			txn.Nonce,
-			new(felt.Felt).SetUint64(DAUint64),
-			crypto.PoseidonArray(txn.AccountDeploymentData...),
-			crypto.PoseidonArray(txn.Calldata...),
-		), nil
-	}
-	return nil, ErrTxnTypeUnSupported
-}
-
-func tipAndResourcesHash(tip uint64, resourceBounds rpc.ResourceBoundsMapping) (*felt.Felt, error) {
-	l1Bytes, err := resourceBounds.L1Gas.Bytes(rpc.ResourceL1Gas)
-	if err != nil {
-		return nil, err
-	}
-	l2Bytes, err := resourceBounds.L2Gas.Bytes(rpc.ResourceL2Gas)
-	if err != nil {
-		return nil, err
-	}
-	l1Bounds := new(felt.Felt).SetBytes(l1Bytes)
-	l2Bounds := new(felt.Felt).SetBytes(l2Bytes)
-	return crypto.PoseidonArray(new(felt.Felt).SetUint64(tip), l1Bounds, l2Bounds), nil
-}
-
-func dataAvailabilityMode(feeDAMode, nonceDAMode rpc.DataAvailabilityMode) (uint64, error) {
-	const dataAvailabilityModeBits = 32
-	fee64, err := feeDAMode.UInt64()
-	if err != nil {
-		return 0, err
-	}
-	nonce64, err := nonceDAMode.UInt64()
-	if err != nil {
-		return 0, err
-	}
-	return fee64 + nonce64<<dataAvailabilityModeBits, nil
+	return hash.TransactionHashInvoke(tx, account.ChainId)
 }
 
 // TransactionHashDeclare calculates the transaction hash for declaring a transaction type.
@@ -381,6 +217,7 @@ func dataAvailabilityMode(feeDAMode, nonceDAMode rpc.DataAvailabilityMode) (uint
 //   - `rpc.DeclareTxnV0`
 //   - `rpc.DeclareTxnV1`
 //   - `rpc.DeclareTxnV2`
+//   - `rpc.DeclareTxnV3`
 //
 // Returns:
 // - *felt.Felt: the calculated transaction hash as `*felt.Felt` value
@@ -388,99 +225,7 @@ func dataAvailabilityMode(feeDAMode, nonceDAMode rpc.DataAvailabilityMode) (uint
 //
 // If the `tx` parameter is not one of the supported types, the function returns an error `ErrTxnTypeUnSupported`.
 func (account *Account) TransactionHashDeclare(tx rpc.DeclareTxnType) (*felt.Felt, error) {
-
-	switch txn := tx.(type) {
-	case rpc.DeclareTxnV0:
-		// Due to inconsistencies in version 0 hash calculation we don't calculate the hash
-		return nil, ErrTxnVersionUnSupported
-	case rpc.DeclareTxnV1:
-		if txn.SenderAddress == nil || txn.Version == "" || txn.ClassHash == nil || txn.MaxFee == nil || txn.Nonce == nil {
-			return nil, ErrNotAllParametersSet
-		}
-
-		calldataHash, err := hash.ComputeHashOnElementsFelt([]*felt.Felt{txn.ClassHash})
-		if err != nil {
-			return nil, err
-		}
-
-		txnVersionFelt, err := new(felt.Felt).SetString(string(txn.Version))
-		if err != nil {
-			return nil, err
-		}
-		return hash.CalculateTransactionHashCommon(
-			PREFIX_DECLARE,
-			txnVersionFelt,
-			txn.SenderAddress,
-			&felt.Zero,
-			calldataHash,
-			txn.MaxFee,
-			account.ChainId,
-			[]*felt.Felt{txn.Nonce},
-		)
-	case rpc.DeclareTxnV2:
-		if txn.CompiledClassHash == nil || txn.SenderAddress == nil || txn.Version == "" || txn.ClassHash == nil || txn.MaxFee == nil || txn.Nonce == nil {
-			return nil, ErrNotAllParametersSet
-		}
-
-		calldataHash, err := hash.ComputeHashOnElementsFelt([]*felt.Felt{txn.ClassHash})
-		if err != nil {
-			return nil, err
-		}
-
-		txnVersionFelt, err := new(felt.Felt).SetString(string(txn.Version))
-		if err != nil {
-			return nil, err
-		}
-		return hash.CalculateTransactionHashCommon(
-			PREFIX_DECLARE,
-			txnVersionFelt,
-			txn.SenderAddress,
-			&felt.Zero,
-			calldataHash,
-			txn.MaxFee,
-			account.ChainId,
-			[]*felt.Felt{txn.Nonce, txn.CompiledClassHash},
-		)
-	case rpc.DeclareTxnV3:
-		// https://github.com/starknet-io/SNIPs/blob/main/SNIPS/snip-8.md#protocol-changes
-		if txn.Version == "" || txn.ResourceBounds == (rpc.ResourceBoundsMapping{}) || txn.Nonce == nil || txn.SenderAddress == nil || txn.PayMasterData == nil || txn.AccountDeploymentData == nil ||
-			txn.ClassHash == nil || txn.CompiledClassHash == nil {
-			return nil, ErrNotAllParametersSet
-		}
-
-		txnVersionFelt, err := new(felt.Felt).SetString(string(txn.Version))
-		if err != nil {
-			return nil, err
-		}
-		DAUint64, err := dataAvailabilityMode(txn.FeeMode, txn.NonceDataMode)
-		if err != nil {
-			return nil, err
-		}
-		tipUint64, err := txn.Tip.ToUint64()
-		if err != nil {
-			return nil, err
-		}
-
-		tipAndResourceHash, err := tipAndResourcesHash(tipUint64, txn.ResourceBounds)
-		if err != nil {
-			return nil, err
-		}
-		return crypto.PoseidonArray(
-			PREFIX_DECLARE,
-			txnVersionFelt,
-			txn.SenderAddress,
-			tipAndResourceHash,
-			crypto.PoseidonArray(txn.PayMasterData...),
-			account.ChainId,
-			txn.Nonce,
-			new(felt.Felt).SetUint64(DAUint64),
-			crypto.PoseidonArray(txn.AccountDeploymentData...),
-			txn.ClassHash,
-			txn.CompiledClassHash,
-		), nil
-	}
-
-	return nil, ErrTxnTypeUnSupported
+	return hash.TransactionHashDeclare(tx, account.ChainId)
 }
 
 // PrecomputeAccountAddress calculates the precomputed address for an account.
@@ -1,23 +1,44 @@
 package account_test
 
 import (
+	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"log"
 	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"sort"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/NethermindEth/juno/core/crypto"
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/starknet.go/account"
 	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/curve"
 	"github.com/NethermindEth/starknet.go/devnet"
 	"github.com/NethermindEth/starknet.go/hash"
 	"github.com/NethermindEth/starknet.go/mocks"
 	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/rpc/rpctest"
+	"github.com/NethermindEth/starknet.go/typed"
 	"github.com/NethermindEth/starknet.go/utils"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
 	"github.com/golang/mock/gomock"
 	"github.com/joho/godotenv"
 	"github.com/stretchr/testify/require"
@@ -1198,3 +1219,1043 @@ func newDevnet(t *testing.T, url string) (*devnet.DevNet, []devnet.TestAccount,
 	acnts, err := devnet.Accounts()
 	return devnet, acnts, err
 }
+
+// TestExecuteMOCK tests that Account.Execute estimates, signs and submits V1 and V3 invoke
+// transactions, against an in-memory node.
+func TestExecuteMOCK(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	server := rpctest.NewServer()
+	t.Cleanup(server.Close)
+	provider, err := server.Provider()
+	require.NoError(t, err)
+
+	ks, pub, _ := account.GetRandomKeys()
+	accountAddress := utils.TestHexToFelt(t, "0x1234")
+	server.AddContract(accountAddress, utils.TestHexToFelt(t, "0xc1a55"))
+	acnt, err := account.NewAccount(provider, accountAddress, pub.String(), ks, 2)
+	require.NoError(t, err)
+
+	calls := []rpc.FunctionCall{{
+		ContractAddress:    utils.TestHexToFelt(t, "0x4dead"),
+		EntryPointSelector: utils.GetSelectorFromNameFelt("transfer"),
+		Calldata:           []*felt.Felt{new(felt.Felt).SetUint64(1), new(felt.Felt)},
+	}}
+
+	type testSetType struct {
+		Options         []account.ExecuteOption
+		ExpectedVersion rpc.TransactionVersion
+		ExpectedNonce   uint64
+	}
+	testSet := []testSetType{
+		{
+			ExpectedVersion: rpc.TransactionV1,
+			ExpectedNonce:   0,
+		},
+		{
+			Options:         []account.ExecuteOption{account.WithTxnVersion(rpc.TransactionV3), account.WithTip(1), account.WithFeeMultiplier(2)},
+			ExpectedVersion: rpc.TransactionV3,
+			ExpectedNonce:   1,
+		},
+		{
+			Options:         []account.ExecuteOption{account.WithNonce(new(felt.Felt).SetUint64(2))},
+			ExpectedVersion: rpc.TransactionV1,
+			ExpectedNonce:   2,
+		},
+	}
+	for _, test := range testSet {
+		result, err := acnt.Execute(context.Background(), calls, test.Options...)
+		require.NoError(t, err)
+		require.Equal(t, rpctest.DefaultFeeRule(rpctest.Txn{Version: test.ExpectedVersion}), result.FeeEstimate)
+
+		txn, err := acnt.TransactionByHash(context.Background(), result.TransactionHash)
+		require.NoError(t, err)
+		switch txn := txn.(type) {
+		case rpc.InvokeTxnV1:
+			require.Equal(t, test.ExpectedVersion, txn.Version)
+			require.Equal(t, new(felt.Felt).SetUint64(test.ExpectedNonce), txn.Nonce)
+			require.Equal(t, new(felt.Felt).SetUint64(1500), txn.MaxFee)
+		case rpc.InvokeTxnV3:
+			require.Equal(t, test.ExpectedVersion, txn.Version)
+			require.Equal(t, new(felt.Felt).SetUint64(test.ExpectedNonce), txn.Nonce)
+			require.Equal(t, rpc.ResourceBounds{MaxAmount: "0x7d0", MaxPricePerUnit: "0x2"}, txn.ResourceBounds.L1Gas)
+			require.Equal(t, rpc.U64("0x1"), txn.Tip)
+		default:
+			t.Fatalf("unexpected transaction %T", txn)
+		}
+	}
+	require.Equal(t, new(felt.Felt).SetUint64(3), server.Nonce(accountAddress))
+
+	_, err = acnt.Execute(context.Background(), calls, account.WithFeeMultiplier(0.5))
+	require.ErrorIs(t, err, account.ErrInvalidFeeMultiplier)
+
+	// a node following spec v0.8 receives the bounds of the L1 data gas, signed with the transaction
+	server.SetSpecVersion("0.8.0")
+	provider, err = server.Provider()
+	require.NoError(t, err)
+	acnt, err = account.NewAccount(provider, accountAddress, pub.String(), ks, 2)
+	require.NoError(t, err)
+	result, err := acnt.Execute(context.Background(), calls, account.WithTxnVersion(rpc.TransactionV3), account.WithFeeMultiplier(2))
+	require.NoError(t, err)
+	txn, err := acnt.TransactionByHash(context.Background(), result.TransactionHash)
+	require.NoError(t, err)
+	invoke, ok := txn.(rpc.InvokeTxnV3)
+	require.True(t, ok)
+	require.Equal(t, rpc.ResourceBounds{MaxAmount: "0x7d0", MaxPricePerUnit: "0x2"}, invoke.ResourceBounds.L1Gas)
+	require.Equal(t, &rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x2"}, invoke.ResourceBounds.L1DataGas)
+	txHash, err := acnt.TransactionHashInvoke(invoke)
+	require.NoError(t, err)
+	withoutDataGas := invoke
+	withoutDataGas.ResourceBounds.L1DataGas = nil
+	otherHash, err := acnt.TransactionHashInvoke(withoutDataGas)
+	require.NoError(t, err)
+	require.NotEqual(t, txHash, otherHash)
+	pubX := utils.FeltToBigInt(pub)
+	require.True(t, curve.Curve.Verify(utils.FeltToBigInt(txHash), utils.FeltToBigInt(invoke.Signature[0]), utils.FeltToBigInt(invoke.Signature[1]), pubX, curve.Curve.GetYCoordinate(pubX)))
+}
+
+// TestNonceManagerMOCK tests that the NonceManager allocates distinct nonces to concurrent callers,
+// reuses the released nonces and resyncs after a nonce error.
+func TestNonceManagerMOCK(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
+
+	ctx := context.Background()
+	accountAddress := utils.TestHexToFelt(t, "0x1234")
+	pending := rpc.BlockID{Tag: "pending"}
+	mockRpcProvider.EXPECT().Nonce(ctx, pending, accountAddress).Return(new(felt.Felt).SetUint64(5), nil)
+	manager := account.NewNonceManager(mockRpcProvider, accountAddress, nil)
+
+	var wg sync.WaitGroup
+	nonces := make([]uint64, 10)
+	for i := range nonces {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			nonce, err := manager.Next(ctx)
+			require.NoError(t, err)
+			nonces[i] = nonce.Bits()[0]
+		}(i)
+	}
+	wg.Wait()
+	sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+	require.Equal(t, []uint64{5, 6, 7, 8, 9, 10, 11, 12, 13, 14}, nonces)
+
+	// a transaction rejected for another reason than its nonce gives its nonce back
+	err := manager.Submit(ctx, func(ctx context.Context, nonce *felt.Felt) error {
+		require.Equal(t, new(felt.Felt).SetUint64(15), nonce)
+		return rpc.ErrInsufficientAccountBalance
+	})
+	require.Equal(t, rpc.ErrInsufficientAccountBalance, err)
+	require.NoError(t, manager.Release(ctx, new(felt.Felt).SetUint64(7)))
+	for _, expected := range []uint64{7, 15} {
+		nonce, err := manager.Next(ctx)
+		require.NoError(t, err)
+		require.Equal(t, new(felt.Felt).SetUint64(expected), nonce)
+	}
+
+	// a nonce error makes the manager read the nonce of the account again
+	err = manager.Submit(ctx, func(ctx context.Context, nonce *felt.Felt) error {
+		return rpc.ErrInvalidTransactionNonce
+	})
+	require.Equal(t, rpc.ErrInvalidTransactionNonce, err)
+	mockRpcProvider.EXPECT().Nonce(ctx, pending, accountAddress).Return(new(felt.Felt).SetUint64(30), nil)
+	nonce, err := manager.Next(ctx)
+	require.NoError(t, err)
+	require.Equal(t, new(felt.Felt).SetUint64(30), nonce)
+}
+
+// TestExecuteWithNonceManagerMOCK tests that Account.Execute allocates its nonces with the nonce manager of the account.
+func TestExecuteWithNonceManagerMOCK(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	server := rpctest.NewServer()
+	t.Cleanup(server.Close)
+	provider, err := server.Provider()
+	require.NoError(t, err)
+
+	ks, pub, _ := account.GetRandomKeys()
+	accountAddress := utils.TestHexToFelt(t, "0x1234")
+	server.AddContract(accountAddress, utils.TestHexToFelt(t, "0xc1a55"))
+	acnt, err := account.NewAccount(provider, accountAddress, pub.String(), ks, 2)
+	require.NoError(t, err)
+	acnt.SetNonceManager(account.NewNonceManager(provider, accountAddress, nil))
+
+	calls := []rpc.FunctionCall{{
+		ContractAddress:    utils.TestHexToFelt(t, "0x4dead"),
+		EntryPointSelector: utils.GetSelectorFromNameFelt("transfer"),
+		Calldata:           []*felt.Felt{},
+	}}
+	ctx := context.Background()
+	_, err = acnt.Execute(ctx, calls)
+	require.NoError(t, err)
+
+	// the nonce of the rejected transaction is reused
+	server.FailNext("starknet_addInvokeTransaction", rpc.ErrInsufficientAccountBalance)
+	_, err = acnt.Execute(ctx, calls)
+	require.Error(t, err)
+	_, err = acnt.Execute(ctx, calls)
+	require.NoError(t, err)
+	require.Equal(t, new(felt.Felt).SetUint64(2), server.Nonce(accountAddress))
+
+	// the nonce of the account changed behind the manager
+	server.SetNonce(accountAddress, new(felt.Felt).SetUint64(10))
+	_, err = acnt.Execute(ctx, calls)
+	require.Equal(t, rpc.ErrInvalidTransactionNonce.Code, err.(*rpc.RPCError).Code)
+	_, err = acnt.Execute(ctx, calls)
+	require.NoError(t, err)
+	require.Equal(t, new(felt.Felt).SetUint64(11), server.Nonce(accountAddress))
+}
+
+// TestEstimateFeeForCallsMOCK tests that the transactions whose fee is estimated by EstimateFeeForCalls
+// and Execute are signed with their query version, and that the query versions cannot be broadcast.
+func TestEstimateFeeForCallsMOCK(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	ctx := context.Background()
+	server := rpctest.NewServer()
+	t.Cleanup(server.Close)
+	provider, err := server.Provider()
+	require.NoError(t, err)
+
+	ks, pub, priv := account.GetRandomKeys()
+	accountAddress := utils.TestHexToFelt(t, "0x1234")
+	server.AddContract(accountAddress, utils.TestHexToFelt(t, "0xc1a55"))
+	acnt, err := account.NewAccount(provider, accountAddress, pub.String(), ks, 2)
+	require.NoError(t, err)
+	pubX, pubY, err := curve.Curve.PrivateToPoint(utils.FeltToBigInt(priv))
+	require.NoError(t, err)
+
+	// the estimated transactions are checked against the signature of their hash, with their own version
+	var estimated []rpc.TransactionVersion
+	server.Handle("starknet_estimateFee", func(params []json.RawMessage) (interface{}, error) {
+		var txns []json.RawMessage
+		if err := json.Unmarshal(params[0], &txns); err != nil {
+			return nil, err
+		}
+		estimates := make([]rpc.FeeEstimate, len(txns))
+		for i, raw := range txns {
+			var header struct {
+				Version rpc.TransactionVersion `json:"version"`
+			}
+			if err := json.Unmarshal(raw, &header); err != nil {
+				return nil, err
+			}
+			var txn rpc.Transaction
+			var signature []*felt.Felt
+			if header.Version.Base() == rpc.TransactionV3 {
+				var v3 rpc.InvokeTxnV3
+				if err := json.Unmarshal(raw, &v3); err != nil {
+					return nil, err
+				}
+				txn, signature = v3, v3.Signature
+			} else {
+				var v1 rpc.InvokeTxnV1
+				if err := json.Unmarshal(raw, &v1); err != nil {
+					return nil, err
+				}
+				txn, signature = v1, v1.Signature
+			}
+			txnHash, err := hash.TransactionHash(txn, acnt.ChainId)
+			if err != nil {
+				return nil, err
+			}
+			if len(signature) != 2 || !curve.Curve.Verify(utils.FeltToBigInt(txnHash), utils.FeltToBigInt(signature[0]), utils.FeltToBigInt(signature[1]), pubX, pubY) {
+				return nil, rpc.ErrValidationFailure
+			}
+			estimated = append(estimated, header.Version)
+			estimates[i] = rpctest.DefaultFeeRule(rpctest.Txn{Version: header.Version})
+		}
+		return estimates, nil
+	})
+
+	calls := []rpc.FunctionCall{{
+		ContractAddress:    utils.TestHexToFelt(t, "0x4dead"),
+		EntryPointSelector: utils.GetSelectorFromNameFelt("transfer"),
+		Calldata:           []*felt.Felt{new(felt.Felt).SetUint64(1), new(felt.Felt)},
+	}}
+	for _, version := range []rpc.TransactionVersion{rpc.TransactionV1, rpc.TransactionV3} {
+		estimate, err := acnt.EstimateFeeForCalls(ctx, calls, account.WithTxnVersion(version))
+		require.NoError(t, err)
+		require.Equal(t, rpctest.DefaultFeeRule(rpctest.Txn{Version: version}), *estimate)
+	}
+	require.Equal(t, []rpc.TransactionVersion{rpc.TransactionV1WithQueryBit, rpc.TransactionV3WithQueryBit}, estimated)
+	require.Equal(t, new(felt.Felt), server.Nonce(accountAddress))
+
+	result, err := acnt.Execute(ctx, calls)
+	require.NoError(t, err)
+	require.Equal(t, rpc.TransactionV1WithQueryBit, estimated[2])
+	txn, err := acnt.TransactionByHash(ctx, result.TransactionHash)
+	require.NoError(t, err)
+	require.Equal(t, rpc.TransactionV1, txn.(rpc.InvokeTxnV1).Version)
+
+	_, err = acnt.AddInvokeTransaction(ctx, rpc.BroadcastInvokev1Txn{InvokeTxnV1: rpc.InvokeTxnV1{Version: rpc.TransactionV1WithQueryBit}})
+	require.ErrorIs(t, err, rpc.ErrQueryVersionBroadcast)
+}
+
+// TestDeclareMOCK tests that Account.Declare declares the classes with V2 and V3 transactions,
+// and skips the classes which are already declared.
+func TestDeclareMOCK(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	server := rpctest.NewServer()
+	t.Cleanup(server.Close)
+	provider, err := server.Provider()
+	require.NoError(t, err)
+
+	ks, pub, _ := account.GetRandomKeys()
+	accountAddress := utils.TestHexToFelt(t, "0x1234")
+	server.AddContract(accountAddress, utils.TestHexToFelt(t, "0xc1a55"))
+	acnt, err := account.NewAccount(provider, accountAddress, pub.String(), ks, 2)
+	require.NoError(t, err)
+
+	type testSetType struct {
+		SierraPath       string
+		CasmPath         string
+		Options          []account.ExecuteOption
+		ExpectedDeclared bool
+	}
+	testSet := []testSetType{
+		{
+			SierraPath:       "./tests/hello_world_compiled.sierra.json",
+			CasmPath:         "./tests/hello_world_compiled.casm.json",
+			ExpectedDeclared: true,
+		},
+		{
+			SierraPath:       "./tests/hello_starknet_compiled.sierra.json",
+			CasmPath:         "./tests/hello_starknet_compiled.casm.json",
+			Options:          []account.ExecuteOption{account.WithTxnVersion(rpc.TransactionV3)},
+			ExpectedDeclared: true,
+		},
+		{
+			SierraPath:       "./tests/hello_starknet_compiled.sierra.json",
+			CasmPath:         "./tests/hello_starknet_compiled.casm.json",
+			ExpectedDeclared: false,
+		},
+	}
+	for _, test := range testSet {
+		result, err := acnt.Declare(context.Background(), test.SierraPath, test.CasmPath, test.Options...)
+		require.NoError(t, err)
+		require.NotNil(t, result.ClassHash)
+		if !test.ExpectedDeclared {
+			require.Nil(t, result.TransactionHash)
+			require.Nil(t, result.FeeEstimate)
+			continue
+		}
+		require.NotNil(t, result.TransactionHash)
+		require.NotNil(t, result.FeeEstimate)
+		_, err = acnt.Class(context.Background(), rpc.BlockID{Tag: "latest"}, result.ClassHash)
+		require.NoError(t, err)
+	}
+	require.Equal(t, new(felt.Felt).SetUint64(2), server.Nonce(accountAddress))
+
+	_, err = acnt.Declare(context.Background(), testSet[0].SierraPath, testSet[0].CasmPath, account.WithTxnVersion(rpc.TransactionV1))
+	require.ErrorIs(t, err, account.ErrTxnVersionUnSupported)
+}
+
+// TestDeployViaUDCMOCK tests that Account.DeployViaUDC calls the UDC and checks the address
+// of the deployed contract against the ContractDeployed event of the UDC.
+func TestDeployViaUDCMOCK(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	server := rpctest.NewServer()
+	t.Cleanup(server.Close)
+	provider, err := server.Provider()
+	require.NoError(t, err)
+
+	ks, pub, _ := account.GetRandomKeys()
+	accountAddress := utils.TestHexToFelt(t, "0x1234")
+	server.AddContract(accountAddress, utils.TestHexToFelt(t, "0xc1a55"))
+	acnt, err := account.NewAccount(provider, accountAddress, pub.String(), ks, 2)
+	require.NoError(t, err)
+
+	contractDeployed := utils.GetSelectorFromNameFelt("ContractDeployed")
+	// the UDC emits the address of the contract, decoded from the single call of the multicall
+	server.SetEventRule(func(txn rpctest.Txn) []rpc.Event {
+		args := txn.Calldata[4:]
+		address, err := account.PrecomputeUDCAddress(txn.SenderAddress, args[0], args[4:], args[1], !args[2].IsZero())
+		require.NoError(t, err)
+		return []rpc.Event{{FromAddress: account.UDCAddress, Keys: []*felt.Felt{contractDeployed}, Data: []*felt.Felt{address}}}
+	})
+
+	classHash := utils.TestHexToFelt(t, "0xc1a55")
+	constructorCalldata := []*felt.Felt{new(felt.Felt).SetUint64(42)}
+	salt := new(felt.Felt).SetUint64(7)
+
+	type testSetType struct {
+		Salt   *felt.Felt
+		Unique bool
+	}
+	testSet := []testSetType{
+		{Salt: salt, Unique: true},
+		{Salt: salt, Unique: false},
+		{Salt: nil, Unique: true},
+	}
+	for _, test := range testSet {
+		result, err := acnt.DeployViaUDC(context.Background(), classHash, constructorCalldata, test.Salt, test.Unique, account.WithPollInterval(time.Millisecond))
+		require.NoError(t, err)
+		require.NotNil(t, result.TransactionHash)
+		if test.Salt != nil {
+			expected, err := account.PrecomputeUDCAddress(accountAddress, classHash, constructorCalldata, test.Salt, test.Unique)
+			require.NoError(t, err)
+			require.Equal(t, expected, result.ContractAddress)
+		}
+	}
+
+	server.SetEventRule(func(txn rpctest.Txn) []rpc.Event {
+		return []rpc.Event{{FromAddress: account.UDCAddress, Keys: []*felt.Felt{contractDeployed}, Data: []*felt.Felt{new(felt.Felt).SetUint64(1)}}}
+	})
+	_, err = acnt.DeployViaUDC(context.Background(), classHash, constructorCalldata, salt, true, account.WithPollInterval(time.Millisecond))
+	require.ErrorIs(t, err, account.ErrUnexpectedContractAddress)
+
+	server.SetEventRule(nil)
+	_, err = acnt.DeployViaUDC(context.Background(), classHash, constructorCalldata, salt, true, account.WithPollInterval(time.Millisecond))
+	require.ErrorIs(t, err, account.ErrContractDeployedNotFound)
+}
+
+// TestDeployAccountMOCK tests that DeployAccount deploys the accounts of each type at their
+// precomputed address, and waits for the funding of the account when asked to.
+func TestDeployAccountMOCK(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	server := rpctest.NewServer()
+	t.Cleanup(server.Close)
+	provider, err := server.Provider()
+	require.NoError(t, err)
+
+	type testSetType struct {
+		AccountType       account.AccountType
+		Options           []account.ExecuteOption
+		ExpectedSignature int
+	}
+	testSet := []testSetType{
+		{AccountType: account.AccountOpenZeppelin, ExpectedSignature: 2},
+		{AccountType: account.AccountArgent, Options: []account.ExecuteOption{account.WithTxnVersion(rpc.TransactionV3)}, ExpectedSignature: 2},
+		{AccountType: account.AccountBraavos, ExpectedSignature: 15},
+	}
+	for _, test := range testSet {
+		ks, pub, _ := account.GetRandomKeys()
+		options := append(test.Options, account.WithPollInterval(time.Millisecond))
+		result, err := account.DeployAccount(context.Background(), provider, test.AccountType, ks, pub, nil, options...)
+		require.NoError(t, err, test.AccountType.String())
+
+		expectedAddress, err := test.AccountType.Address(pub, nil)
+		require.NoError(t, err)
+		require.Equal(t, expectedAddress, result.ContractAddress)
+		require.Equal(t, expectedAddress, result.Account.AccountAddress)
+		require.Equal(t, new(felt.Felt).SetUint64(1), server.Nonce(expectedAddress))
+
+		txn, err := provider.TransactionByHash(context.Background(), result.TransactionHash)
+		require.NoError(t, err)
+		var signature []*felt.Felt
+		switch txn := txn.(type) {
+		case rpc.DeployAccountTxn:
+			signature = txn.Signature
+		case rpc.DeployAccountTxnV3:
+			signature = txn.Signature
+		default:
+			t.Fatalf("unexpected transaction %T", txn)
+		}
+		require.Len(t, signature, test.ExpectedSignature)
+		// TransactionByHash decodes the V3 deploy account transactions without their resource bounds
+		v1, ok := txn.(rpc.DeployAccountTxn)
+		if !ok || v1.Version != rpc.TransactionV1 {
+			continue
+		}
+		txHash, err := result.Account.TransactionHashDeployAccount(v1, expectedAddress)
+		require.NoError(t, err)
+
+		// the transaction hash is signed first, then Braavos signs the hash of its auxiliary data:
+		// the implementation class, the 9 unset deployment parameters and the chain id
+		pubX := utils.FeltToBigInt(pub)
+		verify := func(hash *felt.Felt, r, s *felt.Felt) bool {
+			return curve.Curve.Verify(utils.FeltToBigInt(hash), utils.FeltToBigInt(r), utils.FeltToBigInt(s), pubX, curve.Curve.GetYCoordinate(pubX))
+		}
+		require.True(t, verify(txHash, signature[0], signature[1]), test.AccountType.String())
+		if test.AccountType == account.AccountBraavos {
+			auxData := signature[2:13]
+			require.Equal(t, account.BraavosAccountClassHash, auxData[0])
+			for _, param := range auxData[1:10] {
+				require.Equal(t, &felt.Zero, param)
+			}
+			require.Equal(t, result.Account.ChainId, auxData[10])
+			require.True(t, verify(crypto.PoseidonArray(auxData...), signature[13], signature[14]))
+		}
+	}
+
+	balanceOf := utils.GetSelectorFromNameFelt("balanceOf")
+	server.AddContract(account.ETHTokenAddress, utils.TestHexToFelt(t, "0xe7"))
+	server.SetCallResult(account.ETHTokenAddress, balanceOf, new(felt.Felt), new(felt.Felt))
+
+	ks, pub, _ := account.GetRandomKeys()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = account.DeployAccount(ctx, provider, account.AccountOpenZeppelin, ks, pub, nil, account.WithFundingWait(), account.WithPollInterval(time.Millisecond))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		// the default fee estimate of 1000 times the default fee multiplier
+		server.SetCallResult(account.ETHTokenAddress, balanceOf, new(felt.Felt).SetUint64(1500), new(felt.Felt))
+	}()
+	result, err := account.DeployAccount(context.Background(), provider, account.AccountOpenZeppelin, ks, pub, nil, account.WithFundingWait(), account.WithPollInterval(time.Millisecond))
+	require.NoError(t, err)
+	require.Equal(t, new(felt.Felt).SetUint64(1), server.Nonce(result.ContractAddress))
+
+	_, err = account.DeployAccount(context.Background(), provider, account.AccountType(42), ks, pub, nil)
+	require.ErrorIs(t, err, account.ErrUnknownAccountType)
+}
+
+// TestSignersMOCK tests that the signers give signatures verified by the public keys of their scheme,
+// and that an account fills its transactions with the signature of its signer.
+func TestSignersMOCK(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	ctx := context.Background()
+	msgHash := utils.TestHexToFelt(t, "0x2789daed76c8b750d5a609a706481034db9dc8b63ae01f505d21e75a8fc2336")
+	digest := msgHash.Bytes()
+
+	ks, pub, priv := account.GetRandomKeys()
+	starkSigner := account.NewStarkSigner(ks, pub.String())
+	signature, err := starkSigner.Sign(ctx, msgHash)
+	require.NoError(t, err)
+	require.Len(t, signature, 2)
+	pubX, pubY, err := curve.Curve.PrivateToPoint(utils.FeltToBigInt(priv))
+	require.NoError(t, err)
+	require.True(t, curve.Curve.Verify(utils.FeltToBigInt(msgHash), utils.FeltToBigInt(signature[0]), utils.FeltToBigInt(signature[1]), pubX, pubY))
+
+	// u256 joins the low and high 128 bits of an u256
+	u256 := func(low, high *felt.Felt) []byte {
+		value := new(big.Int).Lsh(utils.FeltToBigInt(high), 128)
+		return value.Add(value, utils.FeltToBigInt(low)).FillBytes(make([]byte, 32))
+	}
+
+	ethKey, err := ethcrypto.GenerateKey()
+	require.NoError(t, err)
+	for _, eip191 := range []bool{false, true} {
+		signature, err := account.NewSecp256k1Signer(ethKey, eip191).Sign(ctx, msgHash)
+		require.NoError(t, err)
+		require.Len(t, signature, 5)
+		hash := digest[:]
+		if eip191 {
+			hash = ethcrypto.Keccak256([]byte("\x19Ethereum Signed Message:\n32"), hash)
+		}
+		sig := append(u256(signature[0], signature[1]), u256(signature[2], signature[3])...)
+		recovered, err := ethcrypto.SigToPub(hash, append(sig, byte(utils.FeltToBigInt(signature[4]).Uint64())))
+		require.NoError(t, err)
+		require.Equal(t, ethKey.PublicKey, *recovered)
+	}
+
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	p256Signer, err := account.NewSecp256r1Signer(p256Key)
+	require.NoError(t, err)
+	signature, err = p256Signer.Sign(ctx, msgHash)
+	require.NoError(t, err)
+	require.Len(t, signature, 4)
+	r, s := new(big.Int).SetBytes(u256(signature[0], signature[1])), new(big.Int).SetBytes(u256(signature[2], signature[3]))
+	require.True(t, ecdsa.Verify(&p256Key.PublicKey, digest[:], r, s))
+	require.LessOrEqual(t, s.Cmp(new(big.Int).Rsh(elliptic.P256().Params().N, 1)), 0)
+	_, err = account.NewSecp256r1Signer(ethKey)
+	require.Error(t, err)
+
+	ownerSignature, err := starkSigner.Sign(ctx, msgHash)
+	require.NoError(t, err)
+	signature, err = account.NewGuardedSigner(starkSigner, nil).Sign(ctx, msgHash)
+	require.NoError(t, err)
+	require.Equal(t, ownerSignature, signature)
+
+	guardianKs, guardianPub, _ := account.GetRandomKeys()
+	guardianSigner := account.NewStarkSigner(guardianKs, guardianPub.String())
+	guardianSignature, err := guardianSigner.Sign(ctx, msgHash)
+	require.NoError(t, err)
+	guardedSigner := account.NewGuardedSigner(starkSigner, guardianSigner)
+	signature, err = guardedSigner.Sign(ctx, msgHash)
+	require.NoError(t, err)
+	require.Equal(t, append(ownerSignature, guardianSignature...), signature)
+
+	server := rpctest.NewServer()
+	t.Cleanup(server.Close)
+	provider, err := server.Provider()
+	require.NoError(t, err)
+	accountAddress := utils.TestHexToFelt(t, "0x1234")
+	server.AddContract(accountAddress, utils.TestHexToFelt(t, "0xc1a55"))
+	acnt, err := account.NewAccountWithSigner(provider, accountAddress, guardedSigner, 2)
+	require.NoError(t, err)
+	result, err := acnt.Execute(ctx, []rpc.FunctionCall{{
+		ContractAddress:    utils.TestHexToFelt(t, "0x4dead"),
+		EntryPointSelector: utils.GetSelectorFromNameFelt("transfer"),
+		Calldata:           []*felt.Felt{},
+	}})
+	require.NoError(t, err)
+	txn, err := acnt.TransactionByHash(ctx, result.TransactionHash)
+	require.NoError(t, err)
+	require.Len(t, txn.(rpc.InvokeTxnV1).Signature, 4)
+}
+
+// TestOutsideExecutionMOCK tests that the outside executions are hashed, signed and serialized for
+// the execute_from_outside functions, and that their support is read from the SRC-5 interface of the account.
+func TestOutsideExecutionMOCK(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
+	mockRpcProvider.EXPECT().ChainID(context.Background()).Return("SN_SEPOLIA", nil)
+
+	ks, pub, priv := account.GetRandomKeys()
+	accountAddress := utils.TestHexToFelt(t, "0x1234")
+	acnt, err := account.NewAccount(mockRpcProvider, accountAddress, pub.String(), ks, 2)
+	require.NoError(t, err)
+	pubX, pubY, err := curve.Curve.PrivateToPoint(utils.FeltToBigInt(priv))
+	require.NoError(t, err)
+
+	oe := account.OutsideExecution{
+		Caller:        account.AnyCaller,
+		Nonce:         new(felt.Felt).SetUint64(1),
+		ExecuteAfter:  0,
+		ExecuteBefore: 1_900_000_000,
+		Calls: []rpc.FunctionCall{{
+			ContractAddress:    utils.TestHexToFelt(t, "0x4dead"),
+			EntryPointSelector: utils.GetSelectorFromNameFelt("transfer"),
+			Calldata:           []*felt.Felt{new(felt.Felt).SetUint64(7), new(felt.Felt).SetUint64(8)},
+		}},
+	}
+
+	type testSetType struct {
+		Version          account.OutsideExecutionVersion
+		ExpectedSelector string
+	}
+	testSet := []testSetType{
+		{Version: account.OutsideExecutionV1, ExpectedSelector: "execute_from_outside"},
+		{Version: account.OutsideExecutionV2, ExpectedSelector: "execute_from_outside_v2"},
+	}
+	hashes := make(map[felt.Felt]bool)
+	for _, test := range testSet {
+		msgHash, err := oe.Hash(test.Version, accountAddress, acnt.ChainId)
+		require.NoError(t, err)
+		hashes[*msgHash] = true
+
+		otherAccountHash, err := oe.Hash(test.Version, utils.TestHexToFelt(t, "0x5678"), acnt.ChainId)
+		require.NoError(t, err)
+		require.NotEqual(t, msgHash, otherAccountHash)
+
+		call, err := acnt.OutsideExecutionCall(context.Background(), oe, test.Version)
+		require.NoError(t, err)
+		require.Equal(t, accountAddress, call.ContractAddress)
+		require.Equal(t, utils.GetSelectorFromNameFelt(test.ExpectedSelector), call.EntryPointSelector)
+
+		// caller, nonce, execute_after, execute_before, the call, then the signature
+		require.Len(t, call.Calldata, 5+5+3)
+		require.Equal(t, oe.Caller, call.Calldata[0])
+		require.Equal(t, new(felt.Felt).SetUint64(1_900_000_000), call.Calldata[3])
+		require.Equal(t, new(felt.Felt).SetUint64(1), call.Calldata[4])
+		require.Equal(t, new(felt.Felt).SetUint64(2), call.Calldata[7])
+		require.Equal(t, new(felt.Felt).SetUint64(2), call.Calldata[10])
+		r, s := utils.FeltToBigInt(call.Calldata[11]), utils.FeltToBigInt(call.Calldata[12])
+		require.True(t, curve.Curve.Verify(utils.FeltToBigInt(msgHash), r, s, pubX, pubY))
+	}
+	require.Len(t, hashes, 2)
+
+	// the same outside executions as SNIP-12 typed data, whose type hashes are the ones of the
+	// SNIP-9 contracts of OpenZeppelin and Argent
+	selector := utils.GetSelectorFromNameFelt("transfer").String()
+	type typedDataTestSetType struct {
+		Version            account.OutsideExecutionVersion
+		TypedData          string
+		DomainType         string
+		ExpectedTypeHash   string
+		ExpectedDomainHash string
+	}
+	typedDataTestSet := []typedDataTestSetType{
+		{
+			Version: account.OutsideExecutionV1,
+			TypedData: `{
+				"types": {
+					"StarkNetDomain": [
+						{"name": "name", "type": "felt"}, {"name": "version", "type": "felt"}, {"name": "chainId", "type": "felt"}
+					],
+					"OutsideExecution": [
+						{"name": "caller", "type": "felt"}, {"name": "nonce", "type": "felt"},
+						{"name": "execute_after", "type": "felt"}, {"name": "execute_before", "type": "felt"},
+						{"name": "calls_len", "type": "felt"}, {"name": "calls", "type": "OutsideCall*"}
+					],
+					"OutsideCall": [
+						{"name": "to", "type": "felt"}, {"name": "selector", "type": "felt"},
+						{"name": "calldata_len", "type": "felt"}, {"name": "calldata", "type": "felt*"}
+					]
+				},
+				"primaryType": "OutsideExecution",
+				"domain": {"name": "Account.execute_from_outside", "version": "1", "chainId": "SN_SEPOLIA"},
+				"message": {
+					"caller": "0x414e595f43414c4c4552", "nonce": "0x1", "execute_after": "0x0", "execute_before": "1900000000",
+					"calls_len": "0x1",
+					"calls": [{"to": "0x4dead", "selector": "` + selector + `", "calldata_len": "0x2", "calldata": ["0x7", "0x8"]}]
+				}
+			}`,
+			DomainType:         "StarkNetDomain",
+			ExpectedTypeHash:   "0x11ff76fe3f640fa6f3d60bbd94a3b9d47141a2c96f87fdcfbeb2af1d03f7050",
+			ExpectedDomainHash: "0x1bfc207425a47a5dfa1a50a4f5241203f50624ca5fdf5e18755765416b8e288",
+		},
+		{
+			Version: account.OutsideExecutionV2,
+			TypedData: `{
+				"types": {
+					"StarknetDomain": [
+						{"name": "name", "type": "shortstring"}, {"name": "version", "type": "shortstring"},
+						{"name": "chainId", "type": "shortstring"}, {"name": "revision", "type": "shortstring"}
+					],
+					"OutsideExecution": [
+						{"name": "Caller", "type": "ContractAddress"}, {"name": "Nonce", "type": "felt"},
+						{"name": "Execute After", "type": "u128"}, {"name": "Execute Before", "type": "u128"},
+						{"name": "Calls", "type": "Call*"}
+					],
+					"Call": [
+						{"name": "To", "type": "ContractAddress"}, {"name": "Selector", "type": "selector"},
+						{"name": "Calldata", "type": "felt*"}
+					]
+				},
+				"primaryType": "OutsideExecution",
+				"domain": {"name": "Account.execute_from_outside", "version": "2", "chainId": "SN_SEPOLIA", "revision": "1"},
+				"message": {
+					"Caller": "0x414e595f43414c4c4552", "Nonce": "0x1", "Execute After": "0x0", "Execute Before": "1900000000",
+					"Calls": [{"To": "0x4dead", "Selector": "` + selector + `", "Calldata": ["0x7", "0x8"]}]
+				}
+			}`,
+			DomainType:         "StarknetDomain",
+			ExpectedTypeHash:   "0x312b56c05a7965066ddbda31c016d8d05afc305071c0ca3cdc2192c3c2f1f0f",
+			ExpectedDomainHash: "0x1ff2f602e42168014d405a94f75e8a93d640751d71d16311266e140d8b0a210",
+		},
+	}
+	for _, test := range typedDataTestSet {
+		td, err := typed.NewTypedDataFromJSON([]byte(test.TypedData))
+		require.NoError(t, err)
+		require.Equal(t, test.ExpectedTypeHash, utils.BigToHex(td.Types["OutsideExecution"].Encoding))
+		require.Equal(t, test.ExpectedDomainHash, utils.BigToHex(td.Types[test.DomainType].Encoding))
+		expected, err := td.MessageHash(accountAddress)
+		require.NoError(t, err)
+		msgHash, err := oe.Hash(test.Version, accountAddress, acnt.ChainId)
+		require.NoError(t, err)
+		require.Equal(t, expected, msgHash)
+	}
+
+	_, err = oe.Hash(account.OutsideExecutionVersion(3), accountAddress, acnt.ChainId)
+	require.ErrorIs(t, err, account.ErrOutsideExecutionVersion)
+
+	supportsInterface := func(interfaceID *felt.Felt) rpc.FunctionCall {
+		return rpc.FunctionCall{
+			ContractAddress:    accountAddress,
+			EntryPointSelector: utils.GetSelectorFromNameFelt("supports_interface"),
+			Calldata:           []*felt.Felt{interfaceID},
+		}
+	}
+	mockRpcProvider.EXPECT().Call(context.Background(), supportsInterface(account.OutsideExecutionV1InterfaceID), rpc.BlockID{Tag: "pending"}).Return([]*felt.Felt{new(felt.Felt)}, nil)
+	mockRpcProvider.EXPECT().Call(context.Background(), supportsInterface(account.OutsideExecutionV2InterfaceID), rpc.BlockID{Tag: "pending"}).Return([]*felt.Felt{new(felt.Felt).SetUint64(1)}, nil)
+	supported, err := account.SupportsOutsideExecution(context.Background(), mockRpcProvider, accountAddress, account.OutsideExecutionV1)
+	require.NoError(t, err)
+	require.False(t, supported)
+	supported, err = account.SupportsOutsideExecution(context.Background(), mockRpcProvider, accountAddress, account.OutsideExecutionV2)
+	require.NoError(t, err)
+	require.True(t, supported)
+}
+
+// TestSessionPolicyMOCK tests that the session policies reject locally the calls which are not allowed,
+// the calls of expired sessions and the calls exceeding the spending limits.
+func TestSessionPolicyMOCK(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	game := utils.TestHexToFelt(t, "0x6a3e")
+	token := utils.TestHexToFelt(t, "0x707e")
+	moveSelector := utils.GetSelectorFromNameFelt("move")
+	transferSelector := utils.GetSelectorFromNameFelt("transfer")
+	now := time.Now()
+	policy := account.SessionPolicy{
+		AllowedMethods: []account.SessionMethod{
+			{ContractAddress: game, Selector: moveSelector},
+			{ContractAddress: token, Selector: transferSelector},
+		},
+		ExpiresAt:      uint64(now.Add(time.Hour).Unix()),
+		SpendingLimits: []account.SpendingLimit{{Token: token, Amount: big.NewInt(100)}},
+	}
+
+	move := rpc.FunctionCall{ContractAddress: game, EntryPointSelector: moveSelector, Calldata: []*felt.Felt{new(felt.Felt).SetUint64(3)}}
+	transfer := func(amount uint64) rpc.FunctionCall {
+		return rpc.FunctionCall{ContractAddress: token, EntryPointSelector: transferSelector, Calldata: []*felt.Felt{game, new(felt.Felt).SetUint64(amount), new(felt.Felt)}}
+	}
+	type testSetType struct {
+		Calls       []rpc.FunctionCall
+		Now         time.Time
+		Spent       map[felt.Felt]*big.Int
+		ExpectedErr error
+	}
+	testSet := []testSetType{
+		{Calls: []rpc.FunctionCall{move}, Now: now},
+		{Calls: []rpc.FunctionCall{move, transfer(60)}, Now: now},
+		{Calls: []rpc.FunctionCall{transfer(60), transfer(60)}, Now: now, ExpectedErr: account.ErrSpendingLimitExceeded},
+		{Calls: []rpc.FunctionCall{transfer(40)}, Now: now, Spent: map[felt.Felt]*big.Int{*token: big.NewInt(60)}},
+		{Calls: []rpc.FunctionCall{transfer(60)}, Now: now, Spent: map[felt.Felt]*big.Int{*token: big.NewInt(60)}, ExpectedErr: account.ErrSpendingLimitExceeded},
+		{Calls: []rpc.FunctionCall{{ContractAddress: game, EntryPointSelector: transferSelector, Calldata: []*felt.Felt{}}}, Now: now, ExpectedErr: account.ErrCallNotAllowed},
+		{Calls: []rpc.FunctionCall{{ContractAddress: token, EntryPointSelector: transferSelector, Calldata: []*felt.Felt{game}}}, Now: now, ExpectedErr: account.ErrSessionAmountMalformed},
+		{Calls: []rpc.FunctionCall{move}, Now: now.Add(2 * time.Hour), ExpectedErr: account.ErrSessionExpired},
+	}
+	for i, test := range testSet {
+		err := policy.Validate(test.Calls, test.Now, test.Spent)
+		if test.ExpectedErr == nil {
+			require.NoError(t, err, i)
+		} else {
+			require.ErrorIs(t, err, test.ExpectedErr, i)
+		}
+	}
+}
+
+// TestFileKeystoreMOCK tests the FileKeystore, creating, importing, unlocking and signing with encrypted JSON keystores.
+//
+// Parameters:
+//   - t: The testing.T object for running the test
+//
+// Returns:
+//
+//	none
+func TestFileKeystoreMOCK(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	ctx := context.Background()
+	ks, err := account.NewFileKeystore(t.TempDir(), account.LightScryptN, account.LightScryptP)
+	require.NoError(t, err)
+
+	publicKey, err := ks.NewKey("passphrase")
+	require.NoError(t, err)
+	keys, err := ks.List()
+	require.NoError(t, err)
+	require.Equal(t, []*felt.Felt{publicKey}, keys)
+
+	msgHash := big.NewInt(0x1234)
+	_, _, err = ks.Sign(ctx, publicKey.String(), msgHash)
+	require.ErrorIs(t, err, account.ErrKeyLocked)
+	_, _, err = ks.Sign(ctx, "0x1", msgHash)
+	require.ErrorIs(t, err, account.ErrSenderNoExist)
+	require.ErrorIs(t, ks.Unlock(publicKey.String(), "wrong"), account.ErrInvalidPassphrase)
+
+	require.NoError(t, ks.Unlock(publicKey.String(), "passphrase"))
+	r, s, err := ks.Sign(ctx, publicKey.String(), msgHash)
+	require.NoError(t, err)
+	pubX := utils.FeltToBigInt(publicKey)
+	pubY := curve.Curve.GetYCoordinate(pubX)
+	require.True(t, curve.Curve.Verify(msgHash, r, s, pubX, pubY))
+	ks.Lock(publicKey.String())
+	_, _, err = ks.Sign(ctx, publicKey.String(), msgHash)
+	require.ErrorIs(t, err, account.ErrKeyLocked)
+
+	privateKey := big.NewInt(0x5eed)
+	imported, err := ks.Import(privateKey, "other")
+	require.NoError(t, err)
+	_, err = ks.Import(privateKey, "other")
+	require.ErrorIs(t, err, account.ErrKeyAlreadyExists)
+	_, err = ks.Import(curve.Curve.N, "other")
+	require.ErrorIs(t, err, account.ErrInvalidPrivateKey)
+
+	// a keystore derived with pbkdf2, as created by other tools
+	keyJSON := []byte(`{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"6087dab2f9fdbbfaddc31a909735c1e6"},"ciphertext":"5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46","kdf":"pbkdf2","kdfparams":{"c":262144,"dklen":32,"prf":"hmac-sha256","salt":"ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"},"mac":"517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`)
+	_, err = account.DecryptKey(keyJSON, "wrong")
+	require.ErrorIs(t, err, account.ErrInvalidPassphrase)
+	// the MAC matches, but the key of the Web3 Secret Storage test vector is not a Stark key
+	_, err = account.DecryptKey(keyJSON, "testpassword")
+	require.ErrorIs(t, err, account.ErrInvalidPrivateKey)
+
+	// scrypt keystores in the format written by starkli, from the test vectors of go-ethereum, whose
+	// keys of 31 and 30 bytes are Stark keys. The public keys are computed independently of this package.
+	type testSetType struct {
+		KeyJSON           string
+		Passphrase        string
+		ExpectedKey       string
+		ExpectedPublicKey string
+	}
+	testSet := []testSetType{
+		{
+			KeyJSON:           `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"e0c41130a323adc1446fc82f724bca2f"},"ciphertext":"9517cd5bdbe69076f9bf5057248c6c050141e970efa36ce53692d5d59a3984","kdf":"scrypt","kdfparams":{"dklen":32,"n":2,"r":8,"p":1,"salt":"711f816911c92d649fb4c84b047915679933555030b3552c1212609b38208c63"},"mac":"d5e116151c6aa71470e67a7d42c9620c75c4d23229847dcc127794f0732b0db5"},"id":"fecfc4ce-e956-48fd-953b-30f8b52ed66c","version":3}`,
+			Passphrase:        "foo",
+			ExpectedKey:       "0xfa7b3db73dc7dfdf8c5fbdb796d741e4488628c41fc4febd9160a866ba0f35",
+			ExpectedPublicKey: "0x58aaa0d81070d79debb594edf587ef6e3dacaa66db3dcec42bdd60206d5fb7d",
+		},
+		{
+			KeyJSON:           `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"3ca92af36ad7c2cd92454c59cea5ef00"},"ciphertext":"108b7d34f3442fc26ab1ab90ca91476ba6bfa8c00975a49ef9051dc675aa","kdf":"scrypt","kdfparams":{"dklen":32,"n":2,"r":8,"p":1,"salt":"d0769e608fb86cda848065642a9c6fa046845c928175662b8e356c77f914cd3b"},"mac":"75d0e6759f7b3cefa319c3be41680ab6beea7d8328653474bd06706d4cc67420"},"id":"a37e1559-5955-450d-8075-7b8931b392b2","version":3}`,
+			Passphrase:        "foo",
+			ExpectedKey:       "0x81c29e8142bb6a81bef5a92bda7a8328a5c85bb2f9542e76f9b0f94fc018",
+			ExpectedPublicKey: "0x3116572f27138381e4d4019d91269f2b203d27eb15df6c81bed41ca15369642",
+		},
+	}
+	vectors, err := account.NewFileKeystore(t.TempDir(), account.LightScryptN, account.LightScryptP)
+	require.NoError(t, err)
+	for _, test := range testSet {
+		_, err := account.DecryptKey([]byte(test.KeyJSON), "wrong")
+		require.ErrorIs(t, err, account.ErrInvalidPassphrase)
+		key, err := account.DecryptKey([]byte(test.KeyJSON), test.Passphrase)
+		require.NoError(t, err)
+		require.Equal(t, test.ExpectedKey, utils.BigIntToFelt(key).String())
+
+		path := t.TempDir() + "/key.json"
+		require.NoError(t, os.WriteFile(path, []byte(test.KeyJSON), 0o600))
+		publicKey, err := vectors.ImportFile(path, test.Passphrase)
+		require.NoError(t, err)
+		require.Equal(t, test.ExpectedPublicKey, publicKey.String())
+	}
+
+	other, err := account.NewFileKeystore(t.TempDir(), account.LightScryptN, account.LightScryptP)
+	require.NoError(t, err)
+	keyJSON, err = account.EncryptKey(privateKey, "other", account.LightScryptN, account.LightScryptP)
+	require.NoError(t, err)
+	path := t.TempDir() + "/key.json"
+	require.NoError(t, os.WriteFile(path, keyJSON, 0o600))
+	_, err = other.ImportFile(path, "wrong")
+	require.ErrorIs(t, err, account.ErrInvalidPassphrase)
+	fromFile, err := other.ImportFile(path, "other")
+	require.NoError(t, err)
+	require.Equal(t, imported, fromFile)
+	require.NoError(t, other.Unlock(fromFile.String(), "other"))
+
+	server := rpctest.NewServer()
+	t.Cleanup(server.Close)
+	provider, err := server.Provider()
+	require.NoError(t, err)
+	acnt, err := account.NewAccount(provider, utils.TestHexToFelt(t, "0x1234"), fromFile.String(), other, 2)
+	require.NoError(t, err)
+	signature, err := acnt.Sign(ctx, utils.BigIntToFelt(msgHash))
+	require.NoError(t, err)
+	pubX, pubY, err = curve.Curve.PrivateToPoint(privateKey)
+	require.NoError(t, err)
+	require.True(t, curve.Curve.Verify(msgHash, utils.FeltToBigInt(signature[0]), utils.FeltToBigInt(signature[1]), pubX, pubY))
+}
+
+// keystoreFunc implements the Keystore interface with a function.
+type keystoreFunc func(ctx context.Context, id string, msgHash *big.Int) (*big.Int, *big.Int, error)
+
+func (f keystoreFunc) Sign(ctx context.Context, id string, msgHash *big.Int) (*big.Int, *big.Int, error) {
+	return f(ctx, id, msgHash)
+}
+
+// testCertificate creates a certificate for 127.0.0.1 signed by a parent, or a self-signed CA without parent.
+func testCertificate(t *testing.T, name string, parent *tls.Certificate) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	signerCert, signerKey := template, interface{}(key)
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		signerCert, signerKey = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signerCert, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+// TestRemoteKeystoreMOCK tests that a RemoteKeystore signs with the keys of a RemoteKeystoreServer over mutual TLS,
+// that the server only signs with the allowed keys and audits the signed hashes, and that the client rejects
+// the invalid signatures.
+//
+// Parameters:
+//   - t: The testing.T object for running the test
+//
+// Returns:
+//
+//	none
+func TestRemoteKeystoreMOCK(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	ctx := context.Background()
+	ks, pub, priv := account.GetRandomKeys()
+	_, otherPub, otherPriv := account.GetRandomKeys()
+	ks.Put(otherPub.String(), utils.FeltToBigInt(otherPriv))
+
+	var audit bytes.Buffer
+	handler, err := account.NewRemoteKeystoreServer(ks, []string{pub.String()}, log.New(&audit, "", 0))
+	require.NoError(t, err)
+	handler.Authorize = func(r *http.Request) error {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			return errors.New("invalid token")
+		}
+		return nil
+	}
+
+	ca := testCertificate(t, "ca", nil)
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Certificate[0]})
+	tlsConfig, err := account.RemoteKeystoreTLSConfig(testCertificate(t, "server", &ca), caPEM)
+	require.NoError(t, err)
+	server := httptest.NewUnstartedServer(handler)
+	server.TLS = tlsConfig
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Leaf)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:      roots,
+		Certificates: []tls.Certificate{testCertificate(t, "client", &ca)},
+		MinVersion:   tls.VersionTLS12,
+	}}}
+	headers := http.Header{"Authorization": []string{"Bearer secret"}}
+	remote := account.NewRemoteKeystore(server.URL, client, headers)
+
+	msgHash := big.NewInt(0xabc)
+	r, s, err := remote.Sign(ctx, pub.String(), msgHash)
+	require.NoError(t, err)
+	expectedR, expectedS, err := curve.Curve.Sign(msgHash, utils.FeltToBigInt(priv))
+	require.NoError(t, err)
+	require.Equal(t, expectedR, r)
+	require.Equal(t, expectedS, s)
+	require.Contains(t, audit.String(), fmt.Sprintf("signed hash 0xabc for key %s from \"CN=client\"", pub))
+
+	_, _, err = remote.Sign(ctx, otherPub.String(), msgHash)
+	require.ErrorIs(t, err, account.ErrKeyNotAllowed)
+	require.Contains(t, audit.String(), fmt.Sprintf("rejected hash 0xabc for key %s", otherPub))
+	_, _, err = account.NewRemoteKeystore(server.URL, client, nil).Sign(ctx, pub.String(), msgHash)
+	require.ErrorIs(t, err, account.ErrUnauthorized)
+
+	// without a client certificate, the TLS handshake fails
+	noCert := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}}}
+	_, _, err = account.NewRemoteKeystore(server.URL, noCert, headers).Sign(ctx, pub.String(), msgHash)
+	require.Error(t, err)
+
+	// the signatures of another key and the unknown keys are rejected
+	lying, err := account.NewRemoteKeystoreServer(keystoreFunc(func(ctx context.Context, _ string, msgHash *big.Int) (*big.Int, *big.Int, error) {
+		return ks.Sign(ctx, otherPub.String(), msgHash)
+	}), nil, log.New(io.Discard, "", 0))
+	require.NoError(t, err)
+	lyingServer := httptest.NewServer(lying)
+	t.Cleanup(lyingServer.Close)
+	_, _, err = account.NewRemoteKeystore(lyingServer.URL, nil, nil).Sign(ctx, pub.String(), msgHash)
+	require.ErrorIs(t, err, account.ErrInvalidRemoteSignature)
+
+	unknown, err := account.NewRemoteKeystoreServer(account.NewMemKeystore(), nil, log.New(io.Discard, "", 0))
+	require.NoError(t, err)
+	unknownServer := httptest.NewServer(unknown)
+	t.Cleanup(unknownServer.Close)
+	_, _, err = account.NewRemoteKeystore(unknownServer.URL, nil, nil).Sign(ctx, pub.String(), msgHash)
+	require.ErrorIs(t, err, account.ErrSenderNoExist)
+}
@@ -0,0 +1,187 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/hash"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// DeclareResult is the outcome of Account.Declare.
+type DeclareResult struct {
+	// TransactionHash the hash of the declare transaction, nil if the class was already declared
+	TransactionHash *felt.Felt
+	// ClassHash the hash of the Sierra class
+	ClassHash *felt.Felt
+	// FeeEstimate the estimate the maximum fee of the transaction was derived from, nil if the class was already declared
+	FeeEstimate *rpc.FeeEstimate
+}
+
+// Declare declares the Sierra class of a contract, unless it is already declared. It reads the
+// compiled classes, estimates the fee, sets the maximum fee from the estimate and the fee
+// multiplier, signs the transaction and submits it.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - sierraPath: the path of the Sierra class, such as "target/dev/project_Contract.contract_class.json"
+// - casmPath: the path of the CASM class, such as "target/dev/project_Contract.compiled_contract_class.json"
+// - opts: the options of the transaction, such as WithTxnVersion or WithFeeMultiplier
+// Returns:
+// - *DeclareResult: the class hash, and the hash of the transaction and the fee estimate if the class was declared
+// - error: an error if any
+func (account *Account) Declare(ctx context.Context, sierraPath, casmPath string, opts ...ExecuteOption) (*DeclareResult, error) {
+	options, err := newExecuteOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if options.version != "" && options.version != rpc.TransactionV2 && options.version != rpc.TransactionV3 {
+		return nil, ErrTxnVersionUnSupported
+	}
+
+	content, err := os.ReadFile(sierraPath)
+	if err != nil {
+		return nil, err
+	}
+	var class rpc.ContractClass
+	if err := json.Unmarshal(content, &class); err != nil {
+		return nil, err
+	}
+	classHash, err := hash.ClassHash(class)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = account.Class(ctx, rpc.BlockID{Tag: "pending"}, classHash)
+	if err == nil {
+		return &DeclareResult{ClassHash: classHash}, nil
+	}
+	var rpcErr *rpc.RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != rpc.ErrClassHashNotFound.Code {
+		return nil, err
+	}
+
+	casmClass, err := contracts.UnmarshalCasmClass(casmPath)
+	if err != nil {
+		return nil, err
+	}
+	compiledClassHash := hash.CompiledClassHash(*casmClass)
+
+	result := &DeclareResult{ClassHash: classHash}
+	err = account.submitWithNonce(ctx, options, func(ctx context.Context, nonce *felt.Felt) error {
+		var err error
+		if options.version == rpc.TransactionV3 {
+			txn := rpc.DeclareTxnV3{
+				Type:                  rpc.TransactionType_Declare,
+				Version:               rpc.TransactionV3,
+				SenderAddress:         account.AccountAddress,
+				Nonce:                 nonce,
+				ClassHash:             classHash,
+				CompiledClassHash:     compiledClassHash,
+				ResourceBounds:        account.zeroResourceBounds(ctx),
+				Tip:                   options.tip,
+				PayMasterData:         options.paymasterData,
+				AccountDeploymentData: []*felt.Felt{},
+				NonceDataMode:         options.nonceDAMode,
+				FeeMode:               options.feeDAMode,
+			}
+			result.TransactionHash, result.FeeEstimate, err = account.declareV3(ctx, txn, class, options.feeMultiplier)
+			return err
+		}
+		txn := rpc.DeclareTxnV2{
+			Type:              rpc.TransactionType_Declare,
+			Version:           rpc.TransactionV2,
+			SenderAddress:     account.AccountAddress,
+			Nonce:             nonce,
+			ClassHash:         classHash,
+			CompiledClassHash: compiledClassHash,
+			MaxFee:            new(felt.Felt),
+		}
+		result.TransactionHash, result.FeeEstimate, err = account.declareV2(ctx, txn, class, options.feeMultiplier)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// declareV2 estimates the fee of a V2 declare transaction, then signs and submits it.
+func (account *Account) declareV2(ctx context.Context, txn rpc.DeclareTxnV2, class rpc.ContractClass, feeMultiplier float64) (*felt.Felt, *rpc.FeeEstimate, error) {
+	broadcast := func(txn rpc.DeclareTxnV2) rpc.BroadcastDeclareTxnV2 {
+		return rpc.BroadcastDeclareTxnV2{
+			Type:              txn.Type,
+			SenderAddress:     txn.SenderAddress,
+			CompiledClassHash: txn.CompiledClassHash,
+			MaxFee:            txn.MaxFee,
+			Version:           txn.Version,
+			Signature:         txn.Signature,
+			Nonce:             txn.Nonce,
+			ContractClass:     class,
+		}
+	}
+
+	query := txn
+	query.Version = txn.Version.WithQueryBit()
+	if err := account.SignDeclareTransaction(ctx, &query); err != nil {
+		return nil, nil, err
+	}
+	estimate, err := account.estimateFee(ctx, broadcast(query))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	txn.MaxFee = multiplyFelt(estimate.OverallFee, feeMultiplier)
+	if err := account.SignDeclareTransaction(ctx, &txn); err != nil {
+		return nil, nil, err
+	}
+	resp, err := account.AddDeclareTransaction(ctx, broadcast(txn))
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.TransactionHash, &estimate, nil
+}
+
+// declareV3 estimates the fee of a V3 declare transaction, then signs and submits it.
+func (account *Account) declareV3(ctx context.Context, txn rpc.DeclareTxnV3, class rpc.ContractClass, feeMultiplier float64) (*felt.Felt, *rpc.FeeEstimate, error) {
+	query := txn
+	query.Version = txn.Version.WithQueryBit()
+	if err := account.signDeclareTransactionV3(ctx, &query); err != nil {
+		return nil, nil, err
+	}
+	estimate, err := account.estimateFee(ctx, rpc.BroadcastDeclareTxnV3{DeclareTxnV3: query, ContractClass: &class})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	txn.ResourceBounds, err = resourceBoundsFromEstimate(estimate, feeMultiplier, txn.ResourceBounds.L1DataGas != nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := account.signDeclareTransactionV3(ctx, &txn); err != nil {
+		return nil, nil, err
+	}
+	resp, err := account.AddDeclareTransaction(ctx, rpc.BroadcastDeclareTxnV3{DeclareTxnV3: txn, ContractClass: &class})
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.TransactionHash, &estimate, nil
+}
+
+// signDeclareTransactionV3 signs a V3 declare transaction.
+func (account *Account) signDeclareTransactionV3(ctx context.Context, txn *rpc.DeclareTxnV3) error {
+	txHash, err := account.TransactionHashDeclare(*txn)
+	if err != nil {
+		return err
+	}
+	signature, err := account.Sign(ctx, txHash)
+	if err != nil {
+		return err
+	}
+	txn.Signature = signature
+	return nil
+}
@@ -0,0 +1,424 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// AccountType is a flavor of account contract, with its own class and constructor.
+type AccountType int
+
+const (
+	// AccountOpenZeppelin is the OpenZeppelin account, constructed with the public key of its signer.
+	AccountOpenZeppelin AccountType = iota
+	// AccountArgent is the Argent account, constructed with the public key of its owner and no guardian.
+	AccountArgent
+	// AccountBraavos is the Braavos account. It is deployed with a base class constructed with
+	// the public key of its signer, which sets the implementation class given in the signature.
+	AccountBraavos
+)
+
+var (
+	// OpenZeppelinAccountClassHash is the class hash of the OpenZeppelin account v0.8.1.
+	OpenZeppelinAccountClassHash, _ = new(felt.Felt).SetString("0x061dac032f228abef9c6626f995015233097ae253a7f72d68552db02f2971b8f")
+	// ArgentAccountClassHash is the class hash of the Argent account v0.3.1.
+	ArgentAccountClassHash, _ = new(felt.Felt).SetString("0x029927c8af6bccf3f6fda035981e765a7bdbf18a2dc0d630494f8758aa908e2b")
+	// BraavosAccountClassHash is the class hash of the Braavos account v1.0.0, the implementation set by the base class.
+	BraavosAccountClassHash, _ = new(felt.Felt).SetString("0x00816dd0297efc55dc1e7559020a3a825e81ef734b558f03c83325d4da7e6253")
+	// BraavosBaseAccountClassHash is the class hash the Braavos accounts are deployed with.
+	BraavosBaseAccountClassHash, _ = new(felt.Felt).SetString("0x013bfe114fb1cf405bfc3a7f8dbe2d91db146c17521d40dcf57e16d6b59fa8e6")
+
+	// ETHTokenAddress is the address of the ETH token, paying the fee of the V1 transactions.
+	ETHTokenAddress, _ = new(felt.Felt).SetString("0x049d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7")
+	// STRKTokenAddress is the address of the STRK token, paying the fee of the V3 transactions.
+	STRKTokenAddress, _ = new(felt.Felt).SetString("0x04718f5a0fc34cc1af16a1cdee98ffb20c31f5cd61d6ab07201858f4287c938d")
+
+	ErrUnknownAccountType  = errors.New("unknown account type")
+	ErrTransactionReverted = errors.New("the transaction reverted")
+)
+
+// braavosDeploymentParams is the number of felts of the deployment parameters of the Braavos account
+// v1.0.0, between its implementation class and the chain id in the auxiliary data of its deploy signature.
+const braavosDeploymentParams = 9
+
+// String returns the name of the account type.
+func (t AccountType) String() string {
+	switch t {
+	case AccountOpenZeppelin:
+		return "OpenZeppelin"
+	case AccountArgent:
+		return "Argent"
+	case AccountBraavos:
+		return "Braavos"
+	}
+	return fmt.Sprintf("AccountType(%d)", int(t))
+}
+
+// ClassHash returns the class hash the accounts of this type are deployed with.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *felt.Felt: the class hash, the base class for Braavos
+// - error: ErrUnknownAccountType for an unknown type
+func (t AccountType) ClassHash() (*felt.Felt, error) {
+	switch t {
+	case AccountOpenZeppelin:
+		return OpenZeppelinAccountClassHash, nil
+	case AccountArgent:
+		return ArgentAccountClassHash, nil
+	case AccountBraavos:
+		return BraavosBaseAccountClassHash, nil
+	}
+	return nil, ErrUnknownAccountType
+}
+
+// ConstructorCalldata returns the arguments of the constructor of the accounts of this type.
+//
+// Parameters:
+// - publicKey: the Stark public key of the signer of the account
+// Returns:
+// - []*felt.Felt: the constructor calldata
+// - error: ErrUnknownAccountType for an unknown type
+func (t AccountType) ConstructorCalldata(publicKey *felt.Felt) ([]*felt.Felt, error) {
+	switch t {
+	case AccountOpenZeppelin, AccountBraavos:
+		return []*felt.Felt{publicKey}, nil
+	case AccountArgent:
+		// the owner, then the guardian which is disabled
+		return []*felt.Felt{publicKey, new(felt.Felt)}, nil
+	}
+	return nil, ErrUnknownAccountType
+}
+
+// Address computes the address of an account of this type.
+//
+// Parameters:
+// - publicKey: the Stark public key of the signer of the account
+// - salt: the salt of the deployment, the public key if nil
+// Returns:
+// - *felt.Felt: the address of the account
+// - error: an error if any
+func (t AccountType) Address(publicKey, salt *felt.Felt) (*felt.Felt, error) {
+	classHash, err := t.ClassHash()
+	if err != nil {
+		return nil, err
+	}
+	calldata, err := t.ConstructorCalldata(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	if salt == nil {
+		salt = publicKey
+	}
+	return contracts.PrecomputeAddress(&felt.Zero, salt, classHash, calldata)
+}
+
+// WithAccountClassHash sets the class of the account deployed by DeployAccount, instead of the
+// class of its AccountType. For Braavos, it sets the implementation class of the account.
+//
+// Parameters:
+// - classHash: the class hash of the account
+// Returns:
+// - a new instance of ExecuteOption
+func WithAccountClassHash(classHash *felt.Felt) ExecuteOption {
+	return newFuncExecuteOption(func(o *executeOptions) {
+		o.accountClassHash = classHash
+	})
+}
+
+// WithFundingWait makes DeployAccount wait until the address of the account holds enough of the
+// fee token to pay the maximum fee of the transaction before sending it, checking the balance at
+// each poll interval. The fee token is ETH for the V1 transactions and STRK for the V3 ones.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - a new instance of ExecuteOption
+func WithFundingWait() ExecuteOption {
+	return newFuncExecuteOption(func(o *executeOptions) {
+		o.waitForFunds = true
+	})
+}
+
+// DeployAccountResult is the outcome of DeployAccount.
+type DeployAccountResult struct {
+	// TransactionHash the hash of the deploy account transaction
+	TransactionHash *felt.Felt
+	// ContractAddress the address of the deployed account
+	ContractAddress *felt.Felt
+	// FeeEstimate the estimate the maximum fee of the transaction was derived from
+	FeeEstimate rpc.FeeEstimate
+	// Account the deployed account, ready to send transactions
+	Account *Account
+}
+
+// DeployAccount deploys an account of the given type. It computes the address of the account,
+// estimates the fee, sets the maximum fee from the estimate and the fee multiplier and signs
+// the transaction. It then optionally waits for the account to be funded, see WithFundingWait,
+// submits the transaction and waits for its receipt.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - provider: the provider sending the transaction
+// - accountType: the type of the account
+// - keystore: the keystore holding the private key of the account
+// - publicKey: the Stark public key of the account, identifying its private key in the keystore
+// - salt: the salt of the deployment, the public key if nil
+// - opts: the options of the transaction, such as WithTxnVersion, WithFundingWait or WithPollInterval
+// Returns:
+// - *DeployAccountResult: the hash of the transaction and the deployed account
+// - error: an error if any
+func DeployAccount(ctx context.Context, provider rpc.RpcProvider, accountType AccountType, keystore Keystore, publicKey, salt *felt.Felt, opts ...ExecuteOption) (*DeployAccountResult, error) {
+	options, err := newExecuteOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if options.version != "" && options.version != rpc.TransactionV1 && options.version != rpc.TransactionV3 {
+		return nil, ErrTxnVersionUnSupported
+	}
+	classHash, err := accountType.ClassHash()
+	if err != nil {
+		return nil, err
+	}
+	implementation := options.accountClassHash
+	if implementation == nil {
+		implementation = BraavosAccountClassHash
+	} else if accountType != AccountBraavos {
+		classHash = implementation
+	}
+	calldata, err := accountType.ConstructorCalldata(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	if salt == nil {
+		salt = publicKey
+	}
+	address, err := contracts.PrecomputeAddress(&felt.Zero, salt, classHash, calldata)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := NewAccount(provider, address, publicKey.String(), keystore, 2)
+	if err != nil {
+		return nil, err
+	}
+	deployer := &accountDeployer{account: account, accountType: accountType, implementation: implementation}
+
+	var result *DeployAccountResult
+	if options.version == rpc.TransactionV3 {
+		txn := rpc.DeployAccountTxnV3{
+			Type:                rpc.TransactionType_DeployAccount,
+			Version:             rpc.TransactionV3,
+			Nonce:               &felt.Zero,
+			ContractAddressSalt: salt,
+			ConstructorCalldata: calldata,
+			ClassHash:           classHash,
+			ResourceBounds:      account.zeroResourceBounds(ctx),
+			Tip:                 options.tip,
+			PayMasterData:       options.paymasterData,
+			NonceDataMode:       options.nonceDAMode,
+			FeeMode:             options.feeDAMode,
+		}
+		result, err = deployer.deployV3(ctx, txn, options)
+	} else {
+		txn := rpc.DeployAccountTxn{
+			Type:                rpc.TransactionType_DeployAccount,
+			Version:             rpc.TransactionV1,
+			Nonce:               &felt.Zero,
+			MaxFee:              new(felt.Felt),
+			ContractAddressSalt: salt,
+			ConstructorCalldata: calldata,
+			ClassHash:           classHash,
+		}
+		result, err = deployer.deployV1(ctx, txn, options)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	receipt, err := account.WaitForTransactionReceipt(ctx, result.TransactionHash, options.pollInterval)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.TransactionReceipt.GetExecutionStatus() == rpc.TxnExecutionStatusREVERTED {
+		return nil, ErrTransactionReverted
+	}
+	return result, nil
+}
+
+// accountDeployer signs and sends the deploy account transaction of an account type.
+type accountDeployer struct {
+	account        *Account
+	accountType    AccountType
+	implementation *felt.Felt
+}
+
+// deployV1 estimates the fee of a V1 deploy account transaction, then signs and submits it.
+func (d *accountDeployer) deployV1(ctx context.Context, txn rpc.DeployAccountTxn, options executeOptions) (*DeployAccountResult, error) {
+	query := txn
+	query.Version = txn.Version.WithQueryBit()
+	if err := d.sign(ctx, query, &query.Signature); err != nil {
+		return nil, err
+	}
+	estimate, err := d.account.estimateFee(ctx, rpc.BroadcastDeployAccountTxn{DeployAccountTxn: query})
+	if err != nil {
+		return nil, err
+	}
+
+	txn.MaxFee = multiplyFelt(estimate.OverallFee, options.feeMultiplier)
+	if err := d.sign(ctx, txn, &txn.Signature); err != nil {
+		return nil, err
+	}
+	if options.waitForFunds {
+		if err := d.waitForFunds(ctx, ETHTokenAddress, utils.FeltToBigInt(txn.MaxFee), options.pollInterval); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := d.account.AddDeployAccountTransaction(ctx, rpc.BroadcastDeployAccountTxn{DeployAccountTxn: txn})
+	if err != nil {
+		return nil, err
+	}
+	return &DeployAccountResult{TransactionHash: resp.TransactionHash, ContractAddress: d.account.AccountAddress, FeeEstimate: estimate, Account: d.account}, nil
+}
+
+// deployV3 estimates the fee of a V3 deploy account transaction, then signs and submits it.
+func (d *accountDeployer) deployV3(ctx context.Context, txn rpc.DeployAccountTxnV3, options executeOptions) (*DeployAccountResult, error) {
+	query := txn
+	query.Version = txn.Version.WithQueryBit()
+	if err := d.sign(ctx, query, &query.Signature); err != nil {
+		return nil, err
+	}
+	estimate, err := d.account.estimateFee(ctx, rpc.BroadcastDeployAccountTxnV3{DeployAccountTxnV3: query})
+	if err != nil {
+		return nil, err
+	}
+
+	txn.ResourceBounds, err = resourceBoundsFromEstimate(estimate, options.feeMultiplier, txn.ResourceBounds.L1DataGas != nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.sign(ctx, txn, &txn.Signature); err != nil {
+		return nil, err
+	}
+	if options.waitForFunds {
+		maxFee, err := maxFeeOfResourceBounds(txn.ResourceBounds)
+		if err != nil {
+			return nil, err
+		}
+		if err := d.waitForFunds(ctx, STRKTokenAddress, maxFee, options.pollInterval); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := d.account.AddDeployAccountTransaction(ctx, rpc.BroadcastDeployAccountTxnV3{DeployAccountTxnV3: txn})
+	if err != nil {
+		return nil, err
+	}
+	return &DeployAccountResult{TransactionHash: resp.TransactionHash, ContractAddress: d.account.AccountAddress, FeeEstimate: estimate, Account: d.account}, nil
+}
+
+// sign sets the signature of a deploy account transaction. The signature of a Braavos account is
+// the signature of the transaction hash, followed by the auxiliary data set by its base class and
+// the signature of the Poseidon hash of the auxiliary data: [r, s, aux..., aux_r, aux_s].
+func (d *accountDeployer) sign(ctx context.Context, txn rpc.DeployAccountType, signature *[]*felt.Felt) error {
+	txHash, err := d.account.TransactionHashDeployAccount(txn, d.account.AccountAddress)
+	if err != nil {
+		return err
+	}
+	sig, err := d.account.Sign(ctx, txHash)
+	if err != nil || d.accountType != AccountBraavos {
+		*signature = sig
+		return err
+	}
+
+	// the implementation class, the unset deployment parameters (the signer type, the secp256r1 public
+	// key as two u256, the multisig threshold, the withdrawal limit and its two fee rates), and the chain
+	// id preventing the replay of the auxiliary data on another chain
+	auxData := []*felt.Felt{d.implementation}
+	for i := 0; i < braavosDeploymentParams; i++ {
+		auxData = append(auxData, new(felt.Felt))
+	}
+	auxData = append(auxData, d.account.ChainId)
+	auxSig, err := d.account.Sign(ctx, crypto.PoseidonArray(auxData...))
+	if err != nil {
+		return err
+	}
+	*signature = append(append(sig, auxData...), auxSig...)
+	return nil
+}
+
+// waitForFunds waits until the account holds at least the given amount of a token.
+func (d *accountDeployer) waitForFunds(ctx context.Context, token *felt.Felt, amount *big.Int, pollInterval time.Duration) error {
+	t := time.NewTicker(pollInterval)
+	defer t.Stop()
+	for {
+		balance, err := d.balance(ctx, token)
+		if err != nil {
+			// the provider reports a request cancelled by the context as an internal error
+			if ctx.Err() != nil {
+				return fmt.Errorf("waiting for %s of token %s at %s: %w", amount, token, d.account.AccountAddress, ctx.Err())
+			}
+			return err
+		}
+		if balance.Cmp(amount) >= 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for %s of token %s at %s: %w", amount, token, d.account.AccountAddress, ctx.Err())
+		case <-t.C:
+		}
+	}
+}
+
+// balance reads the balance of the account in a token, an u256 made of its low and high 128 bits.
+func (d *accountDeployer) balance(ctx context.Context, token *felt.Felt) (*big.Int, error) {
+	result, err := d.account.Call(ctx, rpc.FunctionCall{
+		ContractAddress:    token,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("balanceOf"),
+		Calldata:           []*felt.Felt{d.account.AccountAddress},
+	}, rpc.BlockID{Tag: "pending"})
+	if err != nil {
+		return nil, err
+	}
+	if len(result) != 2 {
+		return nil, fmt.Errorf("expected an u256 balance, got %d felts", len(result))
+	}
+	balance := new(big.Int).Lsh(utils.FeltToBigInt(result[1]), 128)
+	return balance.Add(balance, utils.FeltToBigInt(result[0])), nil
+}
+
+// maxFeeOfResourceBounds returns the maximum fee paid for the given resource bounds, the sum of the maximum
+// amount times the maximum price of each resource.
+func maxFeeOfResourceBounds(mapping rpc.ResourceBoundsMapping) (*big.Int, error) {
+	resources := []rpc.ResourceBounds{mapping.L1Gas, mapping.L2Gas}
+	if mapping.L1DataGas != nil {
+		resources = append(resources, *mapping.L1DataGas)
+	}
+	maxFee := new(big.Int)
+	for _, bounds := range resources {
+		amount, ok := new(big.Int).SetString(string(bounds.MaxAmount), 0)
+		if !ok {
+			return nil, fmt.Errorf("invalid max amount %q", bounds.MaxAmount)
+		}
+		price, ok := new(big.Int).SetString(string(bounds.MaxPricePerUnit), 0)
+		if !ok {
+			return nil, fmt.Errorf("invalid max price per unit %q", bounds.MaxPricePerUnit)
+		}
+		maxFee.Add(maxFee, amount.Mul(amount, price))
+	}
+	return maxFee, nil
+}
@@ -0,0 +1,493 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+const (
+	// DefaultFeeMultiplier is the factor applied to the fee estimate of a transaction to get its maximum fee.
+	DefaultFeeMultiplier = 1.5
+	// DefaultPollInterval is the interval between the receipt requests of the methods waiting for a transaction.
+	DefaultPollInterval = 5 * time.Second
+)
+
+var ErrInvalidFeeMultiplier = errors.New("the fee multiplier must be at least 1")
+
+type executeOptions struct {
+	version       rpc.TransactionVersion
+	feeMultiplier float64
+	nonce         *felt.Felt
+	tip           rpc.U64
+	paymasterData []*felt.Felt
+	nonceDAMode   rpc.DataAvailabilityMode
+	feeDAMode     rpc.DataAvailabilityMode
+	pollInterval  time.Duration
+
+	accountClassHash *felt.Felt
+	waitForFunds     bool
+}
+
+// funcExecuteOption wraps a function that modifies executeOptions into an
+// implementation of the ExecuteOption interface.
+type funcExecuteOption struct {
+	f func(*executeOptions)
+}
+
+// apply applies the given execute options to the funcExecuteOption.
+//
+// Parameters:
+// - do: a pointer to executeOptions
+// Returns:
+//
+//	none
+func (feo *funcExecuteOption) apply(do *executeOptions) {
+	feo.f(do)
+}
+
+// newFuncExecuteOption returns a new instance of funcExecuteOption.
+//
+// Parameters:
+// - f: a function of type func(*executeOptions)
+// Returns:
+// - a pointer to funcExecuteOption
+func newFuncExecuteOption(f func(*executeOptions)) *funcExecuteOption {
+	return &funcExecuteOption{
+		f: f,
+	}
+}
+
+// ExecuteOption configures the transaction sent by Account.Execute.
+type ExecuteOption interface {
+	apply(*executeOptions)
+}
+
+// WithTxnVersion sets the version of the transaction: rpc.TransactionV3 to pay the fee in STRK.
+// By default, the invoke transactions are sent with rpc.TransactionV1 and the declare transactions
+// with rpc.TransactionV2.
+//
+// Parameters:
+// - version: the version of the transaction
+// Returns:
+// - a new instance of ExecuteOption
+func WithTxnVersion(version rpc.TransactionVersion) ExecuteOption {
+	return newFuncExecuteOption(func(o *executeOptions) {
+		o.version = version
+	})
+}
+
+// WithFeeMultiplier sets the factor applied to the fee estimate, DefaultFeeMultiplier by default.
+// It multiplies the max fee of the V1 transactions, and both the max amount and the max price
+// per unit of L1 gas of the V3 transactions.
+//
+// Parameters:
+// - multiplier: the factor, at least 1
+// Returns:
+// - a new instance of ExecuteOption
+func WithFeeMultiplier(multiplier float64) ExecuteOption {
+	return newFuncExecuteOption(func(o *executeOptions) {
+		o.feeMultiplier = multiplier
+	})
+}
+
+// WithNonce sets the nonce of the transaction, instead of reading it from the pending state of the account.
+//
+// Parameters:
+// - nonce: the nonce
+// Returns:
+// - a new instance of ExecuteOption
+func WithNonce(nonce *felt.Felt) ExecuteOption {
+	return newFuncExecuteOption(func(o *executeOptions) {
+		o.nonce = nonce
+	})
+}
+
+// WithTip sets the tip of a V3 transaction, zero by default.
+//
+// Parameters:
+// - tip: the tip
+// Returns:
+// - a new instance of ExecuteOption
+func WithTip(tip uint64) ExecuteOption {
+	return newFuncExecuteOption(func(o *executeOptions) {
+		o.tip = rpc.U64(fmt.Sprintf("0x%x", tip))
+	})
+}
+
+// WithPaymasterData sets the paymaster data of a V3 transaction, empty by default.
+//
+// Parameters:
+// - data: the paymaster data
+// Returns:
+// - a new instance of ExecuteOption
+func WithPaymasterData(data ...*felt.Felt) ExecuteOption {
+	return newFuncExecuteOption(func(o *executeOptions) {
+		o.paymasterData = data
+	})
+}
+
+// WithDataAvailabilityModes sets the data availability modes of a V3 transaction, rpc.DAModeL1 by default.
+//
+// Parameters:
+// - nonceMode: the storage domain of the nonce of the account
+// - feeMode: the storage domain of the balance paying the fee
+// Returns:
+// - a new instance of ExecuteOption
+func WithDataAvailabilityModes(nonceMode, feeMode rpc.DataAvailabilityMode) ExecuteOption {
+	return newFuncExecuteOption(func(o *executeOptions) {
+		o.nonceDAMode = nonceMode
+		o.feeDAMode = feeMode
+	})
+}
+
+// WithPollInterval sets the interval between the receipt requests of the methods waiting for
+// their transaction, such as DeployViaUDC, DefaultPollInterval by default.
+//
+// Parameters:
+// - interval: the interval between the receipt requests
+// Returns:
+// - a new instance of ExecuteOption
+func WithPollInterval(interval time.Duration) ExecuteOption {
+	return newFuncExecuteOption(func(o *executeOptions) {
+		o.pollInterval = interval
+	})
+}
+
+// newExecuteOptions applies the given options to the default ones.
+func newExecuteOptions(opts []ExecuteOption) (executeOptions, error) {
+	options := executeOptions{
+		feeMultiplier: DefaultFeeMultiplier,
+		tip:           "0x0",
+		paymasterData: []*felt.Felt{},
+		nonceDAMode:   rpc.DAModeL1,
+		feeDAMode:     rpc.DAModeL1,
+		pollInterval:  DefaultPollInterval,
+	}
+	for _, opt := range opts {
+		opt.apply(&options)
+	}
+	if options.feeMultiplier < 1 {
+		return options, ErrInvalidFeeMultiplier
+	}
+	return options, nil
+}
+
+// ExecuteResult is the outcome of Account.Execute.
+type ExecuteResult struct {
+	// TransactionHash the hash of the submitted transaction
+	TransactionHash *felt.Felt
+	// FeeEstimate the estimate the maximum fee of the transaction was derived from
+	FeeEstimate rpc.FeeEstimate
+}
+
+// Execute sends an invoke transaction executing the given calls from the account. It reads the nonce,
+// formats the calldata, estimates the fee, sets the maximum fee from the estimate and the fee
+// multiplier, signs the transaction and submits it. The nonce is allocated by the nonce manager
+// of the account if it has one, see SetNonceManager.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - calls: the calls executed by the transaction
+// - opts: the options of the transaction, such as WithTxnVersion or WithFeeMultiplier
+// Returns:
+// - *ExecuteResult: the hash of the transaction and the fee estimate that was used
+// - error: an error if any
+func (account *Account) Execute(ctx context.Context, calls []rpc.FunctionCall, opts ...ExecuteOption) (*ExecuteResult, error) {
+	options, err := newExecuteOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	calldata, err := account.FmtCalldata(calls)
+	if err != nil {
+		return nil, err
+	}
+	if options.version != "" && options.version != rpc.TransactionV1 && options.version != rpc.TransactionV3 {
+		return nil, ErrTxnVersionUnSupported
+	}
+
+	var result *ExecuteResult
+	err = account.submitWithNonce(ctx, options, func(ctx context.Context, nonce *felt.Felt) error {
+		var err error
+		result, err = account.execute(ctx, calldata, nonce, options)
+		return err
+	})
+	return result, err
+}
+
+// submitWithNonce sends a transaction with the nonce of the options if any, else with a nonce
+// allocated by the nonce manager of the account, else with the nonce of the pending state.
+func (account *Account) submitWithNonce(ctx context.Context, options executeOptions, send func(ctx context.Context, nonce *felt.Felt) error) error {
+	if options.nonce != nil {
+		return send(ctx, options.nonce)
+	}
+	if account.nonceManager != nil {
+		return account.nonceManager.Submit(ctx, send)
+	}
+	nonce, err := account.Nonce(ctx, rpc.BlockID{Tag: "pending"}, account.AccountAddress)
+	if err != nil {
+		return err
+	}
+	return send(ctx, nonce)
+}
+
+// EstimateFeeForCalls estimates the fee of an invoke transaction executing the given calls from the
+// account, without sending it. The estimated transaction is signed with the query version of the
+// transaction version, such as rpc.TransactionV1WithQueryBit, so that it cannot be sent by the node.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - calls: the calls executed by the transaction
+// - opts: the options of the transaction, such as WithTxnVersion or WithNonce
+// Returns:
+// - *rpc.FeeEstimate: the fee estimate of the transaction
+// - error: an error if any
+func (account *Account) EstimateFeeForCalls(ctx context.Context, calls []rpc.FunctionCall, opts ...ExecuteOption) (*rpc.FeeEstimate, error) {
+	options, err := newExecuteOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	calldata, err := account.FmtCalldata(calls)
+	if err != nil {
+		return nil, err
+	}
+	if options.version != "" && options.version != rpc.TransactionV1 && options.version != rpc.TransactionV3 {
+		return nil, ErrTxnVersionUnSupported
+	}
+
+	nonce := options.nonce
+	if nonce == nil {
+		nonce, err = account.Nonce(ctx, rpc.BlockID{Tag: "pending"}, account.AccountAddress)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var estimate rpc.FeeEstimate
+	if options.version == rpc.TransactionV3 {
+		estimate, err = account.estimateInvokeV3(ctx, account.invokeTxnV3(ctx, calldata, nonce, options))
+	} else {
+		estimate, err = account.estimateInvokeV1(ctx, account.invokeTxnV1(calldata, nonce))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &estimate, nil
+}
+
+// execute builds, estimates, signs and submits an invoke transaction with the given nonce.
+func (account *Account) execute(ctx context.Context, calldata []*felt.Felt, nonce *felt.Felt, options executeOptions) (*ExecuteResult, error) {
+	if options.version == rpc.TransactionV3 {
+		return account.executeV3(ctx, account.invokeTxnV3(ctx, calldata, nonce, options), options.feeMultiplier)
+	}
+	return account.executeV1(ctx, account.invokeTxnV1(calldata, nonce), options.feeMultiplier)
+}
+
+// invokeTxnV1 builds an unsigned V1 invoke transaction with a zero max fee.
+func (account *Account) invokeTxnV1(calldata []*felt.Felt, nonce *felt.Felt) rpc.InvokeTxnV1 {
+	return rpc.InvokeTxnV1{
+		Type:          rpc.TransactionType_Invoke,
+		Version:       rpc.TransactionV1,
+		SenderAddress: account.AccountAddress,
+		Nonce:         nonce,
+		MaxFee:        new(felt.Felt),
+		Calldata:      calldata,
+	}
+}
+
+// invokeTxnV3 builds an unsigned V3 invoke transaction with zero resource bounds.
+func (account *Account) invokeTxnV3(ctx context.Context, calldata []*felt.Felt, nonce *felt.Felt, options executeOptions) rpc.InvokeTxnV3 {
+	return rpc.InvokeTxnV3{
+		Type:                  rpc.TransactionType_Invoke,
+		Version:               rpc.TransactionV3,
+		SenderAddress:         account.AccountAddress,
+		Nonce:                 nonce,
+		Calldata:              calldata,
+		ResourceBounds:        account.zeroResourceBounds(ctx),
+		Tip:                   options.tip,
+		PayMasterData:         options.paymasterData,
+		AccountDeploymentData: []*felt.Felt{},
+		NonceDataMode:         options.nonceDAMode,
+		FeeMode:               options.feeDAMode,
+	}
+}
+
+// executeV1 estimates the fee of a V1 invoke transaction, then signs and submits it.
+func (account *Account) executeV1(ctx context.Context, txn rpc.InvokeTxnV1, feeMultiplier float64) (*ExecuteResult, error) {
+	estimate, err := account.estimateInvokeV1(ctx, txn)
+	if err != nil {
+		return nil, err
+	}
+
+	txn.MaxFee = multiplyFelt(estimate.OverallFee, feeMultiplier)
+	if err := account.SignInvokeTransaction(ctx, &txn); err != nil {
+		return nil, err
+	}
+	resp, err := account.AddInvokeTransaction(ctx, rpc.BroadcastInvokev1Txn{InvokeTxnV1: txn})
+	if err != nil {
+		return nil, err
+	}
+	return &ExecuteResult{TransactionHash: resp.TransactionHash, FeeEstimate: estimate}, nil
+}
+
+// executeV3 estimates the fee of a V3 invoke transaction, then signs and submits it.
+func (account *Account) executeV3(ctx context.Context, txn rpc.InvokeTxnV3, feeMultiplier float64) (*ExecuteResult, error) {
+	estimate, err := account.estimateInvokeV3(ctx, txn)
+	if err != nil {
+		return nil, err
+	}
+
+	txn.ResourceBounds, err = resourceBoundsFromEstimate(estimate, feeMultiplier, txn.ResourceBounds.L1DataGas != nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := account.signInvokeTransactionV3(ctx, &txn); err != nil {
+		return nil, err
+	}
+	resp, err := account.AddInvokeTransaction(ctx, rpc.BroadcastInvokev3Txn{InvokeTxnV3: txn})
+	if err != nil {
+		return nil, err
+	}
+	return &ExecuteResult{TransactionHash: resp.TransactionHash, FeeEstimate: estimate}, nil
+}
+
+// estimateInvokeV1 signs a V1 invoke transaction with the query version and estimates its fee.
+func (account *Account) estimateInvokeV1(ctx context.Context, txn rpc.InvokeTxnV1) (rpc.FeeEstimate, error) {
+	txn.Version = txn.Version.WithQueryBit()
+	if err := account.SignInvokeTransaction(ctx, &txn); err != nil {
+		return rpc.FeeEstimate{}, err
+	}
+	return account.estimateFee(ctx, rpc.BroadcastInvokev1Txn{InvokeTxnV1: txn})
+}
+
+// estimateInvokeV3 signs a V3 invoke transaction with the query version and estimates its fee.
+func (account *Account) estimateInvokeV3(ctx context.Context, txn rpc.InvokeTxnV3) (rpc.FeeEstimate, error) {
+	txn.Version = txn.Version.WithQueryBit()
+	if err := account.signInvokeTransactionV3(ctx, &txn); err != nil {
+		return rpc.FeeEstimate{}, err
+	}
+	return account.estimateFee(ctx, rpc.BroadcastInvokev3Txn{InvokeTxnV3: txn})
+}
+
+// signInvokeTransactionV3 signs a V3 invoke transaction.
+func (account *Account) signInvokeTransactionV3(ctx context.Context, txn *rpc.InvokeTxnV3) error {
+	txHash, err := account.TransactionHashInvoke(*txn)
+	if err != nil {
+		return err
+	}
+	signature, err := account.Sign(ctx, txHash)
+	if err != nil {
+		return err
+	}
+	txn.Signature = signature
+	return nil
+}
+
+// estimateFee estimates the fee of a signed transaction against the pending state.
+func (account *Account) estimateFee(ctx context.Context, txn rpc.BroadcastTxn) (rpc.FeeEstimate, error) {
+	estimates, err := account.EstimateFee(ctx, []rpc.BroadcastTxn{txn}, []rpc.SimulationFlag{}, rpc.BlockID{Tag: "pending"})
+	if err != nil {
+		return rpc.FeeEstimate{}, err
+	}
+	if len(estimates) != 1 {
+		return rpc.FeeEstimate{}, fmt.Errorf("expected 1 fee estimate, got %d", len(estimates))
+	}
+	return estimates[0], nil
+}
+
+// zeroResourceBounds returns the resource bounds of a V3 transaction whose fee is being estimated, with
+// the bounds of the L1 data gas when the node of the account requires them.
+func (account *Account) zeroResourceBounds(ctx context.Context) rpc.ResourceBoundsMapping {
+	return zeroResourceBounds(rpc.CapabilitiesOf(ctx, account.provider).RequiresL1DataGas())
+}
+
+// zeroResourceBounds returns zero resource bounds, with zero bounds of the L1 data gas if withL1DataGas is true.
+func zeroResourceBounds(withL1DataGas bool) rpc.ResourceBoundsMapping {
+	zero := rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"}
+	bounds := rpc.ResourceBoundsMapping{L1Gas: zero, L2Gas: zero}
+	if withL1DataGas {
+		bounds.L1DataGas = &rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"}
+	}
+	return bounds
+}
+
+// resourceBoundsFromEstimate returns the resource bounds of a V3 transaction from its fee estimate.
+// Without bounds of the L1 data gas, the whole fee is expressed in L1 gas at the estimated gas price.
+// With them, each resource is bounded by its own estimated consumption and price. The amounts and the
+// prices are multiplied by the fee multiplier.
+//
+// Parameters:
+// - estimate: the fee estimate of the transaction
+// - feeMultiplier: the factor applied to the amounts and the prices
+// - withL1DataGas: whether the transaction declares the bounds of the L1 data gas
+// Returns:
+// - rpc.ResourceBoundsMapping: the resource bounds
+// - error: an error if the estimated gas price is zero or the bounds overflow
+func resourceBoundsFromEstimate(estimate rpc.FeeEstimate, feeMultiplier float64, withL1DataGas bool) (rpc.ResourceBoundsMapping, error) {
+	if estimate.GasPrice == nil || estimate.GasPrice.IsZero() || estimate.OverallFee == nil {
+		return rpc.ResourceBoundsMapping{}, errors.New("the fee estimate has no gas price")
+	}
+	bounds := zeroResourceBounds(withL1DataGas)
+	var err error
+	if !withL1DataGas {
+		gasPrice := estimate.GasPrice.BigInt(new(big.Int))
+		amount, remainder := new(big.Int).QuoRem(estimate.OverallFee.BigInt(new(big.Int)), gasPrice, new(big.Int))
+		if remainder.Sign() != 0 {
+			amount.Add(amount, big.NewInt(1))
+		}
+		bounds.L1Gas, err = resourceBounds(amount, gasPrice, feeMultiplier)
+		return bounds, err
+	}
+
+	if bounds.L1Gas, err = resourceBounds(bigOrZero(estimate.GasConsumed), bigOrZero(estimate.GasPrice), feeMultiplier); err != nil {
+		return rpc.ResourceBoundsMapping{}, err
+	}
+	if *bounds.L1DataGas, err = resourceBounds(bigOrZero(estimate.DataGasConsumed), bigOrZero(estimate.DataGasPrice), feeMultiplier); err != nil {
+		return rpc.ResourceBoundsMapping{}, err
+	}
+	if bounds.L2Gas, err = resourceBounds(bigOrZero(estimate.L2GasConsumed), bigOrZero(estimate.L2GasPrice), feeMultiplier); err != nil {
+		return rpc.ResourceBoundsMapping{}, err
+	}
+	return bounds, nil
+}
+
+// resourceBounds returns the bounds of a resource, its amount and its price multiplied by the fee multiplier.
+func resourceBounds(amount, price *big.Int, feeMultiplier float64) (rpc.ResourceBounds, error) {
+	maxAmount := multiplyInt(amount, feeMultiplier)
+	maxPrice := multiplyInt(price, feeMultiplier)
+	if !maxAmount.IsUint64() || maxPrice.BitLen() > 128 {
+		return rpc.ResourceBounds{}, errors.New("the fee estimate exceeds the resource bounds")
+	}
+	return rpc.ResourceBounds{
+		MaxAmount:       rpc.U64(fmt.Sprintf("0x%x", maxAmount)),
+		MaxPricePerUnit: rpc.U128(fmt.Sprintf("0x%x", maxPrice)),
+	}, nil
+}
+
+// bigOrZero converts a felt of a fee estimate to an integer, 0 for nil.
+func bigOrZero(value *felt.Felt) *big.Int {
+	if value == nil {
+		return new(big.Int)
+	}
+	return value.BigInt(new(big.Int))
+}
+
+// multiplyInt multiplies an integer by a factor, rounding down.
+func multiplyInt(value *big.Int, factor float64) *big.Int {
+	product := new(big.Float).Mul(new(big.Float).SetInt(value), big.NewFloat(factor))
+	result, _ := product.Int(nil)
+	return result
+}
+
+// multiplyFelt multiplies a felt by a factor, rounding down.
+func multiplyFelt(value *felt.Felt, factor float64) *felt.Felt {
+	return utils.BigIntToFelt(multiplyInt(value.BigInt(new(big.Int)), factor))
+}
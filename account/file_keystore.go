@@ -0,0 +1,465 @@
+package account
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/utils"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// StandardScryptN and StandardScryptP are the scrypt parameters of the keys created by starkli.
+	StandardScryptN = 1 << 13
+	StandardScryptP = 1
+	// LightScryptN and LightScryptP are scrypt parameters using less memory and time, at the expense of security.
+	LightScryptN = 1 << 12
+	LightScryptP = 1
+
+	keystoreVersion = 3
+	keystoreCipher  = "aes-128-ctr"
+	scryptR         = 8
+	scryptDKLen     = 32
+)
+
+var (
+	ErrInvalidPassphrase   = errors.New("the passphrase does not decrypt the key")
+	ErrKeyLocked           = errors.New("the key is locked")
+	ErrKeyAlreadyExists    = errors.New("the key is already in the keystore")
+	ErrUnsupportedKeystore = errors.New("unsupported keystore format")
+	ErrInvalidPrivateKey   = errors.New("the private key is not a valid Stark key")
+)
+
+// encryptedKey is the JSON encoding of an encrypted key, in the version 3 of the Web3 Secret Storage.
+// https://ethereum.org/en/developers/docs/data-structures-and-encoding/web3-secret-storage/
+type encryptedKey struct {
+	Crypto  cryptoParams `json:"crypto"`
+	ID      string       `json:"id"`
+	Version int          `json:"version"`
+}
+
+type cryptoParams struct {
+	Cipher       string       `json:"cipher"`
+	CipherText   string       `json:"ciphertext"`
+	CipherParams cipherParams `json:"cipherparams"`
+	KDF          string       `json:"kdf"`
+	KDFParams    kdfParams    `json:"kdfparams"`
+	MAC          string       `json:"mac"`
+}
+
+type cipherParams struct {
+	IV string `json:"iv"`
+}
+
+// kdfParams holds the parameters of both the scrypt and the pbkdf2 key derivation functions.
+type kdfParams struct {
+	DKLen int    `json:"dklen"`
+	Salt  string `json:"salt"`
+	N     int    `json:"n,omitempty"`
+	R     int    `json:"r,omitempty"`
+	P     int    `json:"p,omitempty"`
+	C     int    `json:"c,omitempty"`
+	PRF   string `json:"prf,omitempty"`
+}
+
+// EncryptKey encrypts a Stark private key with a passphrase, returning a JSON keystore in the
+// Web3 Secret Storage format used by starkli and Argent, with the scrypt key derivation function.
+//
+// Parameters:
+// - privateKey: the private key
+// - passphrase: the passphrase
+// - scryptN: the CPU and memory cost of scrypt, such as StandardScryptN
+// - scryptP: the parallelization of scrypt, such as StandardScryptP
+// Returns:
+// - []byte: the JSON keystore
+// - error: ErrInvalidPrivateKey if the key is not in the range of the Stark keys, or an error if any
+func EncryptKey(privateKey *big.Int, passphrase string, scryptN, scryptP int) ([]byte, error) {
+	if !validPrivateKey(privateKey) {
+		return nil, ErrInvalidPrivateKey
+	}
+	salt := make([]byte, 32)
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+	derivedKey, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, scryptDKLen)
+	if err != nil {
+		return nil, err
+	}
+	cipherText, err := aesCTR(derivedKey[:16], iv, privateKey.FillBytes(make([]byte, 32)))
+	if err != nil {
+		return nil, err
+	}
+	id, err := newUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(encryptedKey{
+		Crypto: cryptoParams{
+			Cipher:       keystoreCipher,
+			CipherText:   hex.EncodeToString(cipherText),
+			CipherParams: cipherParams{IV: hex.EncodeToString(iv)},
+			KDF:          "scrypt",
+			KDFParams: kdfParams{
+				DKLen: scryptDKLen,
+				Salt:  hex.EncodeToString(salt),
+				N:     scryptN,
+				R:     scryptR,
+				P:     scryptP,
+			},
+			MAC: hex.EncodeToString(ethcrypto.Keccak256(derivedKey[16:32], cipherText)),
+		},
+		ID:      id,
+		Version: keystoreVersion,
+	})
+}
+
+// DecryptKey decrypts a JSON keystore in the Web3 Secret Storage format, such as a keystore
+// created by starkli or Argent, with either the scrypt or the pbkdf2 key derivation function.
+//
+// Parameters:
+// - keyJSON: the JSON keystore
+// - passphrase: the passphrase
+// Returns:
+// - *big.Int: the private key
+// - error: ErrInvalidPassphrase if the MAC of the keystore does not match, or an error if any
+func DecryptKey(keyJSON []byte, passphrase string) (*big.Int, error) {
+	var key encryptedKey
+	if err := json.Unmarshal(keyJSON, &key); err != nil {
+		return nil, err
+	}
+	if key.Version != keystoreVersion || key.Crypto.Cipher != keystoreCipher {
+		return nil, fmt.Errorf("%w: version %d, cipher %q", ErrUnsupportedKeystore, key.Version, key.Crypto.Cipher)
+	}
+
+	derivedKey, err := key.Crypto.derivedKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	cipherText, err := decodeHex(key.Crypto.CipherText)
+	if err != nil {
+		return nil, err
+	}
+	mac, err := decodeHex(key.Crypto.MAC)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(ethcrypto.Keccak256(derivedKey[16:32], cipherText), mac) {
+		return nil, ErrInvalidPassphrase
+	}
+	iv, err := decodeHex(key.Crypto.CipherParams.IV)
+	if err != nil {
+		return nil, err
+	}
+	plainText, err := aesCTR(derivedKey[:16], iv, cipherText)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey := new(big.Int).SetBytes(plainText)
+	if !validPrivateKey(privateKey) {
+		return nil, ErrInvalidPrivateKey
+	}
+	return privateKey, nil
+}
+
+// derivedKey derives the key encrypting the private key from the passphrase.
+func (c cryptoParams) derivedKey(passphrase string) ([]byte, error) {
+	params := c.KDFParams
+	salt, err := decodeHex(params.Salt)
+	if err != nil {
+		return nil, err
+	}
+	if params.DKLen < 32 {
+		return nil, fmt.Errorf("%w: derived key length %d", ErrUnsupportedKeystore, params.DKLen)
+	}
+
+	switch c.KDF {
+	case "scrypt":
+		return scrypt.Key([]byte(passphrase), salt, params.N, params.R, params.P, params.DKLen)
+	case "pbkdf2":
+		if params.PRF != "hmac-sha256" {
+			return nil, fmt.Errorf("%w: pbkdf2 function %q", ErrUnsupportedKeystore, params.PRF)
+		}
+		return pbkdf2.Key([]byte(passphrase), salt, params.C, params.DKLen, sha256.New), nil
+	}
+	return nil, fmt.Errorf("%w: key derivation function %q", ErrUnsupportedKeystore, c.KDF)
+}
+
+// FileKeystore implements the Keystore interface with the keys stored in a directory, each of them
+// in a JSON keystore encrypted with a passphrase, see EncryptKey. The keys are named after their
+// public key, and must be unlocked with their passphrase before signing.
+type FileKeystore struct {
+	mu       sync.Mutex
+	dir      string
+	scryptN  int
+	scryptP  int
+	unlocked map[string]*big.Int
+}
+
+// NewFileKeystore creates a FileKeystore, creating its directory if needed.
+//
+// Parameters:
+// - dir: the directory of the keys
+// - scryptN: the CPU and memory cost of scrypt for the new keys, such as StandardScryptN
+// - scryptP: the parallelization of scrypt for the new keys, such as StandardScryptP
+// Returns:
+// - *FileKeystore: the keystore
+// - error: an error if the directory cannot be created
+func NewFileKeystore(dir string, scryptN, scryptP int) (*FileKeystore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileKeystore{
+		dir:      dir,
+		scryptN:  scryptN,
+		scryptP:  scryptP,
+		unlocked: make(map[string]*big.Int),
+	}, nil
+}
+
+// NewKey creates a random private key and stores it in the keystore, locked.
+//
+// Parameters:
+// - passphrase: the passphrase encrypting the key
+// Returns:
+// - *felt.Felt: the public key, identifying the key in the keystore
+// - error: an error if any
+func (ks *FileKeystore) NewKey(passphrase string) (*felt.Felt, error) {
+	privateKey, err := curve.Curve.GetRandomPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	return ks.Import(privateKey, passphrase)
+}
+
+// Import stores a private key in the keystore, locked.
+//
+// Parameters:
+// - privateKey: the private key
+// - passphrase: the passphrase encrypting the key
+// Returns:
+// - *felt.Felt: the public key, identifying the key in the keystore
+// - error: ErrKeyAlreadyExists if the keystore holds the key, or an error if any
+func (ks *FileKeystore) Import(privateKey *big.Int, passphrase string) (*felt.Felt, error) {
+	keyJSON, err := EncryptKey(privateKey, passphrase, ks.scryptN, ks.scryptP)
+	if err != nil {
+		return nil, err
+	}
+	return ks.store(privateKey, keyJSON)
+}
+
+// ImportFile stores a JSON keystore in the keystore as is, such as the keystore of a starkli signer.
+// The passphrase is only used to check the keystore and get its public key, the key stays locked.
+//
+// Parameters:
+// - path: the path of the JSON keystore
+// - passphrase: the passphrase of the JSON keystore
+// Returns:
+// - *felt.Felt: the public key, identifying the key in the keystore
+// - error: ErrInvalidPassphrase for a wrong passphrase, or an error if any
+func (ks *FileKeystore) ImportFile(path, passphrase string) (*felt.Felt, error) {
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return ks.store(privateKey, keyJSON)
+}
+
+// store writes the JSON keystore of a private key in the directory of the keystore.
+func (ks *FileKeystore) store(privateKey *big.Int, keyJSON []byte) (*felt.Felt, error) {
+	pubX, _, err := curve.Curve.PrivateToPoint(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	publicKey := utils.BigIntToFelt(pubX)
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	path := ks.keyPath(publicKey)
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrKeyAlreadyExists, publicKey)
+	}
+	// write then rename, so that the keystore never holds a partial key
+	tmp, err := os.CreateTemp(ks.dir, ".tmp-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(keyJSON); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return nil, err
+	}
+	return publicKey, nil
+}
+
+// List returns the public keys of the keys stored in the keystore, locked or not.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []*felt.Felt: the public keys
+// - error: an error if the directory cannot be read
+func (ks *FileKeystore) List() ([]*felt.Felt, error) {
+	entries, err := os.ReadDir(ks.dir)
+	if err != nil {
+		return nil, err
+	}
+	publicKeys := []*felt.Felt{}
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if entry.IsDir() || !ok {
+			continue
+		}
+		publicKey, err := new(felt.Felt).SetString(name)
+		if err != nil {
+			continue
+		}
+		publicKeys = append(publicKeys, publicKey)
+	}
+	return publicKeys, nil
+}
+
+// Unlock decrypts a key with its passphrase, so that the keystore can sign with it.
+//
+// Parameters:
+// - publicKey: the public key of the key
+// - passphrase: the passphrase of the key
+// Returns:
+// - error: ErrSenderNoExist for an unknown key, ErrInvalidPassphrase for a wrong passphrase, or an error if any
+func (ks *FileKeystore) Unlock(publicKey, passphrase string) error {
+	pub, err := new(felt.Felt).SetString(publicKey)
+	if err != nil {
+		return err
+	}
+	keyJSON, err := os.ReadFile(ks.keyPath(pub))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error getting key for sender %s: %w", publicKey, ErrSenderNoExist)
+	}
+	if err != nil {
+		return err
+	}
+	privateKey, err := DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return err
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.unlocked[pub.String()] = privateKey
+	return nil
+}
+
+// Lock removes a decrypted key from the memory, until it is unlocked again.
+//
+// Parameters:
+// - publicKey: the public key of the key
+func (ks *FileKeystore) Lock(publicKey string) {
+	pub, err := new(felt.Felt).SetString(publicKey)
+	if err != nil {
+		return
+	}
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	delete(ks.unlocked, pub.String())
+}
+
+// Sign signs a message hash with an unlocked key.
+//
+// Parameters:
+// - ctx: the context of the operation.
+// - id: the public key of the key.
+// - msgHash: is the message hash to be signed.
+// Returns:
+// - *big.Int: the R component of the signature as *big.Int
+// - *big.Int: the S component of the signature as *big.Int
+// - error: ErrKeyLocked if the key is not unlocked, or an error if any
+func (ks *FileKeystore) Sign(ctx context.Context, id string, msgHash *big.Int) (*big.Int, *big.Int, error) {
+	pub, err := new(felt.Felt).SetString(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	ks.mu.Lock()
+	privateKey, ok := ks.unlocked[pub.String()]
+	ks.mu.Unlock()
+	if !ok {
+		if _, err := os.Stat(ks.keyPath(pub)); err != nil {
+			return nil, nil, fmt.Errorf("error getting key for sender %s: %w", id, ErrSenderNoExist)
+		}
+		return nil, nil, fmt.Errorf("%w: %s", ErrKeyLocked, id)
+	}
+	return sign(ctx, msgHash, privateKey)
+}
+
+// keyPath returns the path of the JSON keystore of a public key.
+func (ks *FileKeystore) keyPath(publicKey *felt.Felt) string {
+	return filepath.Join(ks.dir, publicKey.String()+".json")
+}
+
+// validPrivateKey checks that a private key is in the range of the Stark keys.
+func validPrivateKey(privateKey *big.Int) bool {
+	return privateKey.Sign() > 0 && privateKey.Cmp(curve.Curve.N) < 0
+}
+
+// aesCTR encrypts or decrypts a text with AES in the counter mode.
+func aesCTR(key, iv, text []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("%w: iv length %d", ErrUnsupportedKeystore, len(iv))
+	}
+	out := make([]byte, len(text))
+	cipher.NewCTR(block, iv).XORKeyStream(out, text)
+	return out, nil
+}
+
+// decodeHex decodes a hexadecimal string of a JSON keystore, with or without its 0x prefix.
+func decodeHex(s string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
+
+// newUUID returns a random version 4 UUID, the id of a JSON keystore.
+func newUUID() (string, error) {
+	var u [16]byte
+	if _, err := rand.Read(u[:]); err != nil {
+		return "", err
+	}
+	u[6] = (u[6] & 0x0f) | 0x40
+	u[8] = (u[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:]), nil
+}
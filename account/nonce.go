@@ -0,0 +1,201 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// NonceStore holds the nonces allocated to the transactions of accounts. An implementation
+// backed by a shared database, such as Redis, lets several processes send transactions from
+// the same account. All the methods must be atomic.
+type NonceStore interface {
+	// Allocate returns the lowest released nonce of the account if any, else its next nonce,
+	// which is then incremented. It returns false if the account has no next nonce.
+	Allocate(ctx context.Context, address *felt.Felt) (*felt.Felt, bool, error)
+	// Seed sets the next nonce of the account, unless it already has one.
+	Seed(ctx context.Context, address, nonce *felt.Felt) error
+	// Release gives back an allocated nonce which was not used by a transaction.
+	Release(ctx context.Context, address, nonce *felt.Felt) error
+	// Reset forgets the next and the released nonces of the account.
+	Reset(ctx context.Context, address *felt.Felt) error
+}
+
+// memoryNonces are the nonces of an account in a MemoryNonceStore.
+type memoryNonces struct {
+	next     *felt.Felt
+	released []*felt.Felt
+}
+
+// MemoryNonceStore is a NonceStore keeping the nonces in memory, for the accounts used by a single process.
+type MemoryNonceStore struct {
+	mu     sync.Mutex
+	nonces map[felt.Felt]*memoryNonces
+}
+
+var _ NonceStore = &MemoryNonceStore{}
+
+// NewMemoryNonceStore creates an empty MemoryNonceStore.
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{nonces: make(map[felt.Felt]*memoryNonces)}
+}
+
+// Allocate implements NonceStore.
+func (s *MemoryNonceStore) Allocate(ctx context.Context, address *felt.Felt) (*felt.Felt, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	nonces, ok := s.nonces[*address]
+	if !ok {
+		return nil, false, nil
+	}
+	if len(nonces.released) > 0 {
+		nonce := nonces.released[0]
+		nonces.released = nonces.released[1:]
+		return nonce, true, nil
+	}
+	nonce := nonces.next
+	nonces.next = new(felt.Felt).Add(nonce, new(felt.Felt).SetUint64(1))
+	return nonce, true, nil
+}
+
+// Seed implements NonceStore.
+func (s *MemoryNonceStore) Seed(ctx context.Context, address, nonce *felt.Felt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.nonces[*address]; !ok {
+		s.nonces[*address] = &memoryNonces{next: nonce}
+	}
+	return nil
+}
+
+// Release implements NonceStore. Releasing the last allocated nonce decrements the next nonce,
+// the others are kept to be allocated first.
+func (s *MemoryNonceStore) Release(ctx context.Context, address, nonce *felt.Felt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	nonces, ok := s.nonces[*address]
+	// the nonces allocated before a reset are outdated
+	if !ok || nonce.Cmp(nonces.next) >= 0 {
+		return nil
+	}
+	nonces.released = append(nonces.released, nonce)
+	sort.Slice(nonces.released, func(i, j int) bool {
+		return nonces.released[i].Cmp(nonces.released[j]) < 0
+	})
+	for len(nonces.released) > 0 {
+		last := nonces.released[len(nonces.released)-1]
+		if !new(felt.Felt).Add(last, new(felt.Felt).SetUint64(1)).Equal(nonces.next) {
+			break
+		}
+		nonces.next = last
+		nonces.released = nonces.released[:len(nonces.released)-1]
+	}
+	return nil
+}
+
+// Reset implements NonceStore.
+func (s *MemoryNonceStore) Reset(ctx context.Context, address *felt.Felt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.nonces, *address)
+	return nil
+}
+
+// NonceManager allocates the nonces of the transactions of an account, so that several goroutines,
+// or several processes sharing a NonceStore, can send transactions from the account at the same time.
+// The nonces are seeded from the pending state of the account.
+type NonceManager struct {
+	provider rpc.RpcProvider
+	address  *felt.Felt
+	store    NonceStore
+}
+
+// NewNonceManager creates a NonceManager for an account.
+//
+// Parameters:
+// - provider: the provider reading the nonce of the account
+// - address: the address of the account
+// - store: the store holding the nonces, a new MemoryNonceStore if nil
+// Returns:
+// - *NonceManager: the nonce manager
+func NewNonceManager(provider rpc.RpcProvider, address *felt.Felt, store NonceStore) *NonceManager {
+	if store == nil {
+		store = NewMemoryNonceStore()
+	}
+	return &NonceManager{provider: provider, address: address, store: store}
+}
+
+// Next allocates a nonce, reading the nonce of the pending state of the account when the store has none.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// Returns:
+// - *felt.Felt: the nonce, to be released if the transaction is not accepted by the node
+// - error: an error if any
+func (m *NonceManager) Next(ctx context.Context) (*felt.Felt, error) {
+	nonce, ok, err := m.store.Allocate(ctx, m.address)
+	if err != nil || ok {
+		return nonce, err
+	}
+	pending, err := m.provider.Nonce(ctx, rpc.BlockID{Tag: "pending"}, m.address)
+	if err != nil {
+		return nil, err
+	}
+	// another caller may have seeded the store in the meantime, its nonce prevails
+	if err := m.store.Seed(ctx, m.address, pending); err != nil {
+		return nil, err
+	}
+	nonce, ok, err = m.store.Allocate(ctx, m.address)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("the nonce store has no nonce after being seeded")
+	}
+	return nonce, nil
+}
+
+// Release gives back a nonce whose transaction was not accepted by the node, to be allocated again.
+func (m *NonceManager) Release(ctx context.Context, nonce *felt.Felt) error {
+	return m.store.Release(ctx, m.address, nonce)
+}
+
+// Resync forgets the allocated nonces, the next allocation reading the nonce of the account again.
+func (m *NonceManager) Resync(ctx context.Context) error {
+	return m.store.Reset(ctx, m.address)
+}
+
+// Submit allocates a nonce and sends a transaction with it. When the node rejects the transaction
+// because of its nonce, or when the submission cannot tell whether the node got the transaction,
+// the nonces are resynced. When the transaction fails for another reason, the nonce is released.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - send: the function building, signing and sending the transaction with the given nonce
+// Returns:
+// - error: the error of send, if any
+func (m *NonceManager) Submit(ctx context.Context, send func(ctx context.Context, nonce *felt.Felt) error) error {
+	nonce, err := m.Next(ctx)
+	if err != nil {
+		return err
+	}
+	sendErr := send(ctx, nonce)
+	if sendErr == nil {
+		return nil
+	}
+
+	var rpcErr *rpc.RPCError
+	if errors.As(sendErr, &rpcErr) && (rpcErr.Code == rpc.ErrInvalidTransactionNonce.Code || rpcErr.Code == rpc.InternalError) {
+		err = m.Resync(ctx)
+	} else {
+		err = m.Release(ctx, nonce)
+	}
+	if err != nil {
+		return errors.Join(sendErr, err)
+	}
+	return sendErr
+}
@@ -0,0 +1,219 @@
+package account
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/hash"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// OutsideExecutionVersion is a version of the SNIP-9 outside execution, implemented by the
+// execute_from_outside function of the account for V1 and execute_from_outside_v2 for V2.
+// https://github.com/starknet-io/SNIPs/blob/main/SNIPS/snip-9.md
+type OutsideExecutionVersion int
+
+const (
+	// OutsideExecutionV1 hashes the outside executions with the revision 0 of SNIP-12, based on Pedersen.
+	OutsideExecutionV1 OutsideExecutionVersion = 1
+	// OutsideExecutionV2 hashes the outside executions with the revision 1 of SNIP-12, based on Poseidon.
+	OutsideExecutionV2 OutsideExecutionVersion = 2
+)
+
+var (
+	// AnyCaller is the caller of the outside executions which may be executed by any account.
+	AnyCaller = new(felt.Felt).SetBytes([]byte("ANY_CALLER"))
+
+	// OutsideExecutionV1InterfaceID is the SRC-5 interface id of the accounts supporting OutsideExecutionV1.
+	OutsideExecutionV1InterfaceID, _ = new(felt.Felt).SetString("0x68cfd18b92d1907b8ba3cc324900277f5a3622099431ea85dd8089255e4181")
+	// OutsideExecutionV2InterfaceID is the SRC-5 interface id of the accounts supporting OutsideExecutionV2.
+	OutsideExecutionV2InterfaceID, _ = new(felt.Felt).SetString("0x1d1144bb2138366ff28d8e9ab57456b1d332ac42196230c3a602003c89872")
+
+	ErrOutsideExecutionVersion = errors.New("unsupported outside execution version")
+
+	starknetMessage            = new(felt.Felt).SetBytes([]byte("StarkNet Message"))
+	outsideExecutionDomainName = new(felt.Felt).SetBytes([]byte("Account.execute_from_outside"))
+
+	// the type hashes of the revision 0 of SNIP-12
+	domainTypeHashRev0           = utils.GetSelectorFromNameFelt("StarkNetDomain(name:felt,version:felt,chainId:felt)")
+	outsideCallTypeHashRev0      = utils.GetSelectorFromNameFelt("OutsideCall(to:felt,selector:felt,calldata_len:felt,calldata:felt*)")
+	outsideExecutionTypeHashRev0 = utils.GetSelectorFromNameFelt("OutsideExecution(caller:felt,nonce:felt,execute_after:felt,execute_before:felt,calls_len:felt,calls:OutsideCall*)OutsideCall(to:felt,selector:felt,calldata_len:felt,calldata:felt*)")
+
+	// the type hashes of the revision 1 of SNIP-12
+	domainTypeHashRev1           = utils.GetSelectorFromNameFelt(`"StarknetDomain"("name":"shortstring","version":"shortstring","chainId":"shortstring","revision":"shortstring")`)
+	callTypeHashRev1             = utils.GetSelectorFromNameFelt(`"Call"("To":"ContractAddress","Selector":"selector","Calldata":"felt*")`)
+	outsideExecutionTypeHashRev1 = utils.GetSelectorFromNameFelt(`"OutsideExecution"("Caller":"ContractAddress","Nonce":"felt","Execute After":"u128","Execute Before":"u128","Calls":"Call*")"Call"("To":"ContractAddress","Selector":"selector","Calldata":"felt*")`)
+)
+
+// OutsideExecution is a set of calls signed by an account to be executed by another account,
+// such as a relayer paying the fee of the transaction.
+type OutsideExecution struct {
+	// Caller the only account allowed to execute the calls, AnyCaller for any account
+	Caller *felt.Felt
+	// Nonce a value which is not the nonce of a previous outside execution of the account
+	Nonce *felt.Felt
+	// ExecuteAfter the timestamp after which the calls can be executed
+	ExecuteAfter uint64
+	// ExecuteBefore the timestamp before which the calls can be executed
+	ExecuteBefore uint64
+	// Calls the calls executed by the account
+	Calls []rpc.FunctionCall
+}
+
+// Hash computes the SNIP-12 message hash of the outside execution, signed by the account.
+//
+// Parameters:
+// - version: the version of the outside execution
+// - accountAddress: the address of the account executing the calls
+// - chainID: the chain id, such as the ChainId of the account
+// Returns:
+// - *felt.Felt: the message hash
+// - error: ErrOutsideExecutionVersion for an unknown version
+func (oe OutsideExecution) Hash(version OutsideExecutionVersion, accountAddress, chainID *felt.Felt) (*felt.Felt, error) {
+	after := new(felt.Felt).SetUint64(oe.ExecuteAfter)
+	before := new(felt.Felt).SetUint64(oe.ExecuteBefore)
+
+	switch version {
+	case OutsideExecutionV1:
+		callHashes := make([]*felt.Felt, len(oe.Calls))
+		for i, call := range oe.Calls {
+			calldataHash, err := hash.ComputeHashOnElementsFelt(call.Calldata)
+			if err != nil {
+				return nil, err
+			}
+			callHashes[i], err = hash.ComputeHashOnElementsFelt([]*felt.Felt{
+				outsideCallTypeHashRev0, call.ContractAddress, call.EntryPointSelector, new(felt.Felt).SetUint64(uint64(len(call.Calldata))), calldataHash,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		callsHash, err := hash.ComputeHashOnElementsFelt(callHashes)
+		if err != nil {
+			return nil, err
+		}
+		messageHash, err := hash.ComputeHashOnElementsFelt([]*felt.Felt{
+			outsideExecutionTypeHashRev0, oe.Caller, oe.Nonce, after, before, new(felt.Felt).SetUint64(uint64(len(oe.Calls))), callsHash,
+		})
+		if err != nil {
+			return nil, err
+		}
+		domainHash, err := hash.ComputeHashOnElementsFelt([]*felt.Felt{
+			domainTypeHashRev0, outsideExecutionDomainName, new(felt.Felt).SetUint64(1), chainID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return hash.ComputeHashOnElementsFelt([]*felt.Felt{starknetMessage, domainHash, accountAddress, messageHash})
+	case OutsideExecutionV2:
+		callHashes := make([]*felt.Felt, len(oe.Calls))
+		for i, call := range oe.Calls {
+			callHashes[i] = crypto.PoseidonArray(callTypeHashRev1, call.ContractAddress, call.EntryPointSelector, crypto.PoseidonArray(call.Calldata...))
+		}
+		messageHash := crypto.PoseidonArray(outsideExecutionTypeHashRev1, oe.Caller, oe.Nonce, after, before, crypto.PoseidonArray(callHashes...))
+		domainHash := crypto.PoseidonArray(domainTypeHashRev1, outsideExecutionDomainName, new(felt.Felt).SetUint64(2), chainID, new(felt.Felt).SetUint64(1))
+		return crypto.PoseidonArray(starknetMessage, domainHash, accountAddress, messageHash), nil
+	}
+	return nil, ErrOutsideExecutionVersion
+}
+
+// Calldata serializes the outside execution and its signature, the arguments of the execute_from_outside
+// functions of the account.
+//
+// Parameters:
+// - signature: the signature of the outside execution by the account
+// Returns:
+// - []*felt.Felt: the calldata
+func (oe OutsideExecution) Calldata(signature []*felt.Felt) []*felt.Felt {
+	calldata := []*felt.Felt{
+		oe.Caller,
+		oe.Nonce,
+		new(felt.Felt).SetUint64(oe.ExecuteAfter),
+		new(felt.Felt).SetUint64(oe.ExecuteBefore),
+		new(felt.Felt).SetUint64(uint64(len(oe.Calls))),
+	}
+	for _, call := range oe.Calls {
+		calldata = append(calldata, call.ContractAddress, call.EntryPointSelector, new(felt.Felt).SetUint64(uint64(len(call.Calldata))))
+		calldata = append(calldata, call.Calldata...)
+	}
+	calldata = append(calldata, new(felt.Felt).SetUint64(uint64(len(signature))))
+	return append(calldata, signature...)
+}
+
+// SignOutsideExecution signs the hash of an outside execution of the account.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - oe: the outside execution
+// - version: the version of the outside execution supported by the account
+// Returns:
+// - []*felt.Felt: the signature
+// - error: an error if any
+func (account *Account) SignOutsideExecution(ctx context.Context, oe OutsideExecution, version OutsideExecutionVersion) ([]*felt.Felt, error) {
+	msgHash, err := oe.Hash(version, account.AccountAddress, account.ChainId)
+	if err != nil {
+		return nil, err
+	}
+	return account.Sign(ctx, msgHash)
+}
+
+// OutsideExecutionCall signs an outside execution of the account and returns the call executing it,
+// to be sent by the caller of the outside execution, such as with Account.Execute.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - oe: the outside execution
+// - version: the version of the outside execution supported by the account, see SupportsOutsideExecution
+// Returns:
+// - rpc.FunctionCall: the call of the execute_from_outside or execute_from_outside_v2 function of the account
+// - error: an error if any
+func (account *Account) OutsideExecutionCall(ctx context.Context, oe OutsideExecution, version OutsideExecutionVersion) (rpc.FunctionCall, error) {
+	signature, err := account.SignOutsideExecution(ctx, oe, version)
+	if err != nil {
+		return rpc.FunctionCall{}, err
+	}
+	selector := "execute_from_outside"
+	if version == OutsideExecutionV2 {
+		selector = "execute_from_outside_v2"
+	}
+	return rpc.FunctionCall{
+		ContractAddress:    account.AccountAddress,
+		EntryPointSelector: utils.GetSelectorFromNameFelt(selector),
+		Calldata:           oe.Calldata(signature),
+	}, nil
+}
+
+// SupportsOutsideExecution checks whether an account supports a version of the outside execution,
+// calling its SRC-5 supports_interface function.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - provider: the provider calling the account
+// - accountAddress: the address of the account
+// - version: the version of the outside execution
+// Returns:
+// - bool: true if the account supports the version
+// - error: an error if any
+func SupportsOutsideExecution(ctx context.Context, provider rpc.RpcProvider, accountAddress *felt.Felt, version OutsideExecutionVersion) (bool, error) {
+	var interfaceID *felt.Felt
+	switch version {
+	case OutsideExecutionV1:
+		interfaceID = OutsideExecutionV1InterfaceID
+	case OutsideExecutionV2:
+		interfaceID = OutsideExecutionV2InterfaceID
+	default:
+		return false, ErrOutsideExecutionVersion
+	}
+	result, err := provider.Call(ctx, rpc.FunctionCall{
+		ContractAddress:    accountAddress,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("supports_interface"),
+		Calldata:           []*felt.Felt{interfaceID},
+	}, rpc.BlockID{Tag: "pending"})
+	if err != nil {
+		return false, err
+	}
+	return len(result) == 1 && result[0].Equal(new(felt.Felt).SetUint64(1)), nil
+}
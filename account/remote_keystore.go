@@ -0,0 +1,306 @@
+package account
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// RemoteSignPath is the path of the sign endpoint of a remote keystore.
+const RemoteSignPath = "/sign"
+
+// maxRemoteBodySize bounds the size of the requests and responses of the remote keystores.
+const maxRemoteBodySize = 1 << 16
+
+var (
+	ErrRemoteKeystore         = errors.New("remote keystore error")
+	ErrKeyNotAllowed          = errors.New("the key is not allowed to sign")
+	ErrUnauthorized           = errors.New("the request is not authorized")
+	ErrInvalidRemoteSignature = errors.New("the remote keystore returned an invalid signature")
+)
+
+// RemoteSignRequest is the body of a request to the sign endpoint of a remote keystore.
+type RemoteSignRequest struct {
+	// ID the public key of the key signing the hash, as a hex string
+	ID string `json:"id"`
+	// Hash the message hash to sign, as a hex string
+	Hash string `json:"hash"`
+}
+
+// RemoteSignResponse is the body of a response of the sign endpoint of a remote keystore.
+// A successful response, with the status 200, holds the signature. Any other response holds the error.
+type RemoteSignResponse struct {
+	R     string `json:"r,omitempty"`
+	S     string `json:"s,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// RemoteKeystore implements the Keystore interface with a signing service, such as a
+// RemoteKeystoreServer wrapping keys held by an HSM.
+//
+// The wire protocol is JSON over HTTP. The keystore sends
+//
+//	POST <url>/sign
+//	{"id": "0x<public key>", "hash": "0x<message hash>"}
+//
+// with its headers, such as an Authorization header, and the service answers
+//
+//	200 {"r": "0x<r>", "s": "0x<s>"}
+//
+// or, for an error, 401 when the request is not authorized, 403 when the key is not allowed to sign,
+// 404 when the service does not hold the key, 400 for an invalid request, or 500, with {"error": "<message>"}.
+//
+// The ids of the keys are their public keys, and every signature is verified against the public key
+// before being returned.
+type RemoteKeystore struct {
+	url     string
+	client  *http.Client
+	headers http.Header
+}
+
+// NewRemoteKeystore creates a RemoteKeystore sending its requests to a signing service.
+//
+// Parameters:
+// - url: the base URL of the service, such as https://signer.example.com
+// - client: the HTTP client sending the requests, such as a client with the certificate of a mutual TLS, or nil for http.DefaultClient
+// - headers: the headers sent with every request, such as an Authorization header, or nil
+// Returns:
+// - *RemoteKeystore: the keystore
+func NewRemoteKeystore(url string, client *http.Client, headers http.Header) *RemoteKeystore {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &RemoteKeystore{
+		url:     strings.TrimSuffix(url, "/"),
+		client:  client,
+		headers: headers.Clone(),
+	}
+}
+
+// Sign requests the signature of a message hash from the signing service and verifies it
+// with the public key of the key.
+//
+// Parameters:
+// - ctx: the context of the operation.
+// - id: the public key of the key.
+// - msgHash: is the message hash to be signed.
+// Returns:
+// - *big.Int: the R component of the signature as *big.Int
+// - *big.Int: the S component of the signature as *big.Int
+// - error: ErrSenderNoExist, ErrKeyNotAllowed, ErrUnauthorized or ErrRemoteKeystore for the errors of the
+// service, ErrInvalidRemoteSignature if the signature is not verified, or an error if any
+func (ks *RemoteKeystore) Sign(ctx context.Context, id string, msgHash *big.Int) (*big.Int, *big.Int, error) {
+	publicKey, err := new(felt.Felt).SetString(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	body, err := json.Marshal(RemoteSignRequest{ID: publicKey.String(), Hash: "0x" + msgHash.Text(16)})
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ks.url+RemoteSignPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	for key, values := range ks.headers {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := ks.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	var result RemoteSignResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxRemoteBodySize)).Decode(&result); err != nil {
+		return nil, nil, fmt.Errorf("%w: status %d: %v", ErrRemoteKeystore, resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("%w: %s", remoteStatusError(resp.StatusCode), result.Error)
+	}
+
+	r, okR := new(big.Int).SetString(strings.TrimPrefix(result.R, "0x"), 16)
+	s, okS := new(big.Int).SetString(strings.TrimPrefix(result.S, "0x"), 16)
+	if !okR || !okS {
+		return nil, nil, ErrInvalidRemoteSignature
+	}
+	pubX := utils.FeltToBigInt(publicKey)
+	if !curve.Curve.Verify(msgHash, r, s, pubX, curve.Curve.GetYCoordinate(pubX)) {
+		return nil, nil, ErrInvalidRemoteSignature
+	}
+	return r, s, nil
+}
+
+// remoteStatusError gives the error of a status of the sign endpoint.
+func remoteStatusError(status int) error {
+	switch status {
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrKeyNotAllowed
+	case http.StatusNotFound:
+		return ErrSenderNoExist
+	}
+	return fmt.Errorf("%w: status %d", ErrRemoteKeystore, status)
+}
+
+// RemoteKeystoreServer is a reference signing service for the RemoteKeystore, wrapping any Keystore.
+// It serves the sign endpoint to the clients authenticated by mutual TLS, see RemoteKeystoreTLSConfig,
+// and optionally by the Authorize function, signs with the allowed keys only, and logs every
+// signed hash and rejected request.
+//
+//	server := account.NewRemoteKeystoreServer(keystore, []string{publicKey}, log.Default())
+//	tlsConfig, _ := account.RemoteKeystoreTLSConfig(serverCert, clientCAs)
+//	httpServer := &http.Server{Addr: ":8443", Handler: server, TLSConfig: tlsConfig}
+//	httpServer.ListenAndServeTLS("", "")
+type RemoteKeystoreServer struct {
+	keystore Keystore
+	allowed  map[string]bool
+	logger   *log.Logger
+
+	// Authorize checks the request of a client, such as its Authorization header, before signing.
+	// It is optional, and its error is sent to the client with the status 401.
+	Authorize func(r *http.Request) error
+}
+
+// NewRemoteKeystoreServer creates a RemoteKeystoreServer signing with the keys of a keystore.
+//
+// Parameters:
+// - keystore: the keystore signing the hashes
+// - allowedKeys: the public keys allowed to sign, as hex strings, all of the keys of the keystore if nil
+// - logger: the audit logger of the signed hashes and the rejected requests, or nil for log.Default()
+// Returns:
+// - *RemoteKeystoreServer: the server, an http.Handler
+// - error: an error if an allowed key is not a hex string
+func NewRemoteKeystoreServer(keystore Keystore, allowedKeys []string, logger *log.Logger) (*RemoteKeystoreServer, error) {
+	if logger == nil {
+		logger = log.Default()
+	}
+	server := &RemoteKeystoreServer{
+		keystore: keystore,
+		logger:   logger,
+	}
+	if allowedKeys != nil {
+		server.allowed = make(map[string]bool, len(allowedKeys))
+		for _, key := range allowedKeys {
+			publicKey, err := new(felt.Felt).SetString(key)
+			if err != nil {
+				return nil, err
+			}
+			server.allowed[publicKey.String()] = true
+		}
+	}
+	return server, nil
+}
+
+// ServeHTTP answers the requests of the sign endpoint.
+//
+// Parameters:
+// - w: the writer of the response
+// - r: the request
+func (s *RemoteKeystoreServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	client := remoteClientName(r)
+	if r.URL.Path != RemoteSignPath {
+		s.reply(w, http.StatusNotFound, RemoteSignResponse{Error: "not found"})
+		return
+	}
+	if r.Method != http.MethodPost {
+		s.reply(w, http.StatusMethodNotAllowed, RemoteSignResponse{Error: "method not allowed"})
+		return
+	}
+	if s.Authorize != nil {
+		if err := s.Authorize(r); err != nil {
+			s.logger.Printf("remote keystore: rejected unauthorized request from %s: %v", client, err)
+			s.reply(w, http.StatusUnauthorized, RemoteSignResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	var req RemoteSignRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxRemoteBodySize)).Decode(&req); err != nil {
+		s.reply(w, http.StatusBadRequest, RemoteSignResponse{Error: err.Error()})
+		return
+	}
+	publicKey, err := new(felt.Felt).SetString(req.ID)
+	if err != nil {
+		s.reply(w, http.StatusBadRequest, RemoteSignResponse{Error: "invalid id: " + err.Error()})
+		return
+	}
+	msgHash, ok := new(big.Int).SetString(strings.TrimPrefix(req.Hash, "0x"), 16)
+	if !ok {
+		s.reply(w, http.StatusBadRequest, RemoteSignResponse{Error: "invalid hash"})
+		return
+	}
+	if s.allowed != nil && !s.allowed[publicKey.String()] {
+		s.logger.Printf("remote keystore: rejected hash 0x%x for key %s from %s: %v", msgHash, publicKey, client, ErrKeyNotAllowed)
+		s.reply(w, http.StatusForbidden, RemoteSignResponse{Error: ErrKeyNotAllowed.Error()})
+		return
+	}
+
+	sigR, sigS, err := s.keystore.Sign(r.Context(), publicKey.String(), msgHash)
+	if err != nil {
+		s.logger.Printf("remote keystore: failed to sign hash 0x%x for key %s from %s: %v", msgHash, publicKey, client, err)
+		status := http.StatusInternalServerError
+		if errors.Is(err, ErrSenderNoExist) {
+			status = http.StatusNotFound
+		}
+		s.reply(w, status, RemoteSignResponse{Error: err.Error()})
+		return
+	}
+	s.logger.Printf("remote keystore: signed hash 0x%x for key %s from %s", msgHash, publicKey, client)
+	s.reply(w, http.StatusOK, RemoteSignResponse{R: "0x" + sigR.Text(16), S: "0x" + sigS.Text(16)})
+}
+
+// reply writes a JSON response.
+func (s *RemoteKeystoreServer) reply(w http.ResponseWriter, status int, resp RemoteSignResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Printf("remote keystore: failed to write the response: %v", err)
+	}
+}
+
+// remoteClientName names the client of a request for the audit log, by the subject of its
+// certificate with mutual TLS, or else by its address.
+func remoteClientName(r *http.Request) string {
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		return fmt.Sprintf("%q (%s)", r.TLS.PeerCertificates[0].Subject.String(), r.RemoteAddr)
+	}
+	return r.RemoteAddr
+}
+
+// RemoteKeystoreTLSConfig creates the TLS configuration of a RemoteKeystoreServer with mutual TLS,
+// accepting only the clients with a certificate signed by one of the client CAs.
+//
+// Parameters:
+// - certificate: the certificate of the server
+// - clientCAs: the PEM encoded certificates of the CAs of the clients
+// Returns:
+// - *tls.Config: the configuration, for the TLSConfig of an http.Server
+// - error: an error if no certificate is found in clientCAs
+func RemoteKeystoreTLSConfig(certificate tls.Certificate, clientCAs []byte) (*tls.Config, error) {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(clientCAs) {
+		return nil, errors.New("no client CA certificate found")
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
@@ -0,0 +1,101 @@
+package account
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+var (
+	ErrSessionExpired         = errors.New("the session has expired")
+	ErrCallNotAllowed         = errors.New("the call is not allowed by the session policy")
+	ErrSpendingLimitExceeded  = errors.New("the calls exceed the spending limit of the session")
+	ErrSessionAmountMalformed = errors.New("the amount of a token call is not an u256")
+
+	// the token functions whose amount counts against the spending limits
+	transferSelector = utils.GetSelectorFromNameFelt("transfer")
+	approveSelector  = utils.GetSelectorFromNameFelt("approve")
+)
+
+// SessionMethod is a function of a contract that a session key may call.
+type SessionMethod struct {
+	ContractAddress *felt.Felt
+	Selector        *felt.Felt
+}
+
+// SpendingLimit is the maximum amount of a token that a session key may transfer or approve.
+type SpendingLimit struct {
+	Token  *felt.Felt
+	Amount *big.Int
+}
+
+// SessionPolicy restricts the transactions a session key may send. It is only validated locally, before
+// sending the transactions: the signatures of the session accounts of Argent and Braavos, which enforce
+// their policies on chain, are not implemented by this package.
+type SessionPolicy struct {
+	// AllowedMethods the only functions the session key may call
+	AllowedMethods []SessionMethod
+	// ExpiresAt the timestamp, in seconds, from which the session key is rejected
+	ExpiresAt uint64
+	// SpendingLimits the maximum amounts of the tokens transferred or approved during the session
+	SpendingLimits []SpendingLimit
+}
+
+// Validate checks that calls can be sent with the session key: the session has not expired, each call
+// is an allowed method, and the amounts transferred or approved, added to the given spent amounts,
+// stay within the spending limits.
+//
+// Parameters:
+// - calls: the calls of a transaction
+// - now: the current time
+// - spent: the amounts of the tokens already spent during the session, or nil
+// Returns:
+// - error: an error wrapping ErrSessionExpired, ErrCallNotAllowed or ErrSpendingLimitExceeded, if any
+func (p SessionPolicy) Validate(calls []rpc.FunctionCall, now time.Time, spent map[felt.Felt]*big.Int) error {
+	if now.Unix() < 0 || uint64(now.Unix()) >= p.ExpiresAt {
+		return ErrSessionExpired
+	}
+	total := make(map[felt.Felt]*big.Int, len(spent))
+	for token, amount := range spent {
+		total[token] = new(big.Int).Set(amount)
+	}
+	for _, call := range calls {
+		if !p.allows(call) {
+			return fmt.Errorf("%w: %s of %s", ErrCallNotAllowed, call.EntryPointSelector, call.ContractAddress)
+		}
+		if !call.EntryPointSelector.Equal(transferSelector) && !call.EntryPointSelector.Equal(approveSelector) {
+			continue
+		}
+		// the recipient or the spender, then the u256 amount
+		if len(call.Calldata) != 3 {
+			return ErrSessionAmountMalformed
+		}
+		amount := new(big.Int).Lsh(utils.FeltToBigInt(call.Calldata[2]), 128)
+		amount.Add(amount, utils.FeltToBigInt(call.Calldata[1]))
+		if current, ok := total[*call.ContractAddress]; ok {
+			amount.Add(amount, current)
+		}
+		total[*call.ContractAddress] = amount
+	}
+	for _, limit := range p.SpendingLimits {
+		if amount, ok := total[*limit.Token]; ok && amount.Cmp(limit.Amount) > 0 {
+			return fmt.Errorf("%w: %s of token %s", ErrSpendingLimitExceeded, amount, limit.Token)
+		}
+	}
+	return nil
+}
+
+// allows tells whether a call is one of the allowed methods.
+func (p SessionPolicy) allows(call rpc.FunctionCall) bool {
+	for _, method := range p.AllowedMethods {
+		if method.ContractAddress.Equal(call.ContractAddress) && method.Selector.Equal(call.EntryPointSelector) {
+			return true
+		}
+	}
+	return false
+}
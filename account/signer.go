@@ -0,0 +1,166 @@
+package account
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signer signs the transactions of an account, returning the signature in the format expected
+// by the __validate__ function of the account contract.
+type Signer interface {
+	// Sign returns the signature of a message hash, such as a transaction hash.
+	Sign(ctx context.Context, msgHash *felt.Felt) ([]*felt.Felt, error)
+}
+
+var (
+	_ Signer = &StarkSigner{}
+	_ Signer = &Secp256k1Signer{}
+	_ Signer = &Secp256r1Signer{}
+	_ Signer = &GuardedSigner{}
+	_ Signer = &Account{}
+)
+
+// StarkSigner signs with a Stark key held by a Keystore, giving the [r, s] signature of the
+// OpenZeppelin, Argent and Braavos accounts.
+type StarkSigner struct {
+	keystore  Keystore
+	publicKey string
+}
+
+// NewStarkSigner creates a StarkSigner.
+//
+// Parameters:
+// - keystore: the keystore holding the private key
+// - publicKey: the public key identifying the private key in the keystore
+// Returns:
+// - *StarkSigner: the signer
+func NewStarkSigner(keystore Keystore, publicKey string) *StarkSigner {
+	return &StarkSigner{keystore: keystore, publicKey: publicKey}
+}
+
+// Sign implements Signer.
+func (s *StarkSigner) Sign(ctx context.Context, msgHash *felt.Felt) ([]*felt.Felt, error) {
+	r, sig, err := s.keystore.Sign(ctx, s.publicKey, utils.FeltToBigInt(msgHash))
+	if err != nil {
+		return nil, err
+	}
+	return []*felt.Felt{utils.BigIntToFelt(r), utils.BigIntToFelt(sig)}, nil
+}
+
+// Secp256k1Signer signs with an Ethereum key, giving the [r.low, r.high, s.low, s.high, v]
+// signature of the OpenZeppelin EthAccount, where r and s are u256 and v is the parity of y.
+type Secp256k1Signer struct {
+	privateKey *ecdsa.PrivateKey
+	eip191     bool
+}
+
+// NewSecp256k1Signer creates a Secp256k1Signer.
+//
+// Parameters:
+// - privateKey: the secp256k1 private key, such as returned by go-ethereum's crypto.HexToECDSA
+// - eip191: whether to sign the EIP-191 personal message of the hash instead of the hash itself
+// Returns:
+// - *Secp256k1Signer: the signer
+func NewSecp256k1Signer(privateKey *ecdsa.PrivateKey, eip191 bool) *Secp256k1Signer {
+	return &Secp256k1Signer{privateKey: privateKey, eip191: eip191}
+}
+
+// Sign implements Signer.
+func (s *Secp256k1Signer) Sign(ctx context.Context, msgHash *felt.Felt) ([]*felt.Felt, error) {
+	digest := msgHash.Bytes()
+	hash := digest[:]
+	if s.eip191 {
+		hash = ethcrypto.Keccak256([]byte("\x19Ethereum Signed Message:\n32"), hash)
+	}
+	// the signature is r || s || v, with a low s
+	sig, err := ethcrypto.Sign(hash, s.privateKey)
+	if err != nil {
+		return nil, err
+	}
+	signature := append(u256ToFelts(new(big.Int).SetBytes(sig[:32])), u256ToFelts(new(big.Int).SetBytes(sig[32:64]))...)
+	return append(signature, new(felt.Felt).SetUint64(uint64(sig[64]))), nil
+}
+
+// Secp256r1Signer signs with a P-256 key, such as the hardware keys of the Braavos accounts,
+// giving the [r.low, r.high, s.low, s.high] signature where r and s are u256.
+type Secp256r1Signer struct {
+	privateKey *ecdsa.PrivateKey
+}
+
+// NewSecp256r1Signer creates a Secp256r1Signer.
+//
+// Parameters:
+// - privateKey: the private key, on the elliptic.P256 curve
+// Returns:
+// - *Secp256r1Signer: the signer
+// - error: an error if the key is not on the P-256 curve
+func NewSecp256r1Signer(privateKey *ecdsa.PrivateKey) (*Secp256r1Signer, error) {
+	if privateKey.Curve != elliptic.P256() {
+		return nil, errors.New("the private key is not on the P-256 curve")
+	}
+	return &Secp256r1Signer{privateKey: privateKey}, nil
+}
+
+// Sign implements Signer.
+func (s *Secp256r1Signer) Sign(ctx context.Context, msgHash *felt.Felt) ([]*felt.Felt, error) {
+	digest := msgHash.Bytes()
+	r, sig, err := ecdsa.Sign(rand.Reader, s.privateKey, digest[:])
+	if err != nil {
+		return nil, err
+	}
+	// the accounts only accept the low s of the two valid ones
+	order := s.privateKey.Curve.Params().N
+	if sig.Cmp(new(big.Int).Rsh(order, 1)) > 0 {
+		sig.Sub(order, sig)
+	}
+	return append(u256ToFelts(r), u256ToFelts(sig)...), nil
+}
+
+// GuardedSigner signs for an Argent account with a guardian: the signature is the [r, s] signature of
+// the owner followed by the [r, s] signature of the guardian. It does not implement the signature
+// layouts of the Argent multisig accounts or of the accounts with other kinds of signers, which carry
+// the public key of each signer.
+type GuardedSigner struct {
+	owner    Signer
+	guardian Signer
+}
+
+// NewGuardedSigner creates a GuardedSigner.
+//
+// Parameters:
+// - owner: the signer of the owner of the account
+// - guardian: the signer of the guardian of the account, nil for an account without guardian
+// Returns:
+// - *GuardedSigner: the signer
+func NewGuardedSigner(owner, guardian Signer) *GuardedSigner {
+	return &GuardedSigner{owner: owner, guardian: guardian}
+}
+
+// Sign implements Signer.
+func (s *GuardedSigner) Sign(ctx context.Context, msgHash *felt.Felt) ([]*felt.Felt, error) {
+	signature, err := s.owner.Sign(ctx, msgHash)
+	if err != nil || s.guardian == nil {
+		return signature, err
+	}
+	guardianSignature, err := s.guardian.Sign(ctx, msgHash)
+	if err != nil {
+		return nil, err
+	}
+	return append(signature, guardianSignature...), nil
+}
+
+// u256ToFelts splits an u256 into its low and high 128 bits, the Cairo serialization of an u256.
+func u256ToFelts(value *big.Int) []*felt.Felt {
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	low := new(big.Int).And(value, mask)
+	high := new(big.Int).Rsh(value, 128)
+	return []*felt.Felt{utils.BigIntToFelt(low), utils.BigIntToFelt(high)}
+}
@@ -0,0 +1,125 @@
+package account
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+var (
+	// UDCAddress is the address of the Universal Deployer Contract, the same on all the networks.
+	// https://docs.starknet.io/architecture-and-concepts/accounts/universal-deployer/
+	UDCAddress, _ = new(felt.Felt).SetString("0x041a78e741e5af2fec34b695679bc6891742439f7afb8484ecd7766661ad02bf")
+
+	udcDeployContractSelector    = utils.GetSelectorFromNameFelt("deployContract")
+	udcContractDeployedSelector  = utils.GetSelectorFromNameFelt("ContractDeployed")
+	ErrContractDeployedNotFound  = fmt.Errorf("the receipt has no ContractDeployed event of the UDC")
+	ErrUnexpectedContractAddress = fmt.Errorf("the deployed contract address differs from the precomputed one")
+)
+
+// DeployResult is the outcome of Account.DeployViaUDC.
+type DeployResult struct {
+	// TransactionHash the hash of the invoke transaction calling the UDC
+	TransactionHash *felt.Felt
+	// ContractAddress the address of the deployed contract
+	ContractAddress *felt.Felt
+}
+
+// PrecomputeUDCAddress computes the address of a contract deployed through the UDC.
+// A unique deployment mixes the address of the deployer in the salt, and the UDC is the deployer
+// of the contract; otherwise the address does not depend on the deployer.
+//
+// Parameters:
+// - deployerAddress: the address of the account calling the UDC
+// - classHash: the class hash of the contract
+// - constructorCalldata: the arguments of the constructor of the contract
+// - salt: the salt of the deployment
+// - unique: whether the deployment is unique to the deployer
+// Returns:
+// - *felt.Felt: the address of the contract
+// - error: an error if any
+func PrecomputeUDCAddress(deployerAddress, classHash *felt.Felt, constructorCalldata []*felt.Felt, salt *felt.Felt, unique bool) (*felt.Felt, error) {
+	if unique {
+		return contracts.PrecomputeAddress(UDCAddress, crypto.Pedersen(deployerAddress, salt), classHash, constructorCalldata)
+	}
+	return contracts.PrecomputeAddress(&felt.Zero, salt, classHash, constructorCalldata)
+}
+
+// DeployViaUDC deploys a contract of a declared class by calling the deployContract function of the UDC,
+// then waits for the receipt of the transaction. The address of the contract, precomputed from the
+// deployment parameters, is checked against the ContractDeployed event of the UDC.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - classHash: the class hash of the contract
+// - constructorCalldata: the arguments of the constructor of the contract
+// - salt: the salt of the deployment, random if nil
+// - unique: whether the deployment is unique to the account, see PrecomputeUDCAddress
+// - opts: the options of the transaction, such as WithTxnVersion or WithPollInterval
+// Returns:
+// - *DeployResult: the hash of the transaction and the address of the contract
+// - error: an error if any
+func (account *Account) DeployViaUDC(ctx context.Context, classHash *felt.Felt, constructorCalldata []*felt.Felt, salt *felt.Felt, unique bool, opts ...ExecuteOption) (*DeployResult, error) {
+	options, err := newExecuteOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if salt == nil {
+		if salt, err = new(felt.Felt).SetRandom(); err != nil {
+			return nil, err
+		}
+	}
+	address, err := PrecomputeUDCAddress(account.AccountAddress, classHash, constructorCalldata, salt, unique)
+	if err != nil {
+		return nil, err
+	}
+
+	uniqueFelt := new(felt.Felt)
+	if unique {
+		uniqueFelt.SetUint64(1)
+	}
+	calldata := append([]*felt.Felt{classHash, salt, uniqueFelt, new(felt.Felt).SetUint64(uint64(len(constructorCalldata)))}, constructorCalldata...)
+	result, err := account.Execute(ctx, []rpc.FunctionCall{{
+		ContractAddress:    UDCAddress,
+		EntryPointSelector: udcDeployContractSelector,
+		Calldata:           calldata,
+	}}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt, err := account.WaitForTransactionReceipt(ctx, result.TransactionHash, options.pollInterval)
+	if err != nil {
+		return nil, err
+	}
+	deployed, err := deployedAddress(receipt)
+	if err != nil {
+		return nil, err
+	}
+	if !deployed.Equal(address) {
+		return nil, fmt.Errorf("%w: got %s, expected %s", ErrUnexpectedContractAddress, deployed, address)
+	}
+	return &DeployResult{TransactionHash: result.TransactionHash, ContractAddress: address}, nil
+}
+
+// deployedAddress returns the address of the contract deployed by the UDC, given by the first data of its ContractDeployed event.
+func deployedAddress(receipt *rpc.TransactionReceiptWithBlockInfo) (*felt.Felt, error) {
+	common, ok := receipt.TransactionReceipt.(rpc.InvokeTransactionReceipt)
+	if !ok {
+		return nil, fmt.Errorf("unexpected receipt %T", receipt.TransactionReceipt)
+	}
+	if common.ExecutionStatus == rpc.TxnExecutionStatusREVERTED {
+		return nil, fmt.Errorf("the transaction reverted: %s", common.RevertReason)
+	}
+	for _, event := range common.Events {
+		if event.FromAddress.Equal(UDCAddress) && len(event.Keys) > 0 && event.Keys[0].Equal(udcContractDeployedSelector) && len(event.Data) > 0 {
+			return event.Data[0], nil
+		}
+	}
+	return nil, ErrContractDeployedNotFound
+}
@@ -5,9 +5,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/big"
-	"math/rand"
 	"strconv"
-	"time"
 
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/starknet.go/account"
@@ -21,8 +19,6 @@ import (
 // NOTE : Please add in your keys only for testing purposes, in case of a leak you would potentially lose your funds.
 var (
 	someContractHash string = "0x046ded64ae2dead6448e247234bab192a9c483644395b66f2155f2614e5804b0" // The contract hash to be deployed (in this example, it's an ERC20 contract)
-	UDCAddress       string = "0x041a78e741e5af2fec34b695679bc6891742439f7afb8484ecd7766661ad02bf" // UDC contract address
-	contractMethod   string = "deployContract"                                                     // UDC method to deploy a contract (from pre-declared contracts)
 )
 
 // Example succesful transaction created from this example on Sepolia
@@ -66,75 +62,19 @@ func main() {
 		panic(err)
 	}
 
-	// Get the accounts nonce
-	nonce, err := accnt.Nonce(context.Background(), rpc.BlockID{Tag: "latest"}, accnt.AccountAddress)
-	if err != nil {
-		setup.PanicRPC(err)
-	}
-
-	// Build the InvokeTx struct
-	InvokeTx := rpc.BroadcastInvokev1Txn{
-		InvokeTxnV1: rpc.InvokeTxnV1{
-			MaxFee:        new(felt.Felt).SetUint64(100000000000000),
-			Version:       rpc.TransactionV1,
-			Nonce:         nonce,
-			Type:          rpc.TransactionType_Invoke,
-			SenderAddress: accnt.AccountAddress,
-		}}
-
-	// Convert the contractAddress from hex to felt
-	contractAddress, err := utils.HexToFelt(UDCAddress)
-	if err != nil {
-		panic(err)
-	}
-
-	// Build the functionCall struct, where :
-	FnCall := rpc.FunctionCall{
-		ContractAddress:    contractAddress,                               //contractAddress is the contract that we want to call
-		EntryPointSelector: utils.GetSelectorFromNameFelt(contractMethod), //this is the function that we want to call
-		Calldata:           getUDCCalldata(accountAddress),                //change this function content to your use case
-	}
-
-	// Building the Calldata with the help of FmtCalldata where we pass in the FnCall struct along with the Cairo version
-	InvokeTx.Calldata, err = accnt.FmtCalldata([]rpc.FunctionCall{FnCall})
-	if err != nil {
-		panic(err)
-	}
-
-	// Sign the transaction
-	err = accnt.SignInvokeTransaction(context.Background(), &InvokeTx.InvokeTxnV1)
+	classHash, err := new(felt.Felt).SetString(someContractHash)
 	if err != nil {
 		panic(err)
 	}
 
-	// Estimate the transaction fee
-	feeRes, err := accnt.EstimateFee(context.Background(), []rpc.BroadcastTxn{InvokeTx}, []rpc.SimulationFlag{}, rpc.WithBlockTag("latest"))
+	// DeployViaUDC calls the deployContract function of the UDC, where :
+	// - the salt prevents address clashes, a random one is picked when it is nil
+	// - unique mixes the account address in the salt, see https://docs.starknet.io/architecture-and-concepts/accounts/universal-deployer/#deployment_types
+	// It then waits for the receipt and checks the address of the contract against the ContractDeployed event of the UDC.
+	resp, err := accnt.DeployViaUDC(context.Background(), classHash, getConstructorCalldata(accountAddress), nil, false)
 	if err != nil {
 		setup.PanicRPC(err)
 	}
-	estimatedFee := feeRes[0].OverallFee
-	// If the estimated fee is higher than the current fee, let's override it and sign again
-	if estimatedFee.Cmp(InvokeTx.MaxFee) == 1 {
-		newFee, err := strconv.ParseUint(estimatedFee.String(), 0, 64)
-		if err != nil {
-			panic(err)
-		}
-		InvokeTx.MaxFee = new(felt.Felt).SetUint64(newFee + newFee/5) // fee + 20% to be sure
-		// Signing the transaction again
-		err = accnt.SignInvokeTransaction(context.Background(), &InvokeTx.InvokeTxnV1)
-		if err != nil {
-			panic(err)
-		}
-	}
-
-	// After the signing we finally call the AddInvokeTransaction in order to invoke the contract function
-	resp, err := accnt.AddInvokeTransaction(context.Background(), InvokeTx)
-	if err != nil {
-		setup.PanicRPC(err)
-	}
-
-	fmt.Println("Waiting for the transaction status...")
-	time.Sleep(time.Second * 3) // Waiting 3 seconds
 
 	//Getting the transaction status
 	txStatus, err := client.GetTransactionStatus(context.Background(), resp.TransactionHash)
@@ -142,28 +82,15 @@ func main() {
 		setup.PanicRPC(err)
 	}
 
-	// This returns us with the transaction hash and status
+	// This returns us with the transaction hash, the contract address and the status
 	fmt.Printf("Transaction hash response: %v\n", resp.TransactionHash)
+	fmt.Printf("Contract address: %v\n", resp.ContractAddress)
 	fmt.Printf("Transaction execution status: %s\n", txStatus.ExecutionStatus)
 	fmt.Printf("Transaction status: %s\n", txStatus.FinalityStatus)
 }
 
-// getUDCCalldata is a simple helper to set the call data required by the UDCs deployContract function. Update as needed.
-func getUDCCalldata(data ...string) []*felt.Felt {
-
-	classHash, err := new(felt.Felt).SetString(someContractHash)
-	if err != nil {
-		panic(err)
-	}
-
-	randomInt := rand.Uint64()
-	salt := new(felt.Felt).SetUint64(randomInt) // to prevent address clashes
-
-	unique, err := new(felt.Felt).SetString("0x0") // see https://docs.starknet.io/architecture-and-concepts/accounts/universal-deployer/#deployment_types
-	if err != nil {
-		panic(err)
-	}
-
+// getConstructorCalldata is a simple helper to set the constructor parameters of the deployed contract. Update as needed.
+func getConstructorCalldata(data ...string) []*felt.Felt {
 	// As we are using an ERC20 token in this example, the calldata needs to have the ERC20 constructor required parameters.
 	// You must adjust these fields to match the constructor's parameters of your desired contract.
 	// https://docs.openzeppelin.com/contracts-cairo/0.8.1/api/erc20#ERC20-constructor-section
@@ -177,12 +104,5 @@ func getUDCCalldata(data ...string) []*felt.Felt {
 	if err != nil {
 		panic(err)
 	}
-
-	length := int64(len(calldata))
-	calldataLen, err := new(felt.Felt).SetString(strconv.FormatInt(length, 16))
-	if err != nil {
-		panic(err)
-	}
-
-	return append([]*felt.Felt{classHash, salt, unique, calldataLen}, calldata...)
+	return calldata
 }
@@ -0,0 +1,69 @@
+package follower
+
+import (
+	"context"
+	"sync"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// BlockRef identifies a block applied by a ChainFollower.
+type BlockRef struct {
+	BlockNumber uint64     `json:"block_number"`
+	BlockHash   *felt.Felt `json:"block_hash"`
+}
+
+// Checkpoint is the cursor of a ChainFollower: the last blocks it applied,
+// kept to revert them when a reorg is detected.
+type Checkpoint struct {
+	// Blocks are the last applied blocks, from the oldest to the most recent
+	Blocks []BlockRef `json:"blocks"`
+}
+
+// Head returns the last applied block, nil if no block was applied.
+func (c Checkpoint) Head() *BlockRef {
+	if len(c.Blocks) == 0 {
+		return nil
+	}
+	return &c.Blocks[len(c.Blocks)-1]
+}
+
+// CheckpointStore persists the checkpoint of a ChainFollower, which saves it after each applied or reverted block.
+type CheckpointStore interface {
+	// Load returns the saved checkpoint, nil if none was saved yet.
+	Load(ctx context.Context) (*Checkpoint, error)
+	// Save replaces the saved checkpoint.
+	Save(ctx context.Context, checkpoint Checkpoint) error
+}
+
+// MemoryCheckpointStore is a CheckpointStore keeping the checkpoint in memory.
+type MemoryCheckpointStore struct {
+	mu         sync.Mutex
+	checkpoint *Checkpoint
+}
+
+var _ CheckpointStore = &MemoryCheckpointStore{}
+
+// NewMemoryCheckpointStore creates an empty MemoryCheckpointStore.
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{}
+}
+
+// Load implements CheckpointStore.
+func (s *MemoryCheckpointStore) Load(ctx context.Context) (*Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.checkpoint == nil {
+		return nil, nil
+	}
+	checkpoint := Checkpoint{Blocks: append([]BlockRef{}, s.checkpoint.Blocks...)}
+	return &checkpoint, nil
+}
+
+// Save implements CheckpointStore.
+func (s *MemoryCheckpointStore) Save(ctx context.Context, checkpoint Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoint = &Checkpoint{Blocks: append([]BlockRef{}, checkpoint.Blocks...)}
+	return nil
+}
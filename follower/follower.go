@@ -0,0 +1,266 @@
+package follower
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+const (
+	defaultPollInterval  = 5 * time.Second
+	defaultMaxReorgDepth = 64
+)
+
+var ErrReorgTooDeep = errors.New("the reorg is deeper than the blocks kept in the checkpoint")
+
+// Handler receives the changes of the chain followed by a ChainFollower.
+// When a method returns an error, the follower stops and the block is handled again by the next Sync.
+type Handler interface {
+	// BlockApplied is called for each block added to the chain, in order.
+	BlockApplied(ctx context.Context, block *rpc.BlockWithReceipts) error
+	// BlockReverted is called for each block removed from the chain by a reorg, from the most recent one.
+	BlockReverted(ctx context.Context, block BlockRef) error
+}
+
+// HandlerFuncs is a Handler calling the given functions, the nil ones being skipped.
+type HandlerFuncs struct {
+	Applied  func(ctx context.Context, block *rpc.BlockWithReceipts) error
+	Reverted func(ctx context.Context, block BlockRef) error
+}
+
+var _ Handler = HandlerFuncs{}
+
+// BlockApplied implements Handler.
+func (h HandlerFuncs) BlockApplied(ctx context.Context, block *rpc.BlockWithReceipts) error {
+	if h.Applied == nil {
+		return nil
+	}
+	return h.Applied(ctx, block)
+}
+
+// BlockReverted implements Handler.
+func (h HandlerFuncs) BlockReverted(ctx context.Context, block BlockRef) error {
+	if h.Reverted == nil {
+		return nil
+	}
+	return h.Reverted(ctx, block)
+}
+
+// Options configures a ChainFollower. The zero values select the defaults.
+type Options struct {
+	// StartBlock is the first block applied when the store holds no checkpoint
+	StartBlock uint64
+	// Confirmations is the number of blocks a block must be behind the head of the chain to be applied
+	Confirmations uint64
+	// Finality is the status a block must reach to be applied, rpc.BlockStatus_AcceptedOnL2 by default.
+	// The blocks accepted on L1 cannot be reverted.
+	Finality rpc.BlockStatus
+	// PollInterval is the interval between two polls of the head of the chain by Run, 5s by default
+	PollInterval time.Duration
+	// MaxReorgDepth is the number of applied blocks kept in the checkpoint to detect and revert reorgs, 64 by default
+	MaxReorgDepth int
+}
+
+// ChainFollower follows the chain of a node, applying the new blocks and
+// reverting the ones left by a reorg, which it detects by checking that each
+// block has the last applied block as parent.
+type ChainFollower struct {
+	provider rpc.RpcProvider
+	handler  Handler
+	store    CheckpointStore
+	opts     Options
+
+	mu         sync.Mutex
+	checkpoint *Checkpoint
+}
+
+// NewChainFollower creates a ChainFollower, which resumes from the checkpoint of the store if any.
+//
+// Parameters:
+// - provider: the provider used to fetch the blocks
+// - handler: the handler of the applied and reverted blocks
+// - store: the store of the checkpoint
+// - opts: the options of the follower
+// Returns:
+// - *ChainFollower: the follower
+func NewChainFollower(provider rpc.RpcProvider, handler Handler, store CheckpointStore, opts Options) *ChainFollower {
+	if opts.Finality == "" {
+		opts.Finality = rpc.BlockStatus_AcceptedOnL2
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultPollInterval
+	}
+	if opts.MaxReorgDepth <= 0 {
+		opts.MaxReorgDepth = defaultMaxReorgDepth
+	}
+	return &ChainFollower{
+		provider: provider,
+		handler:  handler,
+		store:    store,
+		opts:     opts,
+	}
+}
+
+// Run syncs the follower every PollInterval until the context is cancelled or a sync fails.
+//
+// Parameters:
+// - ctx: the context stopping the follower
+// Returns:
+// - error: the error of the failed sync, or the error of the context
+func (f *ChainFollower) Run(ctx context.Context) error {
+	ticker := time.NewTicker(f.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		if err := f.Sync(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync applies the blocks up to the head of the chain less the confirmations,
+// reverting first the applied blocks that are no longer part of the chain.
+//
+// Parameters:
+// - ctx: the context of the requests and of the handler
+// Returns:
+// - error: an error if a request, the handler or the store fails
+func (f *ChainFollower) Sync(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.checkpoint == nil {
+		checkpoint, err := f.store.Load(ctx)
+		if err != nil {
+			return err
+		}
+		if checkpoint == nil {
+			checkpoint = &Checkpoint{}
+		}
+		f.checkpoint = checkpoint
+	}
+
+	head, err := f.provider.BlockHashAndNumber(ctx)
+	if err != nil {
+		return err
+	}
+	// the node may have switched to a fork with as many blocks or fewer
+	if last := f.checkpoint.Head(); last != nil && (head.BlockNumber < last.BlockNumber ||
+		head.BlockNumber == last.BlockNumber && !head.BlockHash.Equal(last.BlockHash)) {
+		if err := f.rewind(ctx); err != nil {
+			return err
+		}
+	}
+	if head.BlockNumber < f.opts.Confirmations {
+		return nil
+	}
+
+	target := head.BlockNumber - f.opts.Confirmations
+	for next := f.next(); next <= target; next = f.next() {
+		block, err := f.block(ctx, next)
+		if err != nil || block == nil {
+			return err
+		}
+
+		if last := f.checkpoint.Head(); last != nil && !block.ParentHash.Equal(last.BlockHash) {
+			// the last applied block is not the parent of the block, so it was reorged out
+			if err := f.revert(ctx); err != nil {
+				return err
+			}
+			if err := f.rewind(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := f.handler.BlockApplied(ctx, block); err != nil {
+			return err
+		}
+		f.checkpoint.Blocks = append(f.checkpoint.Blocks, BlockRef{BlockNumber: block.BlockNumber, BlockHash: block.BlockHash})
+		if len(f.checkpoint.Blocks) > f.opts.MaxReorgDepth {
+			f.checkpoint.Blocks = f.checkpoint.Blocks[len(f.checkpoint.Blocks)-f.opts.MaxReorgDepth:]
+		}
+		if err := f.store.Save(ctx, *f.checkpoint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Checkpoint returns the current checkpoint of the follower, nil before the first Sync.
+func (f *ChainFollower) Checkpoint() *Checkpoint {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.checkpoint == nil {
+		return nil
+	}
+	return &Checkpoint{Blocks: append([]BlockRef{}, f.checkpoint.Blocks...)}
+}
+
+// next returns the number of the next block to apply.
+func (f *ChainFollower) next() uint64 {
+	if last := f.checkpoint.Head(); last != nil {
+		return last.BlockNumber + 1
+	}
+	return f.opts.StartBlock
+}
+
+// block fetches a block with its receipts, returning nil if the block is not final enough to be applied.
+func (f *ChainFollower) block(ctx context.Context, number uint64) (*rpc.BlockWithReceipts, error) {
+	result, err := rpc.FetchBlockWithReceipts(ctx, f.provider, rpc.BlockID{Number: &number})
+	if err != nil {
+		if isBlockNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	block, ok := result.Block().(*rpc.BlockWithReceipts)
+	if !ok || (f.opts.Finality == rpc.BlockStatus_AcceptedOnL1 && block.BlockStatus != rpc.BlockStatus_AcceptedOnL1) {
+		return nil, nil
+	}
+	return block, nil
+}
+
+// rewind reverts the applied blocks until the last one is part of the chain of the node.
+func (f *ChainFollower) rewind(ctx context.Context) error {
+	for {
+		last := f.checkpoint.Head()
+		result, err := rpc.FetchBlockWithTxHashes(ctx, f.provider, rpc.BlockID{Number: &last.BlockNumber})
+		if err != nil && !isBlockNotFound(err) {
+			return err
+		}
+		if err == nil && !result.IsPending() && result.Header().BlockHash.Equal(last.BlockHash) {
+			return nil
+		}
+		if err := f.revert(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// revert reverts the last applied block. The oldest block of the checkpoint is never
+// reverted, as the blocks preceding it would then be unknown.
+func (f *ChainFollower) revert(ctx context.Context) error {
+	if len(f.checkpoint.Blocks) <= 1 {
+		return ErrReorgTooDeep
+	}
+	last := f.checkpoint.Head()
+	if err := f.handler.BlockReverted(ctx, *last); err != nil {
+		return err
+	}
+	f.checkpoint.Blocks = f.checkpoint.Blocks[:len(f.checkpoint.Blocks)-1]
+	return f.store.Save(ctx, *f.checkpoint)
+}
+
+// isBlockNotFound tells whether the error is rpc.ErrBlockNotFound.
+func isBlockNotFound(err error) bool {
+	var rpcErr *rpc.RPCError
+	return errors.As(err, &rpcErr) && rpcErr.Code == rpc.ErrBlockNotFound.Code
+}
@@ -0,0 +1,191 @@
+package follower
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeChain is a RpcProvider serving a scripted chain, on which forks can be simulated.
+// The methods not used by the follower are left to the embedded nil interface.
+type fakeChain struct {
+	rpc.RpcProvider
+	blocks []*rpc.BlockWithReceipts
+	forks  uint64
+}
+
+// newFakeChain creates a chain of the given number of blocks, accepted on L1 up to l1Head.
+func newFakeChain(length int, l1Head int) *fakeChain {
+	chain := &fakeChain{}
+	chain.extend(length)
+	for i := 0; i <= l1Head && i < length; i++ {
+		chain.blocks[i].BlockStatus = rpc.BlockStatus_AcceptedOnL1
+	}
+	return chain
+}
+
+// extend adds n blocks on top of the chain.
+func (c *fakeChain) extend(n int) {
+	for i := 0; i < n; i++ {
+		number := uint64(len(c.blocks))
+		parent := &felt.Zero
+		if number > 0 {
+			parent = c.blocks[number-1].BlockHash
+		}
+		block := &rpc.BlockWithReceipts{BlockStatus: rpc.BlockStatus_AcceptedOnL2}
+		block.BlockNumber = number
+		block.BlockHash = new(felt.Felt).SetUint64(c.forks<<32 | number)
+		block.ParentHash = parent
+		c.blocks = append(c.blocks, block)
+	}
+}
+
+// fork replaces the blocks from the given number with n new blocks.
+func (c *fakeChain) fork(from uint64, n int) {
+	c.forks++
+	c.blocks = c.blocks[:from]
+	c.extend(n)
+}
+
+func (c *fakeChain) BlockHashAndNumber(ctx context.Context) (*rpc.BlockHashAndNumberOutput, error) {
+	head := c.blocks[len(c.blocks)-1]
+	return &rpc.BlockHashAndNumberOutput{BlockNumber: head.BlockNumber, BlockHash: head.BlockHash}, nil
+}
+
+func (c *fakeChain) BlockWithReceipts(ctx context.Context, blockID rpc.BlockID) (interface{}, error) {
+	if blockID.Number == nil || *blockID.Number >= uint64(len(c.blocks)) {
+		return nil, rpc.ErrBlockNotFound
+	}
+	return c.blocks[*blockID.Number], nil
+}
+
+func (c *fakeChain) BlockWithTxHashes(ctx context.Context, blockID rpc.BlockID) (interface{}, error) {
+	if blockID.Number == nil || *blockID.Number >= uint64(len(c.blocks)) {
+		return nil, rpc.ErrBlockNotFound
+	}
+	block := c.blocks[*blockID.Number]
+	return &rpc.BlockTxHashes{BlockHeader: block.BlockHeader, Status: block.BlockStatus}, nil
+}
+
+// recorder is a Handler recording the applied blocks, removing the reverted ones.
+type recorder struct {
+	applied  []BlockRef
+	reverted []BlockRef
+}
+
+func (r *recorder) BlockApplied(ctx context.Context, block *rpc.BlockWithReceipts) error {
+	r.applied = append(r.applied, BlockRef{BlockNumber: block.BlockNumber, BlockHash: block.BlockHash})
+	return nil
+}
+
+func (r *recorder) BlockReverted(ctx context.Context, block BlockRef) error {
+	last := r.applied[len(r.applied)-1]
+	if last.BlockNumber != block.BlockNumber || !last.BlockHash.Equal(block.BlockHash) {
+		return errors.New("reverted a block that is not the last applied one")
+	}
+	r.applied = r.applied[:len(r.applied)-1]
+	r.reverted = append(r.reverted, block)
+	return nil
+}
+
+// requireFollows checks that the applied blocks are the blocks of the chain from start to the given number.
+func requireFollows(t *testing.T, chain *fakeChain, applied []BlockRef, start, to uint64) {
+	require.Len(t, applied, int(to-start+1))
+	for i, ref := range applied {
+		require.Equal(t, start+uint64(i), ref.BlockNumber)
+		require.Equal(t, chain.blocks[ref.BlockNumber].BlockHash, ref.BlockHash)
+	}
+}
+
+func TestChainFollower(t *testing.T) {
+	type testSetType struct {
+		Options          Options
+		ForkAt           uint64
+		ForkLength       int
+		ExpectedReverted int
+		ExpectedErr      error
+	}
+	testSet := []testSetType{
+		{
+			// a reorg of the last 3 blocks, replaced by a longer fork
+			Options:          Options{StartBlock: 2},
+			ForkAt:           7,
+			ForkLength:       5,
+			ExpectedReverted: 3,
+		},
+		{
+			// a reorg to a fork with fewer blocks
+			Options:          Options{},
+			ForkAt:           8,
+			ForkLength:       1,
+			ExpectedReverted: 2,
+		},
+		{
+			// the reorged blocks are not confirmed yet
+			Options:          Options{Confirmations: 3},
+			ForkAt:           8,
+			ForkLength:       4,
+			ExpectedReverted: 0,
+		},
+		{
+			// a reorg deeper than the kept blocks
+			Options:     Options{MaxReorgDepth: 2},
+			ForkAt:      5,
+			ForkLength:  6,
+			ExpectedErr: ErrReorgTooDeep,
+		},
+	}
+
+	for _, test := range testSet {
+		chain := newFakeChain(10, -1)
+		handler := &recorder{}
+		follower := NewChainFollower(chain, handler, NewMemoryCheckpointStore(), test.Options)
+
+		require.NoError(t, follower.Sync(context.Background()))
+		requireFollows(t, chain, handler.applied, test.Options.StartBlock, 9-test.Options.Confirmations)
+
+		chain.fork(test.ForkAt, test.ForkLength)
+		err := follower.Sync(context.Background())
+		if test.ExpectedErr != nil {
+			require.ErrorIs(t, err, test.ExpectedErr)
+			continue
+		}
+		require.NoError(t, err)
+		require.Len(t, handler.reverted, test.ExpectedReverted)
+		requireFollows(t, chain, handler.applied, test.Options.StartBlock, uint64(len(chain.blocks)-1)-test.Options.Confirmations)
+	}
+}
+
+func TestChainFollowerResume(t *testing.T) {
+	chain := newFakeChain(5, -1)
+	store := NewMemoryCheckpointStore()
+	handler := &recorder{}
+	require.NoError(t, NewChainFollower(chain, handler, store, Options{}).Sync(context.Background()))
+
+	// a new follower resumes from the checkpoint, and detects the reorg of the blocks applied by the first one
+	chain.fork(3, 4)
+	require.NoError(t, NewChainFollower(chain, handler, store, Options{}).Sync(context.Background()))
+	require.Len(t, handler.reverted, 2)
+	requireFollows(t, chain, handler.applied, 0, 6)
+
+	checkpoint, err := store.Load(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, handler.applied, checkpoint.Blocks)
+}
+
+func TestChainFollowerFinality(t *testing.T) {
+	chain := newFakeChain(10, 4)
+	handler := &recorder{}
+	follower := NewChainFollower(chain, handler, NewMemoryCheckpointStore(), Options{Finality: rpc.BlockStatus_AcceptedOnL1})
+
+	require.NoError(t, follower.Sync(context.Background()))
+	requireFollows(t, chain, handler.applied, 0, 4)
+
+	chain.blocks[5].BlockStatus = rpc.BlockStatus_AcceptedOnL1
+	require.NoError(t, follower.Sync(context.Background()))
+	requireFollows(t, chain, handler.applied, 0, 5)
+}
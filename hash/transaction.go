@@ -0,0 +1,495 @@
+package hash
+
+import (
+	"errors"
+
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+var (
+	ErrNotAllParametersSet = errors.New("not all neccessary parameters have been set")
+	ErrTxnTypeUnSupported  = errors.New("unsupported transction type")
+)
+
+var (
+	PREFIX_INVOKE         = new(felt.Felt).SetBytes([]byte("invoke"))
+	PREFIX_DECLARE        = new(felt.Felt).SetBytes([]byte("declare"))
+	PREFIX_DEPLOY         = new(felt.Felt).SetBytes([]byte("deploy"))
+	PREFIX_DEPLOY_ACCOUNT = new(felt.Felt).SetBytes([]byte("deploy_account"))
+	PREFIX_L1_HANDLER     = new(felt.Felt).SetBytes([]byte("l1_handler"))
+)
+
+// TransactionHash calculates the hash of a transaction of any type and version, such as the
+// transactions returned by BlockWithTxs, which can then be checked against their TransactionHash.
+// The query versions, with the 2^128 offset, are hashed with their own version.
+//
+// Parameters:
+// - tx: the transaction, or its Block* wrapper holding its hash
+// - chainID: the id of the chain of the transaction
+// Returns:
+// - *felt.Felt: the transaction hash
+// - error: ErrTxnTypeUnSupported for an unknown type, or an error if any
+func TransactionHash(tx rpc.Transaction, chainID *felt.Felt) (*felt.Felt, error) {
+	switch txn := tx.(type) {
+	case rpc.BlockInvokeTxnV0:
+		return TransactionHashInvoke(txn.InvokeTxnV0, chainID)
+	case rpc.BlockInvokeTxnV1:
+		return TransactionHashInvoke(txn.InvokeTxnV1, chainID)
+	case rpc.BlockInvokeTxnV3:
+		return TransactionHashInvoke(txn.InvokeTxnV3, chainID)
+	case rpc.BlockDeclareTxnV0:
+		return TransactionHashDeclare(txn.DeclareTxnV0, chainID)
+	case rpc.BlockDeclareTxnV1:
+		return TransactionHashDeclare(txn.DeclareTxnV1, chainID)
+	case rpc.BlockDeclareTxnV2:
+		return TransactionHashDeclare(txn.DeclareTxnV2, chainID)
+	case rpc.BlockDeclareTxnV3:
+		return TransactionHashDeclare(txn.DeclareTxnV3, chainID)
+	case rpc.BlockDeployTxn:
+		return TransactionHashDeploy(txn.DeployTxn, chainID)
+	case rpc.BlockDeployAccountTxn:
+		return TransactionHash(txn.DeployAccountTxn, chainID)
+	case rpc.BlockDeployAccountTxnV3:
+		return TransactionHash(txn.DeployAccountTxnV3, chainID)
+	case rpc.BlockL1HandlerTxn:
+		return TransactionHashL1Handler(txn.L1HandlerTxn, chainID)
+	case rpc.InvokeTxnV0, rpc.InvokeTxnV1, rpc.InvokeTxnV3:
+		return TransactionHashInvoke(txn, chainID)
+	case rpc.DeclareTxnV0, rpc.DeclareTxnV1, rpc.DeclareTxnV2, rpc.DeclareTxnV3:
+		return TransactionHashDeclare(txn, chainID)
+	case rpc.DeployTxn:
+		return TransactionHashDeploy(txn, chainID)
+	case rpc.DeployAccountTxn:
+		contractAddress, err := contracts.PrecomputeAddress(&felt.Zero, txn.ContractAddressSalt, txn.ClassHash, txn.ConstructorCalldata)
+		if err != nil {
+			return nil, err
+		}
+		return TransactionHashDeployAccount(txn, contractAddress, chainID)
+	case rpc.DeployAccountTxnV3:
+		contractAddress, err := contracts.PrecomputeAddress(&felt.Zero, txn.ContractAddressSalt, txn.ClassHash, txn.ConstructorCalldata)
+		if err != nil {
+			return nil, err
+		}
+		return TransactionHashDeployAccount(txn, contractAddress, chainID)
+	case rpc.L1HandlerTxn:
+		return TransactionHashL1Handler(txn, chainID)
+	}
+	return nil, ErrTxnTypeUnSupported
+}
+
+// TransactionHashInvoke calculates the transaction hash of an invoke transaction.
+//
+// Parameters:
+// - tx: the transaction, an rpc.InvokeTxnV0, rpc.InvokeTxnV1 or rpc.InvokeTxnV3
+// - chainID: the id of the chain of the transaction
+// Returns:
+// - *felt.Felt: the transaction hash
+// - error: ErrNotAllParametersSet if a field is missing, ErrTxnTypeUnSupported for another type, or an error if any
+func TransactionHashInvoke(tx rpc.InvokeTxnType, chainID *felt.Felt) (*felt.Felt, error) {
+
+	// https://docs.starknet.io/documentation/architecture_and_concepts/Network_Architecture/transactions/#v0_hash_calculation
+	switch txn := tx.(type) {
+	case rpc.InvokeTxnV0:
+		if txn.Version == "" || len(txn.Calldata) == 0 || txn.MaxFee == nil || txn.EntryPointSelector == nil {
+			return nil, ErrNotAllParametersSet
+		}
+
+		calldataHash, err := ComputeHashOnElementsFelt(txn.Calldata)
+		if err != nil {
+			return nil, err
+		}
+
+		txnVersionFelt, err := new(felt.Felt).SetString(string(txn.Version))
+		if err != nil {
+			return nil, err
+		}
+		return CalculateTransactionHashCommon(
+			PREFIX_INVOKE,
+			txnVersionFelt,
+			txn.ContractAddress,
+			txn.EntryPointSelector,
+			calldataHash,
+			txn.MaxFee,
+			chainID,
+			[]*felt.Felt{},
+		)
+
+	case rpc.InvokeTxnV1:
+		if txn.Version == "" || len(txn.Calldata) == 0 || txn.Nonce == nil || txn.MaxFee == nil || txn.SenderAddress == nil {
+			return nil, ErrNotAllParametersSet
+		}
+
+		calldataHash, err := ComputeHashOnElementsFelt(txn.Calldata)
+		if err != nil {
+			return nil, err
+		}
+		txnVersionFelt, err := new(felt.Felt).SetString(string(txn.Version))
+		if err != nil {
+			return nil, err
+		}
+		return CalculateTransactionHashCommon(
+			PREFIX_INVOKE,
+			txnVersionFelt,
+			txn.SenderAddress,
+			&felt.Zero,
+			calldataHash,
+			txn.MaxFee,
+			chainID,
+			[]*felt.Felt{txn.Nonce},
+		)
+	case rpc.InvokeTxnV3:
+		// https://github.com/starknet-io/SNIPs/blob/main/SNIPS/snip-8.md#protocol-changes
+		if txn.Version == "" || txn.ResourceBounds == (rpc.ResourceBoundsMapping{}) || len(txn.Calldata) == 0 || txn.Nonce == nil || txn.SenderAddress == nil || txn.PayMasterData == nil || txn.AccountDeploymentData == nil {
+			return nil, ErrNotAllParametersSet
+		}
+
+		txnVersionFelt, err := new(felt.Felt).SetString(string(txn.Version))
+		if err != nil {
+			return nil, err
+		}
+		DAUint64, err := dataAvailabilityMode(txn.FeeMode, txn.NonceDataMode)
+		if err != nil {
+			return nil, err
+		}
+		tipUint64, err := txn.Tip.ToUint64()
+		if err != nil {
+			return nil, err
+		}
+		tipAndResourceHash, err := tipAndResourcesHash(tipUint64, txn.ResourceBounds)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.PoseidonArray(
+			PREFIX_INVOKE,
+			txnVersionFelt,
+			txn.SenderAddress,
+			tipAndResourceHash,
+			crypto.PoseidonArray(txn.PayMasterData...),
+			chainID,
+			txn.Nonce,
+			new(felt.Felt).SetUint64(DAUint64),
+			crypto.PoseidonArray(txn.AccountDeploymentData...),
+			crypto.PoseidonArray(txn.Calldata...),
+		), nil
+	}
+	return nil, ErrTxnTypeUnSupported
+}
+
+// TransactionHashDeclare calculates the transaction hash of a declare transaction.
+//
+// Parameters:
+// - tx: the transaction, an rpc.DeclareTxnV0, rpc.DeclareTxnV1, rpc.DeclareTxnV2 or rpc.DeclareTxnV3
+// - chainID: the id of the chain of the transaction
+// Returns:
+// - *felt.Felt: the transaction hash
+// - error: ErrNotAllParametersSet if a field is missing, ErrTxnTypeUnSupported for another type, or an error if any
+func TransactionHashDeclare(tx rpc.DeclareTxnType, chainID *felt.Felt) (*felt.Felt, error) {
+
+	switch txn := tx.(type) {
+	case rpc.DeclareTxnV0:
+		if txn.SenderAddress == nil || txn.Version == "" || txn.ClassHash == nil || txn.MaxFee == nil {
+			return nil, ErrNotAllParametersSet
+		}
+
+		// the version 0 has no calldata and no nonce, the class hash is the only additional data
+		calldataHash, err := ComputeHashOnElementsFelt([]*felt.Felt{})
+		if err != nil {
+			return nil, err
+		}
+
+		txnVersionFelt, err := new(felt.Felt).SetString(string(txn.Version))
+		if err != nil {
+			return nil, err
+		}
+		return CalculateTransactionHashCommon(
+			PREFIX_DECLARE,
+			txnVersionFelt,
+			txn.SenderAddress,
+			&felt.Zero,
+			calldataHash,
+			txn.MaxFee,
+			chainID,
+			[]*felt.Felt{txn.ClassHash},
+		)
+	case rpc.DeclareTxnV1:
+		if txn.SenderAddress == nil || txn.Version == "" || txn.ClassHash == nil || txn.MaxFee == nil || txn.Nonce == nil {
+			return nil, ErrNotAllParametersSet
+		}
+
+		calldataHash, err := ComputeHashOnElementsFelt([]*felt.Felt{txn.ClassHash})
+		if err != nil {
+			return nil, err
+		}
+
+		txnVersionFelt, err := new(felt.Felt).SetString(string(txn.Version))
+		if err != nil {
+			return nil, err
+		}
+		return CalculateTransactionHashCommon(
+			PREFIX_DECLARE,
+			txnVersionFelt,
+			txn.SenderAddress,
+			&felt.Zero,
+			calldataHash,
+			txn.MaxFee,
+			chainID,
+			[]*felt.Felt{txn.Nonce},
+		)
+	case rpc.DeclareTxnV2:
+		if txn.CompiledClassHash == nil || txn.SenderAddress == nil || txn.Version == "" || txn.ClassHash == nil || txn.MaxFee == nil || txn.Nonce == nil {
+			return nil, ErrNotAllParametersSet
+		}
+
+		calldataHash, err := ComputeHashOnElementsFelt([]*felt.Felt{txn.ClassHash})
+		if err != nil {
+			return nil, err
+		}
+
+		txnVersionFelt, err := new(felt.Felt).SetString(string(txn.Version))
+		if err != nil {
+			return nil, err
+		}
+		return CalculateTransactionHashCommon(
+			PREFIX_DECLARE,
+			txnVersionFelt,
+			txn.SenderAddress,
+			&felt.Zero,
+			calldataHash,
+			txn.MaxFee,
+			chainID,
+			[]*felt.Felt{txn.Nonce, txn.CompiledClassHash},
+		)
+	case rpc.DeclareTxnV3:
+		// https://github.com/starknet-io/SNIPs/blob/main/SNIPS/snip-8.md#protocol-changes
+		if txn.Version == "" || txn.ResourceBounds == (rpc.ResourceBoundsMapping{}) || txn.Nonce == nil || txn.SenderAddress == nil || txn.PayMasterData == nil || txn.AccountDeploymentData == nil ||
+			txn.ClassHash == nil || txn.CompiledClassHash == nil {
+			return nil, ErrNotAllParametersSet
+		}
+
+		txnVersionFelt, err := new(felt.Felt).SetString(string(txn.Version))
+		if err != nil {
+			return nil, err
+		}
+		DAUint64, err := dataAvailabilityMode(txn.FeeMode, txn.NonceDataMode)
+		if err != nil {
+			return nil, err
+		}
+		tipUint64, err := txn.Tip.ToUint64()
+		if err != nil {
+			return nil, err
+		}
+
+		tipAndResourceHash, err := tipAndResourcesHash(tipUint64, txn.ResourceBounds)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.PoseidonArray(
+			PREFIX_DECLARE,
+			txnVersionFelt,
+			txn.SenderAddress,
+			tipAndResourceHash,
+			crypto.PoseidonArray(txn.PayMasterData...),
+			chainID,
+			txn.Nonce,
+			new(felt.Felt).SetUint64(DAUint64),
+			crypto.PoseidonArray(txn.AccountDeploymentData...),
+			txn.ClassHash,
+			txn.CompiledClassHash,
+		), nil
+	}
+
+	return nil, ErrTxnTypeUnSupported
+}
+
+// TransactionHashDeployAccount calculates the transaction hash of a deploy account transaction.
+//
+// Parameters:
+// - tx: the transaction, an rpc.DeployAccountTxn or rpc.DeployAccountTxnV3
+// - contractAddress: the address of the deployed account
+// - chainID: the id of the chain of the transaction
+// Returns:
+// - *felt.Felt: the transaction hash
+// - error: ErrNotAllParametersSet if a field is missing, ErrTxnTypeUnSupported for another type, or an error if any
+func TransactionHashDeployAccount(tx rpc.DeployAccountType, contractAddress, chainID *felt.Felt) (*felt.Felt, error) {
+
+	// https://docs.starknet.io/documentation/architecture_and_concepts/Network_Architecture/transactions/#deploy_account_transaction
+	switch txn := tx.(type) {
+	case rpc.DeployAccountTxn:
+		calldata := []*felt.Felt{txn.ClassHash, txn.ContractAddressSalt}
+		calldata = append(calldata, txn.ConstructorCalldata...)
+		calldataHash, err := ComputeHashOnElementsFelt(calldata)
+		if err != nil {
+			return nil, err
+		}
+
+		versionFelt, err := new(felt.Felt).SetString(string(txn.Version))
+		if err != nil {
+			return nil, err
+		}
+
+		// https://docs.starknet.io/documentation/architecture_and_concepts/Network_Architecture/transactions/#deploy_account_hash_calculation
+		return CalculateTransactionHashCommon(
+			PREFIX_DEPLOY_ACCOUNT,
+			versionFelt,
+			contractAddress,
+			&felt.Zero,
+			calldataHash,
+			txn.MaxFee,
+			chainID,
+			[]*felt.Felt{txn.Nonce},
+		)
+	case rpc.DeployAccountTxnV3:
+		if txn.Version == "" || txn.ResourceBounds == (rpc.ResourceBoundsMapping{}) || txn.Nonce == nil || txn.PayMasterData == nil {
+			return nil, ErrNotAllParametersSet
+		}
+
+		txnVersionFelt, err := new(felt.Felt).SetString(string(txn.Version))
+		if err != nil {
+			return nil, err
+		}
+		DAUint64, err := dataAvailabilityMode(txn.FeeMode, txn.NonceDataMode)
+		if err != nil {
+			return nil, err
+		}
+		tipUint64, err := txn.Tip.ToUint64()
+		if err != nil {
+			return nil, err
+		}
+		tipAndResourceHash, err := tipAndResourcesHash(tipUint64, txn.ResourceBounds)
+		if err != nil {
+			return nil, err
+		}
+		// https://docs.starknet.io/documentation/architecture_and_concepts/Network_Architecture/transactions/#deploy_account_hash_calculation
+		return crypto.PoseidonArray(
+			PREFIX_DEPLOY_ACCOUNT,
+			txnVersionFelt,
+			contractAddress,
+			tipAndResourceHash,
+			crypto.PoseidonArray(txn.PayMasterData...),
+			chainID,
+			txn.Nonce,
+			new(felt.Felt).SetUint64(DAUint64),
+			crypto.PoseidonArray(txn.ConstructorCalldata...),
+			txn.ClassHash,
+			txn.ContractAddressSalt,
+		), nil
+	}
+	return nil, ErrTxnTypeUnSupported
+}
+
+// TransactionHashL1Handler calculates the transaction hash of a L1 handler transaction, the
+// transaction executing a message sent from L1.
+//
+// Parameters:
+// - tx: the transaction
+// - chainID: the id of the chain of the transaction
+// Returns:
+// - *felt.Felt: the transaction hash
+// - error: ErrNotAllParametersSet if a field is missing, or an error if any
+func TransactionHashL1Handler(tx rpc.L1HandlerTxn, chainID *felt.Felt) (*felt.Felt, error) {
+	if tx.Version == "" || tx.Nonce == "" || tx.ContractAddress == nil || tx.EntryPointSelector == nil {
+		return nil, ErrNotAllParametersSet
+	}
+
+	calldataHash, err := ComputeHashOnElementsFelt(tx.Calldata)
+	if err != nil {
+		return nil, err
+	}
+	txnVersionFelt, err := new(felt.Felt).SetString(string(tx.Version))
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := new(felt.Felt).SetString(tx.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	// the L1 handler transactions pay no fee on L2, their max fee is 0
+	return CalculateTransactionHashCommon(
+		PREFIX_L1_HANDLER,
+		txnVersionFelt,
+		tx.ContractAddress,
+		tx.EntryPointSelector,
+		calldataHash,
+		&felt.Zero,
+		chainID,
+		[]*felt.Felt{nonce},
+	)
+}
+
+// TransactionHashDeploy calculates the transaction hash of a deploy transaction, the deprecated
+// transaction deploying a contract without an account.
+//
+// Parameters:
+// - tx: the transaction
+// - chainID: the id of the chain of the transaction
+// Returns:
+// - *felt.Felt: the transaction hash
+// - error: ErrNotAllParametersSet if a field is missing, or an error if any
+func TransactionHashDeploy(tx rpc.DeployTxn, chainID *felt.Felt) (*felt.Felt, error) {
+	if tx.Version == "" || tx.ClassHash == nil || tx.ContractAddressSalt == nil {
+		return nil, ErrNotAllParametersSet
+	}
+
+	// the contracts deployed by a deploy transaction have no deployer
+	contractAddress, err := contracts.PrecomputeAddress(&felt.Zero, tx.ContractAddressSalt, tx.ClassHash, tx.ConstructorCalldata)
+	if err != nil {
+		return nil, err
+	}
+	calldataHash, err := ComputeHashOnElementsFelt(tx.ConstructorCalldata)
+	if err != nil {
+		return nil, err
+	}
+	txnVersionFelt, err := new(felt.Felt).SetString(string(tx.Version))
+	if err != nil {
+		return nil, err
+	}
+	return CalculateTransactionHashCommon(
+		PREFIX_DEPLOY,
+		txnVersionFelt,
+		contractAddress,
+		utils.GetSelectorFromNameFelt("constructor"),
+		calldataHash,
+		&felt.Zero,
+		chainID,
+		[]*felt.Felt{},
+	)
+}
+
+func tipAndResourcesHash(tip uint64, resourceBounds rpc.ResourceBoundsMapping) (*felt.Felt, error) {
+	l1Bytes, err := resourceBounds.L1Gas.Bytes(rpc.ResourceL1Gas)
+	if err != nil {
+		return nil, err
+	}
+	l2Bytes, err := resourceBounds.L2Gas.Bytes(rpc.ResourceL2Gas)
+	if err != nil {
+		return nil, err
+	}
+	l1Bounds := new(felt.Felt).SetBytes(l1Bytes)
+	l2Bounds := new(felt.Felt).SetBytes(l2Bytes)
+	if resourceBounds.L1DataGas == nil {
+		return crypto.PoseidonArray(new(felt.Felt).SetUint64(tip), l1Bounds, l2Bounds), nil
+	}
+	// the transactions of Starknet v0.13.4 and later also sign the bounds of the L1 data gas
+	l1DataBytes, err := resourceBounds.L1DataGas.Bytes(rpc.ResourceL1DataGas)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.PoseidonArray(new(felt.Felt).SetUint64(tip), l1Bounds, l2Bounds, new(felt.Felt).SetBytes(l1DataBytes)), nil
+}
+
+func dataAvailabilityMode(feeDAMode, nonceDAMode rpc.DataAvailabilityMode) (uint64, error) {
+	const dataAvailabilityModeBits = 32
+	fee64, err := feeDAMode.UInt64()
+	if err != nil {
+		return 0, err
+	}
+	nonce64, err := nonceDAMode.UInt64()
+	if err != nil {
+		return 0, err
+	}
+	return fee64 + nonce64<<dataAvailabilityModeBits, nil
+}
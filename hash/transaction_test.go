@@ -0,0 +1,238 @@
+package hash_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/hash"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTransactionHash tests that TransactionHash gives the hash of the transactions of every
+// type, checked against transactions of the networks.
+//
+// Parameters:
+// - t: A testing.T object used for running the test and reporting any failures.
+// Returns:
+//
+//	none
+func TestTransactionHash(t *testing.T) {
+	mainnet := new(felt.Felt).SetBytes([]byte("SN_MAIN"))
+	goerli := new(felt.Felt).SetBytes([]byte("SN_GOERLI"))
+	sepolia := new(felt.Felt).SetBytes([]byte("SN_SEPOLIA"))
+
+	type testSetType struct {
+		Txn          rpc.Transaction
+		ChainID      *felt.Felt
+		ExpectedHash *felt.Felt
+	}
+	testSet := []testSetType{
+		{
+			// https://voyager.online/tx/0x218adbb5aea7985d67fe49b45d44a991380b63db41622f9f4adc36274d02190
+			Txn: rpc.L1HandlerTxn{
+				Type:    rpc.TransactionType_L1Handler,
+				Version: rpc.L1HandlerTxnVersionV0,
+				Nonce:   "0x1654d",
+				FunctionCall: rpc.FunctionCall{
+					ContractAddress:    utils.TestHexToFelt(t, "0x73314940630fd6dcda0d772d4c972c4e0a9946bef9dabf4ef84eda8ef542b82"),
+					EntryPointSelector: utils.TestHexToFelt(t, "0x2d757788a8d8d6f21d1cd40bce38a8222d70654214e96ff95d8086e684fbee5"),
+					Calldata: utils.TestHexArrToFelt(t, []string{
+						"0xae0ee0a63a2ce6baeeffe56e7714fb4efe48d419",
+						"0x218559e75713ca564d6eaf043b73388e9ac7c2f459ef8905988052051d3ef5e",
+						"0x2386f26fc10000",
+						"0x0",
+					}),
+				},
+			},
+			ChainID:      mainnet,
+			ExpectedHash: utils.TestHexToFelt(t, "0x218adbb5aea7985d67fe49b45d44a991380b63db41622f9f4adc36274d02190"),
+		},
+		{
+			// https://voyager.online/tx/0x222f8902d1eeea76fa2642a90e2411bfd71cffb299b3a299029e1937fab3fe4
+			Txn: rpc.DeclareTxnV0{
+				Type:          rpc.TransactionType_Declare,
+				Version:       rpc.TransactionV0,
+				SenderAddress: utils.TestHexToFelt(t, "0x1"),
+				MaxFee:        utils.TestHexToFelt(t, "0x0"),
+				Signature:     []*felt.Felt{},
+				ClassHash:     utils.TestHexToFelt(t, "0x2760f25d5a4fb2bdde5f561fd0b44a3dee78c28903577d37d669939d97036a0"),
+			},
+			ChainID:      mainnet,
+			ExpectedHash: utils.TestHexToFelt(t, "0x222f8902d1eeea76fa2642a90e2411bfd71cffb299b3a299029e1937fab3fe4"),
+		},
+		{
+			// https://voyager.online/tx/0x6486c6303dba2f364c684a2e9609211c5b8e417e767f37b527cda51e776e6f0
+			Txn: rpc.DeployTxn{
+				Type:                rpc.TransactionType_Deploy,
+				Version:             rpc.TransactionV0,
+				ClassHash:           utils.TestHexToFelt(t, "0x46f844ea1a3b3668f81d38b5c1bd55e816e0373802aefe732138628f0133486"),
+				ContractAddressSalt: utils.TestHexToFelt(t, "0x74dc2fe193daf1abd8241b63329c1123214842b96ad7fd003d25512598a956b"),
+				ConstructorCalldata: utils.TestHexArrToFelt(t, []string{
+					"0x6d706cfbac9b8262d601c38251c5fbe0497c3a96cc91a92b08d91b61d9e70c4",
+					"0x79dc0da7c54b95f10aa182ad0a46400db63156920adb65eca2654c0945a463",
+					"0x2",
+					"0x6658165b4984816ab189568637bedec5aa0a18305909c7f5726e4a16e3afef6",
+					"0x6b648b36b074a91eee55730f5f5e075ec19c0a8f9ffb0903cefeee93b6ff328",
+				}),
+			},
+			ChainID:      mainnet,
+			ExpectedHash: utils.TestHexToFelt(t, "0x6486c6303dba2f364c684a2e9609211c5b8e417e767f37b527cda51e776e6f0"),
+		},
+		{
+			// https://testnet.starkscan.co/tx/0x790cc8b131a58a28d8f30a96a12dc37bdccd7b9a9d830f28cae713f0f8a3ac2
+			Txn: rpc.BlockDeployTxn{
+				TransactionHash: utils.TestHexToFelt(t, "0x790cc8b131a58a28d8f30a96a12dc37bdccd7b9a9d830f28cae713f0f8a3ac2"),
+				DeployTxn: rpc.DeployTxn{
+					Type:                rpc.TransactionType_Deploy,
+					Version:             rpc.TransactionV1,
+					ClassHash:           utils.TestHexToFelt(t, "0x1e77e6a83dc4d6fb9cc698b0493f40795ec95595971f61750643a85afc99bcc"),
+					ContractAddressSalt: utils.TestHexToFelt(t, "0x1f0c06480fbbcf9df67a9780fb13265a26f9a428bb38719375f714f61f7d7cb"),
+					ConstructorCalldata: utils.TestHexArrToFelt(t, []string{
+						"0x618b4d6a27e6a97ebb43ddb825c78c5306409658779b6e920e7a00d493e18c",
+						"0x3147ce71f170b879ab4890f52698317d2cd697443e32cca3f1dfc521f473380",
+					}),
+				},
+			},
+			ChainID:      goerli,
+			ExpectedHash: utils.TestHexToFelt(t, "0x790cc8b131a58a28d8f30a96a12dc37bdccd7b9a9d830f28cae713f0f8a3ac2"),
+		},
+		{
+			// https://sepolia.voyager.online/tx/0x28e430cc73715bd1052e8db4f17b053c53dd8174341cba4b1a337b9fecfa8c3
+			Txn: rpc.DeclareTxnV2{
+				Nonce:             utils.TestHexToFelt(t, "0x1"),
+				Type:              rpc.TransactionType_Declare,
+				Version:           rpc.TransactionV2,
+				SenderAddress:     utils.TestHexToFelt(t, "0x0019bd7ebd72368deb5f160f784e21aa46cd09e06a61dc15212456b5597f47b8"),
+				CompiledClassHash: utils.TestHexToFelt(t, "0x017f655f7a639a49ea1d8d56172e99cff8b51f4123b733f0378dfd6378a2cd37"),
+				ClassHash:         utils.TestHexToFelt(t, "0x01f372292df22d28f2d4c5798734421afe9596e6a566b8bc9b7b50e26521b855"),
+				MaxFee:            utils.TestHexToFelt(t, "0x177e06ff6cab2"),
+			},
+			ChainID:      sepolia,
+			ExpectedHash: utils.TestHexToFelt(t, "0x28e430cc73715bd1052e8db4f17b053c53dd8174341cba4b1a337b9fecfa8c3"),
+		},
+		{
+			// https://sepolia.voyager.online/tx/0x66d1d9d50d308a9eb16efedbad208b0672769a545a0b828d357757f444e9188
+			Txn: rpc.DeployAccountTxn{
+				Nonce:               utils.TestHexToFelt(t, "0x0"),
+				Type:                rpc.TransactionType_DeployAccount,
+				MaxFee:              utils.TestHexToFelt(t, "0x1d2109b99cf94"),
+				Version:             rpc.TransactionV1,
+				ClassHash:           utils.TestHexToFelt(t, "0x1e60c8722677cfb7dd8dbea5be86c09265db02cdfe77113e77da7d44c017388"),
+				ContractAddressSalt: utils.TestHexToFelt(t, "0x15d621f9515c6197d3117eb1a25c7a4a669317be8f49831e03fcc00d855352e"),
+				ConstructorCalldata: []*felt.Felt{
+					utils.TestHexToFelt(t, "0x960532cfba33384bbec41aa669727a9c51e995c87e101c86706aaf244f7e4e"),
+				},
+			},
+			ChainID:      sepolia,
+			ExpectedHash: utils.TestHexToFelt(t, "0x66d1d9d50d308a9eb16efedbad208b0672769a545a0b828d357757f444e9188"),
+		},
+		{
+			// https://sepolia.voyager.online/tx/0x4bf28fb0142063f1b9725ae490c6949e6f1842c79b49f7cc674b7e3f5ad4875
+			Txn: rpc.DeployAccountTxnV3{
+				Nonce:   utils.TestHexToFelt(t, "0x0"),
+				Type:    rpc.TransactionType_DeployAccount,
+				Version: rpc.TransactionV3,
+				ResourceBounds: rpc.ResourceBoundsMapping{
+					L1Gas: rpc.ResourceBounds{
+						MaxAmount:       "0x38",
+						MaxPricePerUnit: "0x7cd9b6080b35",
+					},
+					L2Gas: rpc.ResourceBounds{
+						MaxAmount:       "0x0",
+						MaxPricePerUnit: "0x0",
+					},
+				},
+				Tip:           "0x0",
+				PayMasterData: []*felt.Felt{},
+				NonceDataMode: rpc.DAModeL1,
+				FeeMode:       rpc.DAModeL1,
+				ClassHash:     utils.TestHexToFelt(t, "0x29927c8af6bccf3f6fda035981e765a7bdbf18a2dc0d630494f8758aa908e2b"),
+				ConstructorCalldata: utils.TestHexArrToFelt(t, []string{
+					"0x1a09f0001cc46f82b1a805d07c13e235248a44ed13d87f170d7d925e3c86082",
+					"0x0",
+				}),
+				ContractAddressSalt: utils.TestHexToFelt(t, "0x1a09f0001cc46f82b1a805d07c13e235248a44ed13d87f170d7d925e3c86082"),
+			},
+			ChainID:      sepolia,
+			ExpectedHash: utils.TestHexToFelt(t, "0x4bf28fb0142063f1b9725ae490c6949e6f1842c79b49f7cc674b7e3f5ad4875"),
+		},
+	}
+	for _, test := range testSet {
+		hash, err := hash.TransactionHash(test.Txn, test.ChainID)
+		require.NoError(t, err)
+		require.Equal(t, test.ExpectedHash.String(), hash.String())
+	}
+
+	_, err := hash.TransactionHash(rpc.L1HandlerTxn{Version: rpc.L1HandlerTxnVersionV0}, mainnet)
+	require.ErrorIs(t, err, hash.ErrNotAllParametersSet)
+}
+
+// TestTransactionHashBlock tests that TransactionHash gives the hashes of all the transactions of a block.
+//
+// Parameters:
+// - t: A testing.T object used for running the test and reporting any failures.
+// Returns:
+//
+//	none
+func TestTransactionHashBlock(t *testing.T) {
+	// a block of the goerli network
+	content, err := os.ReadFile("../rpc/tests/block/block.json")
+	require.NoError(t, err)
+
+	var block rpc.Block
+	require.NoError(t, json.Unmarshal(content, &block))
+	require.NotEmpty(t, block.Transactions)
+
+	chainID := new(felt.Felt).SetBytes([]byte("SN_GOERLI"))
+	for _, txn := range block.Transactions {
+		hash, err := hash.TransactionHash(txn.(rpc.Transaction), chainID)
+		require.NoError(t, err)
+		require.Equal(t, txn.Hash().String(), hash.String())
+	}
+}
+
+// TestTransactionHashQueryVersion tests that the transactions with a query version, used to
+// estimate the fees, are hashed with their own version.
+//
+// Parameters:
+// - t: A testing.T object used for running the test and reporting any failures.
+// Returns:
+//
+//	none
+func TestTransactionHashQueryVersion(t *testing.T) {
+	chainID := new(felt.Felt).SetBytes([]byte("SN_SEPOLIA"))
+	txn := rpc.InvokeTxnV1{
+		Type:          rpc.TransactionType_Invoke,
+		Version:       rpc.TransactionV1,
+		Nonce:         utils.TestHexToFelt(t, "0x3cf"),
+		MaxFee:        utils.TestHexToFelt(t, "0x1a6f9d0dc5952"),
+		SenderAddress: utils.TestHexToFelt(t, "0x06fb2806bc2564827796e0796144f8104581acdcbcd7721615ad376f70baf87d"),
+		Calldata:      utils.TestHexArrToFelt(t, []string{"0x1", "0x2", "0x3"}),
+	}
+	txnHash, err := hash.TransactionHash(txn, chainID)
+	require.NoError(t, err)
+
+	txn.Version = rpc.TransactionV1WithQueryBit
+	queryHash, err := hash.TransactionHash(txn, chainID)
+	require.NoError(t, err)
+	require.NotEqual(t, txnHash, queryHash)
+
+	calldataHash, err := hash.ComputeHashOnElementsFelt(txn.Calldata)
+	require.NoError(t, err)
+	expectedHash, err := hash.CalculateTransactionHashCommon(
+		hash.PREFIX_INVOKE,
+		utils.TestHexToFelt(t, string(rpc.TransactionV1WithQueryBit)),
+		txn.SenderAddress,
+		&felt.Zero,
+		calldataHash,
+		txn.MaxFee,
+		chainID,
+		[]*felt.Felt{txn.Nonce},
+	)
+	require.NoError(t, err)
+	require.Equal(t, expectedHash, queryHash)
+}
@@ -0,0 +1,153 @@
+package hdwallet
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// HardenedOffset is the first index of the hardened children of a key, written with ' in the paths.
+const HardenedOffset uint32 = 0x80000000
+
+var (
+	ErrInvalidSeed = errors.New("the seed must be 16 to 64 bytes")
+	ErrInvalidPath = errors.New("invalid derivation path")
+	ErrInvalidKey  = errors.New("the derived key is invalid, use the next index")
+
+	masterKeySecret = []byte("Bitcoin seed")
+)
+
+// ExtendedKey is a BIP-32 extended private key on the secp256k1 curve, the key of a node of
+// the tree of the keys of a wallet.
+// https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki
+type ExtendedKey struct {
+	key       *big.Int
+	chainCode []byte
+}
+
+// NewMasterKey derives the master key of a wallet from its seed, such as the seed of a mnemonic.
+//
+// Parameters:
+// - seed: the seed, 16 to 64 bytes
+// Returns:
+// - *ExtendedKey: the master key
+// - error: ErrInvalidSeed for an invalid size, or ErrInvalidKey for the rare seeds without key
+func NewMasterKey(seed []byte) (*ExtendedKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, ErrInvalidSeed
+	}
+	mac := hmac.New(sha512.New, masterKeySecret)
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	key := new(big.Int).SetBytes(sum[:32])
+	if key.Sign() == 0 || key.Cmp(ethcrypto.S256().Params().N) >= 0 {
+		return nil, ErrInvalidKey
+	}
+	return &ExtendedKey{key: key, chainCode: sum[32:]}, nil
+}
+
+// PrivateKey returns the secp256k1 private key of the extended key.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []byte: the 32 bytes private key
+func (k *ExtendedKey) PrivateKey() []byte {
+	return k.key.FillBytes(make([]byte, 32))
+}
+
+// Child derives a child of the extended key.
+//
+// Parameters:
+// - index: the index of the child, from HardenedOffset for a hardened child
+// Returns:
+// - *ExtendedKey: the child key
+// - error: ErrInvalidKey for the rare indexes without key
+func (k *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	var data []byte
+	if index >= HardenedOffset {
+		data = append([]byte{0}, k.PrivateKey()...)
+	} else {
+		privateKey, err := ethcrypto.ToECDSA(k.PrivateKey())
+		if err != nil {
+			return nil, err
+		}
+		data = ethcrypto.CompressPubkey(&privateKey.PublicKey)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	mac := hmac.New(sha512.New, k.chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	n := ethcrypto.S256().Params().N
+	tweak := new(big.Int).SetBytes(sum[:32])
+	if tweak.Cmp(n) >= 0 {
+		return nil, ErrInvalidKey
+	}
+	key := tweak.Add(tweak, k.key)
+	key.Mod(key, n)
+	if key.Sign() == 0 {
+		return nil, ErrInvalidKey
+	}
+	return &ExtendedKey{key: key, chainCode: sum[32:]}, nil
+}
+
+// Derive derives the descendant of the extended key at a path, such as m/44'/9004'/0'/0/0.
+//
+// Parameters:
+// - path: the path from the extended key, starting with m, with ' or h for the hardened indexes
+// Returns:
+// - *ExtendedKey: the descendant key
+// - error: ErrInvalidPath for an invalid path, or ErrInvalidKey for the rare paths without key
+func (k *ExtendedKey) Derive(path string) (*ExtendedKey, error) {
+	indexes, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	key := k
+	for _, index := range indexes {
+		if key, err = key.Child(index); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// ParsePath parses a BIP-32 derivation path, such as m/44'/9004'/0'/0/0.
+//
+// Parameters:
+// - path: the path, starting with m, with ' or h for the hardened indexes
+// Returns:
+// - []uint32: the indexes of the path, from HardenedOffset for the hardened indexes
+// - error: ErrInvalidPath for an invalid path
+func ParsePath(path string) ([]uint32, error) {
+	parts := strings.Split(strings.TrimSpace(path), "/")
+	if parts[0] != "m" {
+		return nil, fmt.Errorf("%w: %q does not start with m", ErrInvalidPath, path)
+	}
+	indexes := make([]uint32, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		hardened := strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h") || strings.HasSuffix(part, "H")
+		if hardened {
+			part = part[:len(part)-1]
+		}
+		index, err := strconv.ParseUint(part, 10, 32)
+		if err != nil || uint32(index) >= HardenedOffset {
+			return nil, fmt.Errorf("%w: invalid index %q in %q", ErrInvalidPath, part, path)
+		}
+		if hardened {
+			index += uint64(HardenedOffset)
+		}
+		indexes = append(indexes, uint32(index))
+	}
+	return indexes, nil
+}
@@ -0,0 +1,221 @@
+package hdwallet
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// EIP2645Purpose is the purpose of the EIP-2645 paths, the first index of the paths of the Stark keys.
+// https://github.com/ethereum/EIPs/blob/master/EIPS/eip-2645.md
+const EIP2645Purpose = 2645
+
+// argentXEthereumPath is the path of the Ethereum key of the mnemonic, the seed of the Stark keys of Argent X.
+const argentXEthereumPath = "m/44'/60'/0'/0/0"
+
+// maxGrindIterations bounds the loop of GrindKey, each iteration failing with a probability below 2^-4.
+const maxGrindIterations = 100000
+
+var (
+	ErrGrindKey = errors.New("no key found while grinding the seed")
+
+	int31Mask = big.NewInt(1<<31 - 1)
+)
+
+// GrindKey turns a secp256k1 private key, or any seed, into a Stark private key, as done by the
+// wallets such as Argent X and Braavos and by starknet.js. It hashes the seed with an increasing
+// index until the hash is below the largest multiple of the order of the Stark curve, so that the
+// key is uniform, and returns the hash modulo the order.
+//
+// Parameters:
+// - seed: the seed, such as the 32 bytes private key of an ExtendedKey
+// Returns:
+// - *big.Int: the Stark private key
+// - error: ErrGrindKey in the unlikely case where no key is found
+func GrindKey(seed []byte) (*big.Int, error) {
+	order := curve.Curve.N
+	sha256Mask := new(big.Int).Lsh(big.NewInt(1), 256)
+	limit := new(big.Int).Sub(sha256Mask, new(big.Int).Mod(sha256Mask, order))
+
+	for i := 0; i <= maxGrindIterations; i++ {
+		// the index is appended as the shortest big endian bytes, one zero byte for 0
+		index := new(big.Int).SetInt64(int64(i)).Bytes()
+		if len(index) == 0 {
+			index = []byte{0}
+		}
+		hash := sha256.Sum256(append(append([]byte{}, seed...), index...))
+		key := new(big.Int).SetBytes(hash[:])
+		if key.Cmp(limit) < 0 {
+			return key.Mod(key, order), nil
+		}
+	}
+	return nil, ErrGrindKey
+}
+
+// EIP2645Path gives the EIP-2645 path of a Stark key,
+// m/2645'/layer'/application'/eth_address_1'/eth_address_2'/index, where the layer and
+// the application are the 31 low bits of the sha256 of their names, and eth_address_1 and
+// eth_address_2 are the 31 low bits and the next 31 bits of the Ethereum address.
+//
+// Parameters:
+// - layer: the name of the layer, such as starkex
+// - application: the name of the application
+// - ethereumAddress: the Ethereum address of the user, as a hex string
+// - index: the index of the key
+// Returns:
+// - string: the path
+// - error: an error if the Ethereum address is not a hex string
+func EIP2645Path(layer, application, ethereumAddress string, index uint32) (string, error) {
+	eth, ok := new(big.Int).SetString(strings.TrimPrefix(strings.ToLower(ethereumAddress), "0x"), 16)
+	if !ok {
+		return "", fmt.Errorf("invalid Ethereum address %q", ethereumAddress)
+	}
+	return fmt.Sprintf("m/%d'/%d'/%d'/%d'/%d'/%d",
+		EIP2645Purpose,
+		int31(sha256Int([]byte(layer))),
+		int31(sha256Int([]byte(application))),
+		int31(eth),
+		int31(new(big.Int).Rsh(eth, 31)),
+		index,
+	), nil
+}
+
+// AccountPath gives the path of the Stark key of the account at an index, m/44'/9004'/0'/0/index,
+// the coin type 9004 being Starknet. Argent X derives this path from its own master seed, not from the
+// seed of the mnemonic: use a wallet of NewArgentXWallet to get the keys of its accounts.
+//
+// Parameters:
+// - index: the index of the account
+// Returns:
+// - string: the path
+func AccountPath(index uint32) string {
+	return fmt.Sprintf("m/44'/9004'/0'/0/%d", index)
+}
+
+// Wallet derives the Stark keys of a hierarchical deterministic wallet, reproducible from its mnemonic.
+type Wallet struct {
+	master *ExtendedKey
+}
+
+// NewWallet creates the wallet of a BIP-39 mnemonic.
+//
+// Parameters:
+// - mnemonic: the mnemonic, such as a mnemonic of NewMnemonic
+// - passphrase: the optional passphrase of the mnemonic, the empty string for none
+// Returns:
+// - *Wallet: the wallet
+// - error: ErrInvalidMnemonic for an invalid mnemonic, or an error if any
+func NewWallet(mnemonic, passphrase string) (*Wallet, error) {
+	seed, err := NewSeed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return NewWalletFromSeed(seed)
+}
+
+// NewArgentXWallet creates the wallet of a mnemonic as Argent X does: the private key of the Ethereum
+// account of the mnemonic, at m/44'/60'/0'/0/0 and without passphrase, is the seed of the wallet, whose
+// AccountPath keys are those of the Argent X accounts.
+//
+// Parameters:
+// - mnemonic: the mnemonic of the Argent X wallet
+// Returns:
+// - *Wallet: the wallet
+// - error: ErrInvalidMnemonic for an invalid mnemonic, or an error if any
+func NewArgentXWallet(mnemonic string) (*Wallet, error) {
+	seed, err := NewSeed(mnemonic, "")
+	if err != nil {
+		return nil, err
+	}
+	master, err := NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	ethereumKey, err := master.Derive(argentXEthereumPath)
+	if err != nil {
+		return nil, err
+	}
+	// the key is passed as a number, without its leading zero bytes
+	return NewWalletFromSeed(new(big.Int).SetBytes(ethereumKey.PrivateKey()).Bytes())
+}
+
+// NewWalletFromSeed creates the wallet of a seed.
+//
+// Parameters:
+// - seed: the seed, 16 to 64 bytes
+// Returns:
+// - *Wallet: the wallet
+// - error: ErrInvalidSeed for an invalid size, or an error if any
+func NewWalletFromSeed(seed []byte) (*Wallet, error) {
+	master, err := NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	return &Wallet{master: master}, nil
+}
+
+// StarkKey derives the secp256k1 key of the wallet at a path and grinds it into a Stark key.
+//
+// Parameters:
+// - path: the path of the key, such as an AccountPath or an EIP2645Path
+// Returns:
+// - *big.Int: the Stark private key
+// - *felt.Felt: the Stark public key, identifying the key in the keystores
+// - error: an error if any
+func (w *Wallet) StarkKey(path string) (*big.Int, *felt.Felt, error) {
+	key, err := w.master.Derive(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	privateKey, err := GrindKey(key.PrivateKey())
+	if err != nil {
+		return nil, nil, err
+	}
+	pubX, _, err := curve.Curve.PrivateToPoint(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return privateKey, utils.BigIntToFelt(pubX), nil
+}
+
+// Keystore derives the Stark keys of the wallet at some paths and puts them in a MemKeystore.
+// The keys can be stored in other keystores with StarkKey, such as with FileKeystore.Import.
+//
+// Parameters:
+// - paths: the paths of the keys
+// Returns:
+// - *account.MemKeystore: the keystore holding the keys
+// - []*felt.Felt: the public keys, in the order of the paths
+// - error: an error if any
+func (w *Wallet) Keystore(paths ...string) (*account.MemKeystore, []*felt.Felt, error) {
+	ks := account.NewMemKeystore()
+	publicKeys := make([]*felt.Felt, len(paths))
+	for i, path := range paths {
+		privateKey, publicKey, err := w.StarkKey(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		ks.Put(publicKey.String(), privateKey)
+		publicKeys[i] = publicKey
+	}
+	return ks, publicKeys, nil
+}
+
+// sha256Int returns the sha256 of some data as an integer.
+func sha256Int(data []byte) *big.Int {
+	hash := sha256.Sum256(data)
+	return new(big.Int).SetBytes(hash[:])
+}
+
+// int31 returns the 31 low bits of an integer.
+func int31(n *big.Int) uint32 {
+	return binary.BigEndian.Uint32(new(big.Int).And(n, int31Mask).FillBytes(make([]byte, 4)))
+}
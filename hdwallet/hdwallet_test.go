@@ -0,0 +1,223 @@
+package hdwallet_test
+
+import (
+	"context"
+	"encoding/hex"
+	"hash/crc32"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/hdwallet"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWordList checks the word list against the checksum of the English word list of BIP-39.
+func TestWordList(t *testing.T) {
+	data, err := os.ReadFile("english.txt")
+	require.NoError(t, err)
+	require.Equal(t, uint32(0xc1dbd296), crc32.ChecksumIEEE(data))
+}
+
+// TestMnemonic tests the mnemonics and their seeds with the test vectors of BIP-39, with the passphrase TREZOR.
+func TestMnemonic(t *testing.T) {
+	type testSetType struct {
+		Entropy  string
+		Mnemonic string
+		Seed     string
+	}
+	testSet := []testSetType{
+		{
+			Entropy:  "00000000000000000000000000000000",
+			Mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			Seed:     "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		},
+		{
+			Entropy:  "7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+			Mnemonic: "legal winner thank year wave sausage worth useful legal winner thank yellow",
+			Seed:     "2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+		},
+		{
+			Entropy:  "808080808080808080808080808080808080808080808080",
+			Mnemonic: "letter advice cage absurd amount doctor acoustic avoid letter advice cage absurd amount doctor acoustic avoid letter always",
+			Seed:     "107d7c02a5aa6f38c58083ff74f04c607c2d2c0ecc55501dadd72d025b751bc27fe913ffb796f841c49b1d33b610cf0e91d3aa239027f5e99fe4ce9e5088cd65",
+		},
+		{
+			Entropy:  "ffffffffffffffffffffffffffffffffffffffffffffffff",
+			Mnemonic: "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo when",
+			Seed:     "0cd6e5d827bb62eb8fc1e262254223817fd068a74b5b449cc2f667c3f1f985a76379b43348d952e2265b4cd129090758b3e3c2c49103b5051aac2eaeb890a528",
+		},
+		{
+			Entropy:  "8080808080808080808080808080808080808080808080808080808080808080",
+			Mnemonic: "letter advice cage absurd amount doctor acoustic avoid letter advice cage absurd amount doctor acoustic avoid letter advice cage absurd amount doctor acoustic bless",
+			Seed:     "c0c519bd0e91a2ed54357d9d1ebef6f5af218a153624cf4f2da911a0ed8f7a09e2ef61af0aca007096df430022f7a2b6fb91661a9589097069720d015e4e982f",
+		},
+	}
+	for _, test := range testSet {
+		entropy, err := hex.DecodeString(test.Entropy)
+		require.NoError(t, err)
+		mnemonic, err := hdwallet.MnemonicFromEntropy(entropy)
+		require.NoError(t, err)
+		require.Equal(t, test.Mnemonic, mnemonic)
+
+		seed, err := hdwallet.NewSeed(test.Mnemonic, "TREZOR")
+		require.NoError(t, err)
+		require.Equal(t, test.Seed, hex.EncodeToString(seed))
+	}
+
+	for _, mnemonic := range []string{
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+		"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo",
+		"legal winner thank year wave sausage worth useful legal winner thanks yellow",
+	} {
+		_, err := hdwallet.NewSeed(mnemonic, "")
+		require.ErrorIs(t, err, hdwallet.ErrInvalidMnemonic, mnemonic)
+	}
+
+	mnemonic, err := hdwallet.NewMnemonic(256)
+	require.NoError(t, err)
+	require.NoError(t, hdwallet.ValidateMnemonic(mnemonic))
+	_, err = hdwallet.NewMnemonic(100)
+	require.ErrorIs(t, err, hdwallet.ErrInvalidEntropy)
+}
+
+// TestExtendedKey tests the derivation of the keys with the test vector 1 of BIP-32.
+func TestExtendedKey(t *testing.T) {
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	require.NoError(t, err)
+	master, err := hdwallet.NewMasterKey(seed)
+	require.NoError(t, err)
+
+	type testSetType struct {
+		Path       string
+		PrivateKey string
+	}
+	testSet := []testSetType{
+		{Path: "m", PrivateKey: "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35"},
+		{Path: "m/0'", PrivateKey: "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{Path: "m/0h/1", PrivateKey: "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368"},
+		{Path: "m/0'/1/2'", PrivateKey: "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca"},
+		{Path: "m/0'/1/2'/2/1000000000", PrivateKey: "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"},
+	}
+	for _, test := range testSet {
+		key, err := master.Derive(test.Path)
+		require.NoError(t, err, test.Path)
+		require.Equal(t, test.PrivateKey, hex.EncodeToString(key.PrivateKey()), test.Path)
+	}
+
+	for _, path := range []string{"", "0/1", "m/x", "m/2147483648", "m/1''"} {
+		_, err := master.Derive(path)
+		require.ErrorIs(t, err, hdwallet.ErrInvalidPath, path)
+	}
+	_, err = hdwallet.NewMasterKey(seed[:8])
+	require.ErrorIs(t, err, hdwallet.ErrInvalidSeed)
+}
+
+// TestGrindKey tests GrindKey and EIP2645Path with the test vectors of starknet.js.
+func TestGrindKey(t *testing.T) {
+	seed, err := hex.DecodeString("86F3E7293141F20A8BAFF320E8EE4ACCB9D4A4BF2B4D295E8CEE784DB46E0519")
+	require.NoError(t, err)
+	key, err := hdwallet.GrindKey(seed)
+	require.NoError(t, err)
+	require.Equal(t, "0x5c8c8683596c732541a59e03007b2d30dbbbb873556fe65b5fb63c16688f941", utils.BigIntToFelt(key).String())
+
+	path, err := hdwallet.EIP2645Path("starkex", "starkdeployement", "0xa4864d977b944315389d1765ffa7e66F74ee8cd7", 0)
+	require.NoError(t, err)
+	require.Equal(t, "m/2645'/579218131'/891216374'/1961790679'/2135936222'/0", path)
+	_, err = hdwallet.EIP2645Path("starkex", "starkdeployement", "0xzz", 0)
+	require.Error(t, err)
+}
+
+// TestWallet tests that the Stark keys of a wallet are reproducible from its mnemonic, and that they sign
+// in a keystore.
+func TestWallet(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	wallet, err := hdwallet.NewWallet(mnemonic, "")
+	require.NoError(t, err)
+	seed, err := hdwallet.NewSeed(mnemonic, "")
+	require.NoError(t, err)
+	master, err := hdwallet.NewMasterKey(seed)
+	require.NoError(t, err)
+
+	paths := []string{hdwallet.AccountPath(0), hdwallet.AccountPath(1)}
+	ks, publicKeys, err := wallet.Keystore(paths...)
+	require.NoError(t, err)
+	require.Len(t, publicKeys, 2)
+	require.NotEqual(t, publicKeys[0], publicKeys[1])
+	for i, path := range paths {
+		key, err := master.Derive(path)
+		require.NoError(t, err)
+		expected, err := hdwallet.GrindKey(key.PrivateKey())
+		require.NoError(t, err)
+		privateKey, publicKey, err := wallet.StarkKey(path)
+		require.NoError(t, err)
+		require.Equal(t, expected, privateKey)
+		require.Equal(t, publicKeys[i], publicKey)
+		require.True(t, privateKey.Cmp(curve.Curve.N) < 0)
+
+		msgHash := big.NewInt(0x1234)
+		r, s, err := ks.Sign(context.Background(), publicKey.String(), msgHash)
+		require.NoError(t, err)
+		pubX, pubY, err := curve.Curve.PrivateToPoint(privateKey)
+		require.NoError(t, err)
+		require.True(t, curve.Curve.Verify(msgHash, r, s, pubX, pubY))
+	}
+
+	// the key of AccountPath(0), computed by an implementation of BIP-39, BIP-32, the grinding of
+	// starknet.js and the Stark curve independent of this package
+	privateKey, publicKey, err := wallet.StarkKey(hdwallet.AccountPath(0))
+	require.NoError(t, err)
+	require.Equal(t, "0x1b8e16cdf31892c56c0370f0e4ca0da096ef4e0c81007b3ba10b11452f8971", utils.BigIntToFelt(privateKey).String())
+	require.Equal(t, "0x5d97a4a9174d9158c3886717a70112c5e60b17318a1d3ae17f563f1cf8292f4", publicKey.String())
+
+	again, err := hdwallet.NewWallet(mnemonic, "")
+	require.NoError(t, err)
+	_, publicKey, err = again.StarkKey(hdwallet.AccountPath(0))
+	require.NoError(t, err)
+	require.Equal(t, publicKeys[0], publicKey)
+	withPassphrase, err := hdwallet.NewWallet(mnemonic, "passphrase")
+	require.NoError(t, err)
+	_, publicKey, err = withPassphrase.StarkKey(hdwallet.AccountPath(0))
+	require.NoError(t, err)
+	require.NotEqual(t, publicKeys[0], publicKey)
+}
+
+// TestArgentXWallet tests that the Argent X wallet derives its keys from the Ethereum key of the mnemonic,
+// checked against the known Ethereum key of the mnemonic.
+func TestArgentXWallet(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	wallet, err := hdwallet.NewArgentXWallet(mnemonic)
+	require.NoError(t, err)
+
+	ethereumKey, err := hex.DecodeString("1ab42cc412b618bdea3a599e3c9bae199ebf030895b039e9db1e30dafb12b727")
+	require.NoError(t, err)
+	expected, err := hdwallet.NewWalletFromSeed(ethereumKey)
+	require.NoError(t, err)
+	mnemonicWallet, err := hdwallet.NewWallet(mnemonic, "")
+	require.NoError(t, err)
+
+	// the key of AccountPath(0), computed by an implementation of BIP-39, BIP-32, the grinding of
+	// starknet.js and the Stark curve independent of this package
+	privateKey, publicKey, err := wallet.StarkKey(hdwallet.AccountPath(0))
+	require.NoError(t, err)
+	require.Equal(t, "0x18a556cbd949d1e6d25ed391bf032559fb6055f321c3e02714f7a6268bff3d1", utils.BigIntToFelt(privateKey).String())
+	require.Equal(t, "0x1f03432e214578b6ac859bd1d282e948a615bef54feaf8da02141d42a5f5fa", publicKey.String())
+
+	for _, index := range []uint32{0, 1} {
+		privateKey, publicKey, err := wallet.StarkKey(hdwallet.AccountPath(index))
+		require.NoError(t, err)
+		expectedKey, expectedPublicKey, err := expected.StarkKey(hdwallet.AccountPath(index))
+		require.NoError(t, err)
+		require.Equal(t, expectedKey, privateKey)
+		require.Equal(t, expectedPublicKey, publicKey)
+
+		_, fromMnemonic, err := mnemonicWallet.StarkKey(hdwallet.AccountPath(index))
+		require.NoError(t, err)
+		require.NotEqual(t, fromMnemonic, publicKey)
+	}
+
+	_, err = hdwallet.NewArgentXWallet("zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo")
+	require.ErrorIs(t, err, hdwallet.ErrInvalidMnemonic)
+}
@@ -0,0 +1,133 @@
+package hdwallet
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+var (
+	ErrInvalidEntropy  = errors.New("the entropy must be 128 to 256 bits, in steps of 32 bits")
+	ErrInvalidMnemonic = errors.New("invalid mnemonic")
+)
+
+// englishWordList is the English word list of BIP-39.
+// https://github.com/bitcoin/bips/blob/master/bip-0039/english.txt
+//
+//go:embed english.txt
+var englishWordList string
+
+var (
+	wordList  = strings.Fields(englishWordList)
+	wordIndex = func() map[string]int {
+		index := make(map[string]int, len(wordList))
+		for i, word := range wordList {
+			index[word] = i
+		}
+		return index
+	}()
+)
+
+// NewMnemonic creates a random BIP-39 mnemonic in English.
+//
+// Parameters:
+// - bits: the size of the entropy of the mnemonic, 128 bits for 12 words up to 256 bits for 24 words
+// Returns:
+// - string: the mnemonic
+// - error: ErrInvalidEntropy for an invalid size, or an error if any
+func NewMnemonic(bits int) (string, error) {
+	if bits < 128 || bits > 256 || bits%32 != 0 {
+		return "", ErrInvalidEntropy
+	}
+	entropy := make([]byte, bits/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", err
+	}
+	return MnemonicFromEntropy(entropy)
+}
+
+// MnemonicFromEntropy encodes an entropy into a BIP-39 mnemonic in English.
+//
+// Parameters:
+// - entropy: the entropy, 16 to 32 bytes, in steps of 4 bytes
+// Returns:
+// - string: the mnemonic
+// - error: ErrInvalidEntropy for an invalid size
+func MnemonicFromEntropy(entropy []byte) (string, error) {
+	bits := len(entropy) * 8
+	if bits < 128 || bits > 256 || bits%32 != 0 {
+		return "", ErrInvalidEntropy
+	}
+	checksumBits := bits / 32
+	checksum := sha256.Sum256(entropy)
+
+	// the entropy followed by the first bits of its hash, read 11 bits at a time
+	data := new(big.Int).SetBytes(entropy)
+	data.Lsh(data, uint(checksumBits))
+	data.Or(data, big.NewInt(int64(checksum[0]>>(8-checksumBits))))
+	words := make([]string, (bits+checksumBits)/11)
+	mask := big.NewInt(1<<11 - 1)
+	for i := len(words) - 1; i >= 0; i-- {
+		words[i] = wordList[new(big.Int).And(data, mask).Int64()]
+		data.Rsh(data, 11)
+	}
+	return strings.Join(words, " "), nil
+}
+
+// ValidateMnemonic checks that a mnemonic is made of 12 to 24 words of the English word list of
+// BIP-39 and that its checksum matches.
+//
+// Parameters:
+// - mnemonic: the mnemonic
+// Returns:
+// - error: ErrInvalidMnemonic for an invalid mnemonic
+func ValidateMnemonic(mnemonic string) error {
+	words := strings.Fields(mnemonic)
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return fmt.Errorf("%w: %d words", ErrInvalidMnemonic, len(words))
+	}
+	data := new(big.Int)
+	for _, word := range words {
+		index, ok := wordIndex[word]
+		if !ok {
+			return fmt.Errorf("%w: unknown word %q", ErrInvalidMnemonic, word)
+		}
+		data.Lsh(data, 11)
+		data.Or(data, big.NewInt(int64(index)))
+	}
+
+	checksumBits := len(words) * 11 / 33
+	checksum := new(big.Int).And(data, big.NewInt(1<<checksumBits-1))
+	entropy := data.Rsh(data, uint(checksumBits)).FillBytes(make([]byte, checksumBits*4))
+	hash := sha256.Sum256(entropy)
+	if checksum.Int64() != int64(hash[0]>>(8-checksumBits)) {
+		return fmt.Errorf("%w: wrong checksum", ErrInvalidMnemonic)
+	}
+	return nil
+}
+
+// NewSeed validates a BIP-39 mnemonic and derives its seed, the seed of the master key of the wallet.
+//
+// The mnemonic and the passphrase are used as is: a passphrase with non-ASCII characters must be in
+// the NFKD normal form, as required by BIP-39.
+//
+// Parameters:
+// - mnemonic: the mnemonic
+// - passphrase: the optional passphrase of the mnemonic, the empty string for none
+// Returns:
+// - []byte: the 64 bytes seed
+// - error: ErrInvalidMnemonic for an invalid mnemonic
+func NewSeed(mnemonic, passphrase string) ([]byte, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	return pbkdf2.Key([]byte(mnemonic), []byte("mnemonic"+passphrase), 2048, 64, sha512.New), nil
+}
@@ -15,6 +15,7 @@ import (
 	time "time"
 
 	felt "github.com/NethermindEth/juno/core/felt"
+	account "github.com/NethermindEth/starknet.go/account"
 	rpc "github.com/NethermindEth/starknet.go/rpc"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -42,6 +43,86 @@ func (m *MockAccountInterface) EXPECT() *MockAccountInterfaceMockRecorder {
 	return m.recorder
 }
 
+// Declare mocks base method.
+func (m *MockAccountInterface) Declare(ctx context.Context, sierraPath, casmPath string, opts ...account.ExecuteOption) (*account.DeclareResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, sierraPath, casmPath}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Declare", varargs...)
+	ret0, _ := ret[0].(*account.DeclareResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Declare indicates an expected call of Declare.
+func (mr *MockAccountInterfaceMockRecorder) Declare(ctx, sierraPath, casmPath any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, sierraPath, casmPath}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Declare", reflect.TypeOf((*MockAccountInterface)(nil).Declare), varargs...)
+}
+
+// DeployViaUDC mocks base method.
+func (m *MockAccountInterface) DeployViaUDC(ctx context.Context, classHash *felt.Felt, constructorCalldata []*felt.Felt, salt *felt.Felt, unique bool, opts ...account.ExecuteOption) (*account.DeployResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, classHash, constructorCalldata, salt, unique}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeployViaUDC", varargs...)
+	ret0, _ := ret[0].(*account.DeployResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeployViaUDC indicates an expected call of DeployViaUDC.
+func (mr *MockAccountInterfaceMockRecorder) DeployViaUDC(ctx, classHash, constructorCalldata, salt, unique any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, classHash, constructorCalldata, salt, unique}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeployViaUDC", reflect.TypeOf((*MockAccountInterface)(nil).DeployViaUDC), varargs...)
+}
+
+// EstimateFeeForCalls mocks base method.
+func (m *MockAccountInterface) EstimateFeeForCalls(ctx context.Context, calls []rpc.FunctionCall, opts ...account.ExecuteOption) (*rpc.FeeEstimate, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, calls}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "EstimateFeeForCalls", varargs...)
+	ret0, _ := ret[0].(*rpc.FeeEstimate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EstimateFeeForCalls indicates an expected call of EstimateFeeForCalls.
+func (mr *MockAccountInterfaceMockRecorder) EstimateFeeForCalls(ctx, calls any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, calls}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateFeeForCalls", reflect.TypeOf((*MockAccountInterface)(nil).EstimateFeeForCalls), varargs...)
+}
+
+// Execute mocks base method.
+func (m *MockAccountInterface) Execute(ctx context.Context, calls []rpc.FunctionCall, opts ...account.ExecuteOption) (*account.ExecuteResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, calls}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Execute", varargs...)
+	ret0, _ := ret[0].(*account.ExecuteResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockAccountInterfaceMockRecorder) Execute(ctx, calls any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, calls}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockAccountInterface)(nil).Execute), varargs...)
+}
+
 // PrecomputeAccountAddress mocks base method.
 func (m *MockAccountInterface) PrecomputeAccountAddress(salt, classHash *felt.Felt, constructorCalldata []*felt.Felt) (*felt.Felt, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,265 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// maxBatchSize is the maximum number of calls sent in a single JSON-RPC batch.
+// Larger batches are split, as most nodes limit the size of the batches they accept.
+const maxBatchSize = 100
+
+// batchCaller is implemented by the clients able to send JSON-RPC batches, such as ethrpc.Client.
+type batchCaller interface {
+	BatchCallContext(ctx context.Context, b []ethrpc.BatchElem) error
+}
+
+// batchCall is a call queued in a Batch.
+type batchCall struct {
+	method    string
+	args      []interface{}
+	decode    func(raw json.RawMessage) (interface{}, error)
+	rpcErrors []*RPCError
+}
+
+// BatchResult is the result of a single call of a Batch. Result holds the
+// same type as the one returned by the equivalent Provider method.
+type BatchResult struct {
+	Result interface{}
+	Err    *RPCError
+}
+
+// BatchValue returns the result of a call of a Batch with its type.
+//
+// Parameters:
+// - result: the result of the call
+// Returns:
+// - T: the result of the call
+// - error: the error of the call, or an error if the result is not a T
+func BatchValue[T any](result BatchResult) (T, error) {
+	var zero T
+	if result.Err != nil {
+		return zero, result.Err
+	}
+	value, ok := result.Result.(T)
+	if !ok {
+		return zero, Err(InternalError, fmt.Sprintf("batch result is %T, not %T", result.Result, zero))
+	}
+	return value, nil
+}
+
+// Batch queues calls to send them to the node in a single JSON-RPC batch.
+// The calls are queued with the chainable methods and sent with Send.
+type Batch struct {
+	provider *Provider
+	ctx      context.Context
+	calls    []batchCall
+}
+
+// Batch creates a new empty Batch.
+//
+// Parameters:
+// - ctx: The context.Context object used to send the batch
+// Returns:
+// - *Batch: the new Batch
+func (provider *Provider) Batch(ctx context.Context) *Batch {
+	return &Batch{provider: provider, ctx: ctx}
+}
+
+// Len returns the number of calls queued in the batch.
+func (b *Batch) Len() int {
+	return len(b.calls)
+}
+
+// add queues a call whose result is decoded into a new T.
+func add[T any](b *Batch, method string, args []interface{}, rpcErrors ...*RPCError) *Batch {
+	b.calls = append(b.calls, batchCall{
+		method: method,
+		args:   args,
+		decode: func(raw json.RawMessage) (interface{}, error) {
+			var v T
+			err := json.Unmarshal(raw, &v)
+			return v, err
+		},
+		rpcErrors: rpcErrors,
+	})
+	return b
+}
+
+// TransactionReceipt queues a starknet_getTransactionReceipt call.
+// Its result is a *TransactionReceiptWithBlockInfo.
+func (b *Batch) TransactionReceipt(transactionHash *felt.Felt) *Batch {
+	return add[*TransactionReceiptWithBlockInfo](b, "starknet_getTransactionReceipt", []interface{}{transactionHash}, ErrHashNotFound)
+}
+
+// TransactionByHash queues a starknet_getTransactionByHash call.
+// Its result is a Transaction.
+func (b *Batch) TransactionByHash(transactionHash *felt.Felt) *Batch {
+	b.calls = append(b.calls, batchCall{
+		method: "starknet_getTransactionByHash",
+		args:   []interface{}{transactionHash},
+		decode: func(raw json.RawMessage) (interface{}, error) {
+			var tx TXN
+			if err := json.Unmarshal(raw, &tx); err != nil {
+				return nil, err
+			}
+			return adaptTransaction(tx)
+		},
+		rpcErrors: []*RPCError{ErrHashNotFound},
+	})
+	return b
+}
+
+// GetTransactionStatus queues a starknet_getTransactionStatus call.
+// Its result is a *TxnStatusResp.
+func (b *Batch) GetTransactionStatus(transactionHash *felt.Felt) *Batch {
+	return add[*TxnStatusResp](b, "starknet_getTransactionStatus", []interface{}{transactionHash}, ErrHashNotFound)
+}
+
+// Nonce queues a starknet_getNonce call.
+// Its result is a *felt.Felt.
+func (b *Batch) Nonce(blockID BlockID, contractAddress *felt.Felt) *Batch {
+	return add[*felt.Felt](b, "starknet_getNonce", []interface{}{blockID, contractAddress}, ErrContractNotFound, ErrBlockNotFound)
+}
+
+// ClassHashAt queues a starknet_getClassHashAt call.
+// Its result is a *felt.Felt.
+func (b *Batch) ClassHashAt(blockID BlockID, contractAddress *felt.Felt) *Batch {
+	return add[*felt.Felt](b, "starknet_getClassHashAt", []interface{}{blockID, contractAddress}, ErrContractNotFound, ErrBlockNotFound)
+}
+
+// StorageAt queues a starknet_getStorageAt call, the key being the name of the storage variable.
+// Its result is a string.
+func (b *Batch) StorageAt(contractAddress *felt.Felt, key string, blockID BlockID) *Batch {
+	hashKey := fmt.Sprintf("0x%x", utils.GetSelectorFromName(key))
+	return add[string](b, "starknet_getStorageAt", []interface{}{contractAddress, hashKey, blockID}, ErrContractNotFound, ErrBlockNotFound)
+}
+
+// Call queues a starknet_call call.
+// Its result is a []*felt.Felt.
+func (b *Batch) Call(request FunctionCall, blockID BlockID) *Batch {
+	if len(request.Calldata) == 0 {
+		request.Calldata = make([]*felt.Felt, 0)
+	}
+	return add[[]*felt.Felt](b, "starknet_call", []interface{}{request, blockID}, ErrContractNotFound, ErrBlockNotFound)
+}
+
+// Send sends the queued calls and returns their results in the same order.
+// The calls are sent as JSON-RPC batches when the client supports them, and one by one otherwise.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []BatchResult: the results of the calls
+// - error: an error if the batch could not be sent
+func (b *Batch) Send() ([]BatchResult, error) {
+	results := make([]BatchResult, len(b.calls))
+	bc, ok := b.provider.c.(batchCaller)
+	if !ok {
+		for i, call := range b.calls {
+			var raw json.RawMessage
+			err := b.provider.c.CallContext(b.ctx, &raw, call.method, call.args...)
+			results[i] = call.result(raw, err)
+		}
+		return results, nil
+	}
+
+	for start := 0; start < len(b.calls); start += maxBatchSize {
+		end := start + maxBatchSize
+		if end > len(b.calls) {
+			end = len(b.calls)
+		}
+		raws := make([]json.RawMessage, end-start)
+		elems := make([]ethrpc.BatchElem, end-start)
+		for i, call := range b.calls[start:end] {
+			elems[i] = ethrpc.BatchElem{Method: call.method, Args: call.args, Result: &raws[i]}
+		}
+		if err := bc.BatchCallContext(b.ctx, elems); err != nil {
+			return nil, tryUnwrapToRPCErr(err)
+		}
+		for i, call := range b.calls[start:end] {
+			results[start+i] = call.result(raws[i], elems[i].Error)
+		}
+	}
+	return results, nil
+}
+
+// result builds the BatchResult of a call from its raw result and error.
+func (call batchCall) result(raw json.RawMessage, err error) BatchResult {
+	if err == nil && len(raw) == 0 {
+		err = errNotFound
+	}
+	if err != nil {
+		return BatchResult{Err: tryUnwrapToRPCErr(err, call.rpcErrors...)}
+	}
+	value, err := call.decode(raw)
+	if err != nil {
+		return BatchResult{Err: Err(InternalError, err.Error())}
+	}
+	return BatchResult{Result: value}
+}
+
+// ReceiptsForBlock fetches the receipts of all the transactions of a block in a single batch.
+//
+// Parameters:
+// - ctx: The context.Context object for the request
+// - blockID: The ID of the block
+// Returns:
+// - []*TransactionReceiptWithBlockInfo: the receipts, in the order of the transactions of the block
+// - error: an error if the block or one of the receipts could not be fetched
+func (provider *Provider) ReceiptsForBlock(ctx context.Context, blockID BlockID) ([]*TransactionReceiptWithBlockInfo, error) {
+	block, err := FetchBlockWithTxHashes(ctx, provider, blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := provider.Batch(ctx)
+	for _, hash := range block.TransactionHashes() {
+		batch.TransactionReceipt(hash)
+	}
+	results, err := batch.Send()
+	if err != nil {
+		return nil, err
+	}
+	receipts := make([]*TransactionReceiptWithBlockInfo, len(results))
+	for i, result := range results {
+		if receipts[i], err = BatchValue[*TransactionReceiptWithBlockInfo](result); err != nil {
+			return nil, err
+		}
+	}
+	return receipts, nil
+}
+
+// NoncesFor fetches the nonces of several contracts in a single batch.
+//
+// Parameters:
+// - ctx: The context.Context object for the request
+// - blockID: The ID of the block
+// - addresses: The addresses of the contracts
+// Returns:
+// - []*felt.Felt: the nonces, in the order of the addresses
+// - error: an error if one of the nonces could not be fetched
+func (provider *Provider) NoncesFor(ctx context.Context, blockID BlockID, addresses []*felt.Felt) ([]*felt.Felt, error) {
+	batch := provider.Batch(ctx)
+	for _, address := range addresses {
+		batch.Nonce(blockID, address)
+	}
+	results, err := batch.Send()
+	if err != nil {
+		return nil, err
+	}
+	nonces := make([]*felt.Felt, len(results))
+	for i, result := range results {
+		if nonces[i], err = BatchValue[*felt.Felt](result); err != nil {
+			return nil, err
+		}
+	}
+	return nonces, nil
+}
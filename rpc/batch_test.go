@@ -0,0 +1,115 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBatch tests the Batch with the calls sent one by one, as the mock does not support batches.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestBatch(t *testing.T) {
+	testConfig := beforeEach(t)
+
+	type testSetType struct {
+		TxnHash         *felt.Felt
+		ContractAddress *felt.Felt
+		ExpectedNonce   *felt.Felt
+	}
+	testSet := map[string][]testSetType{
+		"mock": {
+			{
+				TxnHash:         utils.TestHexToFelt(t, "0xdeadbeef"),
+				ContractAddress: utils.TestHexToFelt(t, "0x0207acc15dc241e7d167e67e30e769719a727d3e0fa47f9e187707289885dfde"),
+				ExpectedNonce:   utils.TestHexToFelt(t, "0xdeadbeef"),
+			},
+		},
+	}[testEnv]
+
+	for _, test := range testSet {
+		results, err := testConfig.provider.Batch(context.Background()).
+			TransactionReceipt(test.TxnHash).
+			Nonce(BlockID{Tag: "latest"}, test.ContractAddress).
+			Send()
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+
+		receipt, err := BatchValue[*TransactionReceiptWithBlockInfo](results[0])
+		require.NoError(t, err)
+		require.Equal(t, test.TxnHash, receipt.Hash())
+
+		nonce, err := BatchValue[*felt.Felt](results[1])
+		require.NoError(t, err)
+		require.Equal(t, test.ExpectedNonce, nonce)
+
+		_, err = BatchValue[string](results[1])
+		require.Error(t, err)
+
+		nonces, err := testConfig.provider.NoncesFor(context.Background(), BlockID{Tag: "latest"}, []*felt.Felt{test.ContractAddress, test.ContractAddress})
+		require.NoError(t, err)
+		require.Equal(t, []*felt.Felt{test.ExpectedNonce, test.ExpectedNonce}, nonces)
+
+		receipts, err := testConfig.provider.ReceiptsForBlock(context.Background(), BlockID{Tag: "latest"})
+		require.NoError(t, err)
+		require.Len(t, receipts, 2)
+		require.Equal(t, utils.TestHexToFelt(t, "0x5754961d70d6f39d0e2c71a1a4ff5df0a26b1ceda4881ca82898994379e1e73"), receipts[0].Hash())
+		require.Equal(t, utils.TestHexToFelt(t, "0x692381bba0e8505a8e0b92d0f046c8272de9e65f050850df678a0c10d8781d"), receipts[1].Hash())
+	}
+}
+
+// TestBatchCallContext tests that the Batch sends a single JSON-RPC batch and maps the errors of each call.
+func TestBatchCallContext(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var batch []struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+		require.Len(t, batch, 3)
+
+		resp := []map[string]interface{}{
+			{"jsonrpc": "2.0", "id": batch[0].ID, "result": "0x1"},
+			{"jsonrpc": "2.0", "id": batch[1].ID, "error": ErrContractNotFound},
+			{"jsonrpc": "2.0", "id": batch[2].ID, "result": []string{"0x2", "0x3"}},
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider(server.URL)
+	require.NoError(t, err)
+
+	address := utils.TestHexToFelt(t, "0x1")
+	results, err := provider.Batch(context.Background()).
+		Nonce(BlockID{Tag: "latest"}, address).
+		ClassHashAt(BlockID{Tag: "latest"}, address).
+		Call(FunctionCall{ContractAddress: address, EntryPointSelector: address}, BlockID{Tag: "latest"}).
+		Send()
+	require.NoError(t, err)
+	require.Equal(t, 1, requests)
+	require.Len(t, results, 3)
+
+	nonce, err := BatchValue[*felt.Felt](results[0])
+	require.NoError(t, err)
+	require.Equal(t, address, nonce)
+
+	_, err = BatchValue[*felt.Felt](results[1])
+	require.Equal(t, ErrContractNotFound, err)
+
+	output, err := BatchValue[[]*felt.Felt](results[2])
+	require.NoError(t, err)
+	require.Equal(t, []*felt.Felt{utils.TestHexToFelt(t, "0x2"), utils.TestHexToFelt(t, "0x3")}, output)
+}
@@ -35,7 +35,7 @@ func (provider *Provider) BlockNumber(ctx context.Context) (uint64, error) {
 // - error: An error if any
 func (provider *Provider) BlockHashAndNumber(ctx context.Context) (*BlockHashAndNumberOutput, error) {
 	var block BlockHashAndNumberOutput
-	if err := do(ctx, provider.c, "starknet_blockHashAndNumber", &block); err != nil {
+	if err := provider.do(ctx, "starknet_blockHashAndNumber", &block); err != nil {
 		return nil, tryUnwrapToRPCErr(err, ErrNoBlocks)
 	}
 	return &block, nil
@@ -88,17 +88,14 @@ func WithBlockTag(tag string) BlockID {
 // - error: An error, if any
 func (provider *Provider) BlockWithTxHashes(ctx context.Context, blockID BlockID) (interface{}, error) {
 	var result BlockTxHashes
-	if err := do(ctx, provider.c, "starknet_getBlockWithTxHashes", &result, blockID); err != nil {
+	if err := provider.do(ctx, "starknet_getBlockWithTxHashes", &result, blockID); err != nil {
 		return nil, tryUnwrapToRPCErr(err, ErrBlockNotFound)
 	}
 
 	// if header.Hash == nil it's a pending block
 	if result.BlockHeader.BlockHash == nil {
 		return &PendingBlockTxHashes{
-			PendingBlockHeader{
-				ParentHash:       result.ParentHash,
-				Timestamp:        result.Timestamp,
-				SequencerAddress: result.SequencerAddress},
+			result.BlockHeader.toPendingBlockHeader(),
 			result.Transactions,
 		}, nil
 	}
@@ -117,7 +114,7 @@ func (provider *Provider) BlockWithTxHashes(ctx context.Context, blockID BlockID
 // - error: An error, if any
 func (provider *Provider) StateUpdate(ctx context.Context, blockID BlockID) (*StateUpdateOutput, error) {
 	var state StateUpdateOutput
-	if err := do(ctx, provider.c, "starknet_getStateUpdate", &state, blockID); err != nil {
+	if err := provider.do(ctx, "starknet_getStateUpdate", &state, blockID); err != nil {
 		return nil, tryUnwrapToRPCErr(err, ErrBlockNotFound)
 	}
 	return &state, nil
@@ -133,7 +130,7 @@ func (provider *Provider) StateUpdate(ctx context.Context, blockID BlockID) (*St
 // - error: An error, if any
 func (provider *Provider) BlockTransactionCount(ctx context.Context, blockID BlockID) (uint64, error) {
 	var result uint64
-	if err := do(ctx, provider.c, "starknet_getBlockTransactionCount", &result, blockID); err != nil {
+	if err := provider.do(ctx, "starknet_getBlockTransactionCount", &result, blockID); err != nil {
 		if errors.Is(err, errNotFound) {
 			return 0, ErrBlockNotFound
 		}
@@ -152,16 +149,13 @@ func (provider *Provider) BlockTransactionCount(ctx context.Context, blockID Blo
 // - error: An error, if any
 func (provider *Provider) BlockWithTxs(ctx context.Context, blockID BlockID) (interface{}, error) {
 	var result Block
-	if err := do(ctx, provider.c, "starknet_getBlockWithTxs", &result, blockID); err != nil {
+	if err := provider.do(ctx, "starknet_getBlockWithTxs", &result, blockID); err != nil {
 		return nil, tryUnwrapToRPCErr(err, ErrBlockNotFound)
 	}
 	// if header.Hash == nil it's a pending block
 	if result.BlockHeader.BlockHash == nil {
 		return &PendingBlock{
-			PendingBlockHeader{
-				ParentHash:       result.ParentHash,
-				Timestamp:        result.Timestamp,
-				SequencerAddress: result.SequencerAddress},
+			result.BlockHeader.toPendingBlockHeader(),
 			result.Transactions,
 		}, nil
 	}
@@ -171,7 +165,7 @@ func (provider *Provider) BlockWithTxs(ctx context.Context, blockID BlockID) (in
 // Get block information with full transactions and receipts given the block id
 func (provider *Provider) BlockWithReceipts(ctx context.Context, blockID BlockID) (interface{}, error) {
 	var result json.RawMessage
-	if err := do(ctx, provider.c, "starknet_getBlockWithReceipts", &result, blockID); err != nil {
+	if err := provider.do(ctx, "starknet_getBlockWithReceipts", &result, blockID); err != nil {
 		return nil, tryUnwrapToRPCErr(err, ErrBlockNotFound)
 	}
 
@@ -196,3 +190,57 @@ func (provider *Provider) BlockWithReceipts(ctx context.Context, blockID BlockID
 	}
 
 }
+
+// FetchBlockWithTxHashes retrieves the block with transaction hashes for the given block ID,
+// wrapped in a BlockResult instead of being returned as an interface{}.
+//
+// Parameters:
+// - ctx: The context.Context object for controlling the function call
+// - provider: The provider used to fetch the block, such as a *Provider or an account
+// - blockID: The ID of the block to retrieve
+// Returns:
+// - *BlockResult: The retrieved block
+// - error: An error, if any
+func FetchBlockWithTxHashes(ctx context.Context, provider RpcProvider, blockID BlockID) (*BlockResult, error) {
+	block, err := provider.BlockWithTxHashes(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+	return NewBlockResult(block)
+}
+
+// FetchBlockWithTxs retrieves the block with its transactions for the given block ID,
+// wrapped in a BlockResult instead of being returned as an interface{}.
+//
+// Parameters:
+// - ctx: The context.Context object for controlling the function call
+// - provider: The provider used to fetch the block, such as a *Provider or an account
+// - blockID: The ID of the block to retrieve
+// Returns:
+// - *BlockResult: The retrieved block
+// - error: An error, if any
+func FetchBlockWithTxs(ctx context.Context, provider RpcProvider, blockID BlockID) (*BlockResult, error) {
+	block, err := provider.BlockWithTxs(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+	return NewBlockResult(block)
+}
+
+// FetchBlockWithReceipts retrieves the block with its transactions and their receipts for the given block ID,
+// wrapped in a BlockResult instead of being returned as an interface{}.
+//
+// Parameters:
+// - ctx: The context.Context object for controlling the function call
+// - provider: The provider used to fetch the block, such as a *Provider or an account
+// - blockID: The ID of the block to retrieve
+// Returns:
+// - *BlockResult: The retrieved block
+// - error: An error, if any
+func FetchBlockWithReceipts(ctx context.Context, provider RpcProvider, blockID BlockID) (*BlockResult, error) {
+	block, err := provider.BlockWithReceipts(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+	return NewBlockResult(block)
+}
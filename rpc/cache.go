@@ -0,0 +1,147 @@
+package rpc
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Cache stores the encoded responses of a CachingProvider. It must be safe for concurrent use.
+type Cache interface {
+	// Get returns the value stored for the key, if any.
+	Get(key string) ([]byte, bool)
+	// Set stores the value for the key.
+	Set(key string, value []byte)
+}
+
+// LRUCache is an in-memory Cache evicting the least recently used entries above a size limit.
+type LRUCache struct {
+	mu       sync.Mutex
+	maxBytes int
+	size     int
+	entries  *list.List
+	index    map[string]*list.Element
+}
+
+type lruEntry struct {
+	key   string
+	value []byte
+}
+
+var _ Cache = &LRUCache{}
+
+// NewLRUCache creates an LRUCache holding up to maxBytes of keys and values.
+//
+// Parameters:
+// - maxBytes: the size limit of the cache
+// Returns:
+// - *LRUCache: the cache
+func NewLRUCache(maxBytes int) *LRUCache {
+	return &LRUCache{
+		maxBytes: maxBytes,
+		entries:  list.New(),
+		index:    make(map[string]*list.Element),
+	}
+}
+
+// Get implements Cache.
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.index[key]
+	if !ok {
+		return nil, false
+	}
+	c.entries.MoveToFront(elem)
+	return elem.Value.(*lruEntry).value, true
+}
+
+// Set implements Cache. The values larger than the size limit are not stored.
+func (c *LRUCache) Set(key string, value []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(key)+len(value) > c.maxBytes {
+		return
+	}
+	if elem, ok := c.index[key]; ok {
+		c.remove(elem)
+	}
+	c.index[key] = c.entries.PushFront(&lruEntry{key: key, value: value})
+	c.size += len(key) + len(value)
+	for c.size > c.maxBytes {
+		c.remove(c.entries.Back())
+	}
+}
+
+// Len returns the number of entries of the cache.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries.Len()
+}
+
+// remove removes an entry of the cache.
+func (c *LRUCache) remove(elem *list.Element) {
+	entry := c.entries.Remove(elem).(*lruEntry)
+	delete(c.index, entry.key)
+	c.size -= len(entry.key) + len(entry.value)
+}
+
+// DiskCache is a Cache storing each entry in a file of a directory, so that it outlives the process.
+// The read and write failures are treated as cache misses.
+type DiskCache struct {
+	dir string
+}
+
+var _ Cache = &DiskCache{}
+
+// NewDiskCache creates a DiskCache in the given directory, creating it if needed.
+//
+// Parameters:
+// - dir: the directory of the cache
+// Returns:
+// - *DiskCache: the cache
+// - error: an error if the directory cannot be created
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+// Get implements Cache.
+func (c *DiskCache) Get(key string) ([]byte, bool) {
+	value, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// Set implements Cache. The entry is written to a temporary file first, so that
+// concurrent readers never see a partial entry.
+func (c *DiskCache) Set(key string, value []byte) {
+	tmp, err := os.CreateTemp(c.dir, "tmp-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(value)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.path(key))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// path returns the file of an entry, named after the hash of its key.
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,289 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// CacheStats are the statistics of a CachingProvider. The calls that can never
+// be cached, such as the ones on the "latest" block, are not counted.
+type CacheStats struct {
+	// Hits is the number of calls answered from the cache
+	Hits uint64
+	// Misses is the number of calls sent to the underlying provider
+	Misses uint64
+	// Shared is the number of calls answered by an identical call in flight
+	Shared uint64
+}
+
+// CachingProvider is a RpcProvider caching the responses that cannot change anymore:
+//   - the classes, and the classes of contracts, requested at a block given by hash or number
+//   - the blocks accepted on L1, requested by hash or number
+//   - the transactions and their receipts, once accepted on L1
+//
+// Nothing requested with the "latest" or "pending" tags is cached. The methods
+// not listed are forwarded to the underlying provider. Concurrent identical calls
+// are sent once to the underlying provider.
+type CachingProvider struct {
+	RpcProvider
+	cache  Cache
+	flight flightGroup
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+	shared atomic.Uint64
+}
+
+var _ RpcProvider = &CachingProvider{}
+
+// NewCachingProvider creates a CachingProvider on top of a provider.
+//
+// Parameters:
+// - provider: the underlying provider
+// - cache: the backend of the cache, such as an LRUCache or a DiskCache
+// Returns:
+// - *CachingProvider: the caching provider
+func NewCachingProvider(provider RpcProvider, cache Cache) *CachingProvider {
+	return &CachingProvider{RpcProvider: provider, cache: cache}
+}
+
+// Stats returns the statistics of the cache.
+func (cp *CachingProvider) Stats() CacheStats {
+	return CacheStats{
+		Hits:   cp.hits.Load(),
+		Misses: cp.misses.Load(),
+		Shared: cp.shared.Load(),
+	}
+}
+
+// Capabilities returns the capabilities of the underlying provider, the ones of DefaultRPCSpec if it does
+// not detect the spec of its node.
+//
+// Parameters:
+// - ctx: The context.Context object for the request
+// Returns:
+// - Capabilities: the capabilities of the underlying provider
+func (cp *CachingProvider) Capabilities(ctx context.Context) Capabilities {
+	return CapabilitiesOf(ctx, cp.RpcProvider)
+}
+
+// BlockWithTxHashes implements RpcProvider, caching the blocks accepted on L1.
+func (cp *CachingProvider) BlockWithTxHashes(ctx context.Context, blockID BlockID) (interface{}, error) {
+	return cached(cp, blockKey("starknet_getBlockWithTxHashes", blockID),
+		func() (interface{}, error) { return cp.RpcProvider.BlockWithTxHashes(ctx, blockID) },
+		isAcceptedOnL1Block,
+		decodeBlock[*BlockTxHashes])
+}
+
+// BlockWithTxs implements RpcProvider, caching the blocks accepted on L1.
+func (cp *CachingProvider) BlockWithTxs(ctx context.Context, blockID BlockID) (interface{}, error) {
+	return cached(cp, blockKey("starknet_getBlockWithTxs", blockID),
+		func() (interface{}, error) { return cp.RpcProvider.BlockWithTxs(ctx, blockID) },
+		isAcceptedOnL1Block,
+		decodeBlock[*Block])
+}
+
+// BlockWithReceipts implements RpcProvider, caching the blocks accepted on L1.
+func (cp *CachingProvider) BlockWithReceipts(ctx context.Context, blockID BlockID) (interface{}, error) {
+	return cached(cp, blockKey("starknet_getBlockWithReceipts", blockID),
+		func() (interface{}, error) { return cp.RpcProvider.BlockWithReceipts(ctx, blockID) },
+		isAcceptedOnL1Block,
+		decodeBlock[*BlockWithReceipts])
+}
+
+// Class implements RpcProvider, caching the classes requested at a block given by hash or number.
+// The block is part of the key: a class not yet declared at a block fails there, and a block given
+// by number may be reorged before it is accepted on L1.
+func (cp *CachingProvider) Class(ctx context.Context, blockID BlockID, classHash *felt.Felt) (ClassOutput, error) {
+	key := blockKey("starknet_getClass", blockID)
+	if key != "" {
+		key += "/" + classHash.String()
+	}
+	return cached(cp, key,
+		func() (ClassOutput, error) { return cp.RpcProvider.Class(ctx, blockID, classHash) },
+		always[ClassOutput],
+		decodeClassOutput)
+}
+
+// ClassAt implements RpcProvider, caching the classes requested at a block given by hash or number.
+func (cp *CachingProvider) ClassAt(ctx context.Context, blockID BlockID, contractAddress *felt.Felt) (ClassOutput, error) {
+	key := blockKey("starknet_getClassAt", blockID)
+	if key != "" {
+		key += "/" + contractAddress.String()
+	}
+	return cached(cp, key,
+		func() (ClassOutput, error) { return cp.RpcProvider.ClassAt(ctx, blockID, contractAddress) },
+		always[ClassOutput],
+		decodeClassOutput)
+}
+
+// TransactionByHash implements RpcProvider, caching the transactions once their receipt is accepted on L1.
+// A pending transaction may still be dropped or reorged, and its hash reused by another one.
+func (cp *CachingProvider) TransactionByHash(ctx context.Context, hash *felt.Felt) (Transaction, error) {
+	return cached(cp, "starknet_getTransactionByHash/"+hash.String(),
+		func() (Transaction, error) { return cp.RpcProvider.TransactionByHash(ctx, hash) },
+		func(Transaction) bool {
+			receipt, err := cp.TransactionReceipt(ctx, hash)
+			return err == nil && receiptIsFinal(receipt)
+		},
+		func(data []byte) (Transaction, error) {
+			var txn map[string]interface{}
+			if err := json.Unmarshal(data, &txn); err != nil {
+				return nil, err
+			}
+			return unmarshalTxn(txn)
+		})
+}
+
+// TransactionReceipt implements RpcProvider, caching the receipts of the transactions accepted on L1.
+func (cp *CachingProvider) TransactionReceipt(ctx context.Context, transactionHash *felt.Felt) (*TransactionReceiptWithBlockInfo, error) {
+	return cached(cp, "starknet_getTransactionReceipt/"+transactionHash.String(),
+		func() (*TransactionReceiptWithBlockInfo, error) {
+			return cp.RpcProvider.TransactionReceipt(ctx, transactionHash)
+		},
+		receiptIsFinal,
+		decodeTo[*TransactionReceiptWithBlockInfo])
+}
+
+// receiptIsFinal tells whether a receipt is accepted on L1. The receipts without finality status are never final.
+func receiptIsFinal(receipt *TransactionReceiptWithBlockInfo) bool {
+	withStatus, ok := receipt.TransactionReceipt.(finalityStatusReceipt)
+	return ok && withStatus.GetFinalityStatus() == TxnFinalityStatusAcceptedOnL1
+}
+
+// cached answers a call from the cache, or sends it once to the underlying provider, storing the
+// response in the cache when it is final. The calls with an empty key are never cached.
+func cached[T any](cp *CachingProvider, key string, call func() (T, error), final func(T) bool, decode func([]byte) (T, error)) (T, error) {
+	if key == "" {
+		return call()
+	}
+	if data, ok := cp.cache.Get(key); ok {
+		if value, err := decode(data); err == nil {
+			cp.hits.Add(1)
+			return value, nil
+		}
+	}
+
+	var value T
+	data, shared, err := cp.flight.do(key, func() ([]byte, error) {
+		var err error
+		if value, err = call(); err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		if final(value) {
+			cp.cache.Set(key, data)
+		}
+		return data, nil
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	if !shared {
+		cp.misses.Add(1)
+		return value, nil
+	}
+	// the callers sharing a call get their own copy of the response
+	cp.shared.Add(1)
+	return decode(data)
+}
+
+// blockKey returns the cache key of a call on a block given by hash or number, or an empty key for a block tag.
+func blockKey(method string, blockID BlockID) string {
+	switch {
+	case blockID.Number != nil:
+		return method + "/number/" + strconv.FormatUint(*blockID.Number, 10)
+	case blockID.Hash != nil:
+		return method + "/hash/" + blockID.Hash.String()
+	default:
+		return ""
+	}
+}
+
+// isAcceptedOnL1Block tells whether a block returned by the provider is accepted on L1.
+func isAcceptedOnL1Block(block interface{}) bool {
+	result, err := NewBlockResult(block)
+	return err == nil && result.Status() == BlockStatus_AcceptedOnL1
+}
+
+// always is the finality check of the responses that never change.
+func always[T any](T) bool {
+	return true
+}
+
+// decodeTo decodes a response to a pointer type.
+func decodeTo[P interface{ *V }, V any](data []byte) (P, error) {
+	value := P(new(V))
+	if err := json.Unmarshal(data, value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// decodeBlock decodes a block to a pointer type, returned as the interface{} of the block methods.
+func decodeBlock[P interface{ *V }, V any](data []byte) (interface{}, error) {
+	return decodeTo[P](data)
+}
+
+// decodeClassOutput decodes a class.
+func decodeClassOutput(data []byte) (ClassOutput, error) {
+	var rawClass map[string]any
+	if err := json.Unmarshal(data, &rawClass); err != nil {
+		return nil, err
+	}
+	return typecastClassOutput(rawClass)
+}
+
+// flightGroup runs once the concurrent calls with the same key.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done chan struct{}
+	dups int
+	data []byte
+	err  error
+}
+
+// do runs fn, unless a call with the same key is in flight, in which case it waits for its result.
+//
+// Parameters:
+// - key: the key of the call
+// - fn: the call
+// Returns:
+// - []byte: the result of the call
+// - bool: true if the result is the one of another call
+// - error: the error of the call
+func (g *flightGroup) do(key string, fn func() ([]byte, error)) ([]byte, bool, error) {
+	g.mu.Lock()
+	if call, ok := g.calls[key]; ok {
+		call.dups++
+		g.mu.Unlock()
+		<-call.done
+		return call.data, true, call.err
+	}
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	call := &flightCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	call.data, call.err = fn()
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	close(call.done)
+	return call.data, false, call.err
+}
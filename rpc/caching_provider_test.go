@@ -0,0 +1,264 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachingProvider(t *testing.T) {
+	closer := &scriptedCloser{results: map[string]string{
+		"starknet_getBlockWithTxHashes": `{
+			"status":"ACCEPTED_ON_L1","block_hash":"0x1","parent_hash":"0x0","block_number":1,"new_root":"0x0",
+			"timestamp":1,"sequencer_address":"0x0","l1_gas_price":{"price_in_wei":"0x1"},
+			"l1_data_gas_price":{"price_in_wei":"0x1"},"l1_da_mode":"BLOB","starknet_version":"0.13.1",
+			"transactions":["0x2"]
+		}`,
+	}}
+	provider := NewCachingProvider(&Provider{c: closer}, NewLRUCache(1<<20))
+	ctx := context.Background()
+	number := uint64(1)
+
+	type testSetType struct {
+		Call           func() (interface{}, error)
+		ExpectedCached bool
+	}
+	testSet := []testSetType{
+		{
+			Call:           func() (interface{}, error) { return provider.BlockWithTxHashes(ctx, BlockID{Number: &number}) },
+			ExpectedCached: true,
+		},
+		{
+			Call:           func() (interface{}, error) { return provider.BlockWithTxHashes(ctx, BlockID{Tag: "latest"}) },
+			ExpectedCached: false,
+		},
+		{
+			Call: func() (interface{}, error) {
+				return provider.Class(ctx, BlockID{Number: &number}, utils.TestHexToFelt(t, "0xdeadbeef"))
+			},
+			ExpectedCached: true,
+		},
+		{
+			Call: func() (interface{}, error) {
+				return provider.Class(ctx, BlockID{Tag: "pending"}, utils.TestHexToFelt(t, "0xdeadbeef"))
+			},
+			ExpectedCached: false,
+		},
+		{
+			// the receipt of the transaction is accepted on L1
+			Call:           func() (interface{}, error) { return provider.TransactionByHash(ctx, utils.TestHexToFelt(t, "0x1")) },
+			ExpectedCached: true,
+		},
+		{
+			// the receipt of the mock is accepted on L1
+			Call: func() (interface{}, error) {
+				return provider.TransactionReceipt(ctx, utils.TestHexToFelt(t, "0xdeadbeef"))
+			},
+			ExpectedCached: true,
+		},
+	}
+
+	for i, test := range testSet {
+		first, err := test.Call()
+		require.NoError(t, err)
+		calls := closer.callCount()
+
+		second, err := test.Call()
+		require.NoError(t, err)
+		require.Equal(t, first, second, i)
+		if test.ExpectedCached {
+			require.Equal(t, calls, closer.callCount(), i)
+		} else {
+			require.Equal(t, calls+1, closer.callCount(), i)
+		}
+	}
+	// the transaction is cached after its receipt, which is a miss too
+	require.Equal(t, CacheStats{Hits: 4, Misses: 5}, provider.Stats())
+}
+
+func TestCachingProviderNotFinal(t *testing.T) {
+	closer := &scriptedCloser{results: map[string]string{
+		"starknet_getBlockWithTxHashes": `{
+			"status":"ACCEPTED_ON_L2","block_hash":"0x1","parent_hash":"0x0","block_number":1,"new_root":"0x0",
+			"timestamp":1,"sequencer_address":"0x0","l1_gas_price":{"price_in_wei":"0x1"},
+			"l1_data_gas_price":{"price_in_wei":"0x1"},"l1_da_mode":"BLOB","starknet_version":"0.13.1",
+			"transactions":[]
+		}`,
+		"starknet_getTransactionReceipt": `{
+			"type":"INVOKE","transaction_hash":"0x1","actual_fee":{"amount":"0x1","unit":"FRI"},
+			"execution_status":"SUCCEEDED","finality_status":"ACCEPTED_ON_L1","block_hash":"0x2","block_number":1,
+			"messages_sent":[],"events":[],"execution_resources":{"steps":1,"data_availability":{"l1_gas":0,"l1_data_gas":0}}
+		}`,
+	}}
+	provider := NewCachingProvider(&Provider{c: closer}, NewLRUCache(1<<20))
+	ctx := context.Background()
+
+	// a block accepted on L2 may still be reorged
+	for i := 0; i < 2; i++ {
+		_, err := provider.BlockWithTxHashes(ctx, BlockID{Hash: utils.TestHexToFelt(t, "0x1")})
+		require.NoError(t, err)
+	}
+	require.Equal(t, 2, closer.callCount())
+
+	// a receipt accepted on L1 is final
+	for i := 0; i < 2; i++ {
+		receipt, err := provider.TransactionReceipt(ctx, utils.TestHexToFelt(t, "0x1"))
+		require.NoError(t, err)
+		require.Equal(t, TxnFinalityStatusAcceptedOnL1, receipt.TransactionReceipt.(finalityStatusReceipt).GetFinalityStatus())
+		require.Equal(t, utils.TestHexToFelt(t, "0x2"), receipt.BlockHash)
+	}
+	require.Equal(t, 3, closer.callCount())
+}
+
+// executedReceipt is a TransactionReceipt implemented outside of the receipts of the package, without finality status.
+type executedReceipt struct{}
+
+func (executedReceipt) Hash() *felt.Felt                       { return new(felt.Felt) }
+func (executedReceipt) GetExecutionStatus() TxnExecutionStatus { return TxnExecutionStatusSUCCEEDED }
+
+func TestCachingProviderPendingTransaction(t *testing.T) {
+	closer := &scriptedCloser{results: map[string]string{
+		"starknet_getTransactionReceipt": `{
+			"type":"INVOKE","transaction_hash":"0x1","actual_fee":{"amount":"0x1","unit":"FRI"},
+			"execution_status":"SUCCEEDED","finality_status":"ACCEPTED_ON_L2","block_hash":"0x2","block_number":1,
+			"messages_sent":[],"events":[],"execution_resources":{"steps":1,"data_availability":{"l1_gas":0,"l1_data_gas":0}}
+		}`,
+	}}
+	provider := NewCachingProvider(&Provider{c: closer}, NewLRUCache(1<<20))
+
+	// a transaction not accepted on L1 may still be dropped or reorged
+	for i := 0; i < 2; i++ {
+		_, err := provider.TransactionByHash(context.Background(), utils.TestHexToFelt(t, "0x1"))
+		require.NoError(t, err)
+	}
+	require.Equal(t, 4, closer.callCount())
+}
+
+func TestCachingProviderClassPerBlock(t *testing.T) {
+	closer := &scriptedCloser{}
+	provider := NewCachingProvider(&Provider{c: closer}, NewLRUCache(1<<20))
+	classHash := utils.TestHexToFelt(t, "0xdeadbeef")
+
+	// a class may not be declared yet at an earlier block
+	for _, number := range []uint64{1, 2, 1, 2} {
+		number := number
+		_, err := provider.Class(context.Background(), BlockID{Number: &number}, classHash)
+		require.NoError(t, err)
+	}
+	_, err := provider.Class(context.Background(), BlockID{Hash: utils.TestHexToFelt(t, "0x1")}, classHash)
+	require.NoError(t, err)
+	require.Equal(t, 3, closer.callCount())
+	require.Equal(t, CacheStats{Hits: 2, Misses: 3}, provider.Stats())
+}
+
+func TestCachingProviderReceiptWithoutFinalityStatus(t *testing.T) {
+	// such a receipt is never final
+	withReceipt := func(receipt TransactionReceipt) *TransactionReceiptWithBlockInfo {
+		return &TransactionReceiptWithBlockInfo{UnknownTransactionReceipt: UnknownTransactionReceipt{receipt}}
+	}
+	require.False(t, receiptIsFinal(withReceipt(executedReceipt{})))
+	require.False(t, receiptIsFinal(withReceipt(nil)))
+	require.True(t, receiptIsFinal(withReceipt(InvokeTransactionReceipt{FinalityStatus: TxnFinalityStatusAcceptedOnL1})))
+}
+
+// blockingCloser is a CallCloser holding the calls until released.
+type blockingCloser struct {
+	scriptedCloser
+	release chan struct{}
+}
+
+func (c *blockingCloser) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if method != "starknet_specVersion" {
+		<-c.release
+	}
+	return c.scriptedCloser.CallContext(ctx, result, method, args...)
+}
+
+func TestCachingProviderSingleflight(t *testing.T) {
+	closer := &blockingCloser{release: make(chan struct{})}
+	provider := NewCachingProvider(&Provider{c: closer}, NewLRUCache(1<<20))
+	hash := utils.TestHexToFelt(t, "0x1")
+
+	var wg sync.WaitGroup
+	txns := make([]Transaction, 5)
+	for i := range txns {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var err error
+			txns[i], err = provider.TransactionByHash(context.Background(), hash)
+			require.NoError(t, err)
+		}(i)
+	}
+	// let the calls join the one in flight before releasing it
+	for provider.flight.dups("starknet_getTransactionByHash/"+hash.String()) < len(txns)-1 {
+		time.Sleep(time.Millisecond)
+	}
+	close(closer.release)
+	wg.Wait()
+
+	for _, txn := range txns[1:] {
+		require.Equal(t, txns[0], txn)
+	}
+	// the transaction and its receipt
+	require.Equal(t, CacheStats{Misses: 2, Shared: 4}, provider.Stats())
+	require.Equal(t, 2, closer.callCount())
+}
+
+// dups returns the number of calls waiting for the call in flight with the given key.
+func (g *flightGroup) dups(key string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if call, ok := g.calls[key]; ok {
+		return call.dups
+	}
+	return 0
+}
+
+func TestLRUCache(t *testing.T) {
+	cache := NewLRUCache(30)
+	for i := 0; i < 3; i++ {
+		cache.Set(fmt.Sprint("key", i), []byte("value"))
+	}
+	// each entry takes 9 bytes, reading key0 makes key1 the least recently used
+	_, ok := cache.Get("key0")
+	require.True(t, ok)
+	cache.Set("key3", []byte("value"))
+	require.Equal(t, 3, cache.Len())
+
+	_, ok = cache.Get("key1")
+	require.False(t, ok)
+	for _, key := range []string{"key0", "key2", "key3"} {
+		value, ok := cache.Get(key)
+		require.True(t, ok)
+		require.Equal(t, []byte("value"), value)
+	}
+
+	cache.Set("large", make([]byte, 100))
+	_, ok = cache.Get("large")
+	require.False(t, ok)
+	require.Equal(t, 3, cache.Len())
+}
+
+func TestDiskCache(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewDiskCache(dir)
+	require.NoError(t, err)
+
+	_, ok := cache.Get("key")
+	require.False(t, ok)
+	cache.Set("key", []byte("value"))
+
+	// the entries outlive the cache
+	cache, err = NewDiskCache(dir)
+	require.NoError(t, err)
+	value, ok := cache.Get("key")
+	require.True(t, ok)
+	require.Equal(t, []byte("value"), value)
+}
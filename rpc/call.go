@@ -21,7 +21,7 @@ func (provider *Provider) Call(ctx context.Context, request FunctionCall, blockI
 		request.Calldata = make([]*felt.Felt, 0)
 	}
 	var result []*felt.Felt
-	if err := do(ctx, provider.c, "starknet_call", &result, request, blockID); err != nil {
+	if err := provider.do(ctx, "starknet_call", &result, request, blockID); err != nil {
 		return nil, tryUnwrapToRPCErr(err, ErrContractNotFound, ErrBlockNotFound)
 	}
 	return result, nil
@@ -44,6 +44,21 @@ func (provider *Provider) Syncing(ctx context.Context) (*SyncStatus, error) {
 		return &SyncStatus{SyncStatus: res}, nil
 	case SyncStatus:
 		return &res, nil
+	case map[string]interface{}:
+		// the node is syncing
+		var status syncingStatus
+		if err := remarshal(res, &status); err != nil {
+			return nil, Err(InternalError, err)
+		}
+		return &SyncStatus{
+			SyncStatus:        true,
+			StartingBlockHash: status.StartingBlockHash,
+			StartingBlockNum:  NumAsHex(status.StartingBlockNum),
+			CurrentBlockHash:  status.CurrentBlockHash,
+			CurrentBlockNum:   NumAsHex(status.CurrentBlockNum),
+			HighestBlockHash:  status.HighestBlockHash,
+			HighestBlockNum:   NumAsHex(status.HighestBlockNum),
+		}, nil
 	default:
 		return nil, Err(InternalError, "internal error with starknet_syncing")
 	}
@@ -7,22 +7,24 @@ import (
 	ethrpc "github.com/ethereum/go-ethereum/rpc"
 )
 
-type callCloser interface {
+// CallCloser is the transport of a Provider, sending the JSON-RPC calls to the node.
+// It is implemented by the go-ethereum rpc.Client, and can be replaced with NewProviderWithTransport.
+type CallCloser interface {
 	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
 	Close()
 }
 
-// do is a function that performs a remote procedure call (RPC) using the provided callCloser.
+// do is a function that performs a remote procedure call (RPC) using the provided CallCloser.
 //
 // Parameters:
 // - ctx: represents the current execution context
-// - call: the callCloser object
+// - call: the CallCloser object
 // - method: the string representing the RPC method to be called
 // - data: the interface{} to store the result of the RPC call
 // - args: variadic and can be used to pass additional arguments to the RPC method
 // Returns:
 // - error: an error if any occurred during the function call
-func do(ctx context.Context, call callCloser, method string, data interface{}, args ...interface{}) error {
+func do(ctx context.Context, call CallCloser, method string, data interface{}, args ...interface{}) error {
 	var raw json.RawMessage
 	err := call.CallContext(ctx, &raw, method, args...)
 	if err != nil {
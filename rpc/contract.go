@@ -21,7 +21,7 @@ import (
 // - error: An error if any occurred during the execution.
 func (provider *Provider) Class(ctx context.Context, blockID BlockID, classHash *felt.Felt) (ClassOutput, error) {
 	var rawClass map[string]any
-	if err := do(ctx, provider.c, "starknet_getClass", &rawClass, blockID, classHash); err != nil {
+	if err := provider.do(ctx, "starknet_getClass", &rawClass, blockID, classHash); err != nil {
 		return nil, tryUnwrapToRPCErr(err, ErrClassHashNotFound, ErrBlockNotFound)
 	}
 
@@ -40,7 +40,7 @@ func (provider *Provider) Class(ctx context.Context, blockID BlockID, classHash
 // - error: An error if any occurred during the execution
 func (provider *Provider) ClassAt(ctx context.Context, blockID BlockID, contractAddress *felt.Felt) (ClassOutput, error) {
 	var rawClass map[string]any
-	if err := do(ctx, provider.c, "starknet_getClassAt", &rawClass, blockID, contractAddress); err != nil {
+	if err := provider.do(ctx, "starknet_getClassAt", &rawClass, blockID, contractAddress); err != nil {
 		return nil, tryUnwrapToRPCErr(err, ErrContractNotFound, ErrBlockNotFound)
 	}
 	return typecastClassOutput(rawClass)
@@ -87,7 +87,7 @@ func typecastClassOutput(rawClass map[string]any) (ClassOutput, error) {
 // - error: An error if any occurred during the execution
 func (provider *Provider) ClassHashAt(ctx context.Context, blockID BlockID, contractAddress *felt.Felt) (*felt.Felt, error) {
 	var result *felt.Felt
-	if err := do(ctx, provider.c, "starknet_getClassHashAt", &result, blockID, contractAddress); err != nil {
+	if err := provider.do(ctx, "starknet_getClassHashAt", &result, blockID, contractAddress); err != nil {
 
 		return nil, tryUnwrapToRPCErr(err, ErrContractNotFound, ErrBlockNotFound)
 	}
@@ -107,7 +107,7 @@ func (provider *Provider) ClassHashAt(ctx context.Context, blockID BlockID, cont
 func (provider *Provider) StorageAt(ctx context.Context, contractAddress *felt.Felt, key string, blockID BlockID) (string, error) {
 	var value string
 	hashKey := fmt.Sprintf("0x%x", utils.GetSelectorFromName(key))
-	if err := do(ctx, provider.c, "starknet_getStorageAt", &value, contractAddress, hashKey, blockID); err != nil {
+	if err := provider.do(ctx, "starknet_getStorageAt", &value, contractAddress, hashKey, blockID); err != nil {
 
 		return "", tryUnwrapToRPCErr(err, ErrContractNotFound, ErrBlockNotFound)
 	}
@@ -125,7 +125,7 @@ func (provider *Provider) StorageAt(ctx context.Context, contractAddress *felt.F
 // - error: an error if any
 func (provider *Provider) Nonce(ctx context.Context, blockID BlockID, contractAddress *felt.Felt) (*felt.Felt, error) {
 	var nonce *felt.Felt
-	if err := do(ctx, provider.c, "starknet_getNonce", &nonce, blockID, contractAddress); err != nil {
+	if err := provider.do(ctx, "starknet_getNonce", &nonce, blockID, contractAddress); err != nil {
 
 		return nil, tryUnwrapToRPCErr(err, ErrContractNotFound, ErrBlockNotFound)
 	}
@@ -137,7 +137,7 @@ func (provider *Provider) Nonce(ctx context.Context, blockID BlockID, contractAd
 // a TRANSACTION_EXECUTION_ERROR is returned. For v0-2 transactions the estimate is given in wei, and for v3 transactions it is given in fri.
 func (provider *Provider) EstimateFee(ctx context.Context, requests []BroadcastTxn, simulationFlags []SimulationFlag, blockID BlockID) ([]FeeEstimate, error) {
 	var raw []FeeEstimate
-	if err := do(ctx, provider.c, "starknet_estimateFee", &raw, requests, simulationFlags, blockID); err != nil {
+	if err := provider.do(ctx, "starknet_estimateFee", &raw, requests, simulationFlags, blockID); err != nil {
 		return nil, tryUnwrapToRPCErr(err, ErrTxnExec, ErrBlockNotFound)
 	}
 	return raw, nil
@@ -154,7 +154,7 @@ func (provider *Provider) EstimateFee(ctx context.Context, requests []BroadcastT
 // - error: an error if any occurred during the execution
 func (provider *Provider) EstimateMessageFee(ctx context.Context, msg MsgFromL1, blockID BlockID) (*FeeEstimate, error) {
 	var raw FeeEstimate
-	if err := do(ctx, provider.c, "starknet_estimateMessageFee", &raw, msg, blockID); err != nil {
+	if err := provider.do(ctx, "starknet_estimateMessageFee", &raw, msg, blockID); err != nil {
 
 		return nil, tryUnwrapToRPCErr(err, ErrContractNotFound, ErrBlockNotFound)
 	}
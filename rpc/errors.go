@@ -47,13 +47,13 @@ func Err(code int, data any) *RPCError {
 func tryUnwrapToRPCErr(err error, rpcErrors ...*RPCError) *RPCError {
 	errBytes, errIn := json.Marshal(err)
 	if errIn != nil {
-		return Err(InternalError, errIn.Error())
+		return internalError(errIn.Error(), err)
 	}
 
 	var nodeErr RPCError
 	errIn = json.Unmarshal(errBytes, &nodeErr)
 	if errIn != nil {
-		return Err(InternalError, errIn.Error())
+		return internalError(errIn.Error(), err)
 	}
 
 	for _, rpcErr := range rpcErrors {
@@ -61,19 +61,39 @@ func tryUnwrapToRPCErr(err error, rpcErrors ...*RPCError) *RPCError {
 			return &nodeErr
 		}
 	}
-	return Err(InternalError, fmt.Sprintln(nodeErr.Code, nodeErr.Message, nodeErr.Data))
+	if nodeErr.Code == ErrUnsupportedSpecVersion.Code && nodeErr.Message == ErrUnsupportedSpecVersion.Message {
+		return &nodeErr
+	}
+	return internalError(fmt.Sprintln(nodeErr.Code, nodeErr.Message, nodeErr.Data), err)
+}
+
+// internalError returns an InternalError with the given data, wrapping the
+// error it was built from.
+func internalError(data any, cause error) *RPCError {
+	rpcErr := Err(InternalError, data)
+	rpcErr.cause = cause
+	return rpcErr
 }
 
 type RPCError struct {
 	Code    int    `json:"code"`
 	Message string `json:"message"`
 	Data    any    `json:"data,omitempty"`
+
+	// cause is the error that was not sent by the node, such as a transport
+	// error, when the RPCError is an InternalError wrapping it.
+	cause error
 }
 
 func (e RPCError) Error() string {
 	return e.Message
 }
 
+// Unwrap returns the transport error wrapped by an InternalError, if any.
+func (e RPCError) Unwrap() error {
+	return e.cause
+}
+
 var (
 	ErrFailedToReceiveTxn = &RPCError{
 		Code:    1,
@@ -191,4 +211,24 @@ var (
 		Code:    63,
 		Message: "An unexpected error occurred",
 	}
+	ErrUnsupportedSpecVersion = &RPCError{
+		Code:    MethodNotFound,
+		Message: "The method is not supported by the spec version of the node",
+	}
+	ErrInvalidSubscriptionID = &RPCError{
+		Code:    66,
+		Message: "Invalid subscription id",
+	}
+	ErrTooManyAddressesInFilter = &RPCError{
+		Code:    67,
+		Message: "Too many addresses in filter sender_address filter",
+	}
+	ErrTooManyBlocksBack = &RPCError{
+		Code:    68,
+		Message: "Cannot go back more than 1024 blocks",
+	}
+	ErrCallOnPending = &RPCError{
+		Code:    69,
+		Message: "This method does not support being called on the pending block",
+	}
 )
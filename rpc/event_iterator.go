@@ -0,0 +1,360 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+const (
+	defaultEventChunkSize   = 1000
+	defaultEventConcurrency = 4
+)
+
+// EventIteratorOptions configures an EventIterator. The zero values select the defaults.
+type EventIteratorOptions struct {
+	// ChunkSize is the number of events requested per page, 1000 by default.
+	// It is halved each time the node answers with ErrPageSizeTooBig.
+	ChunkSize int
+	// WindowSize is the number of blocks of the windows the block range is split into,
+	// the windows being fetched in parallel. When zero, the range is read as a whole.
+	WindowSize uint64
+	// Concurrency is the number of windows fetched in parallel, 4 by default
+	Concurrency int
+	// Checkpoint is the position to resume from, as returned by EventIterator.Checkpoint.
+	// The filter and the WindowSize must be the ones of the iterator that returned it.
+	Checkpoint *EventCheckpoint
+}
+
+// EventCheckpoint is a position in the events matched by an EventIterator.
+type EventCheckpoint struct {
+	// BlockNumber is the first block of the range being read
+	BlockNumber uint64 `json:"block_number"`
+	// ContinuationToken is the token of the page being read, empty for the first page of the range
+	ContinuationToken string `json:"continuation_token,omitempty"`
+	// Skip is the number of events of the page already delivered
+	Skip int `json:"skip,omitempty"`
+}
+
+// EventIterator iterates over the events matching a filter, following the continuation tokens.
+// The events are delivered in the order of the chain, even when the windows are fetched in parallel.
+//
+//	it := rpc.NewEventIterator(ctx, provider, filter, rpc.EventIteratorOptions{WindowSize: 1000})
+//	defer it.Close()
+//	for it.Next() {
+//		event := it.Event()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type EventIterator struct {
+	provider RpcProvider
+	filter   EventFilter
+	opts     EventIteratorOptions
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	chunkMu   sync.Mutex
+	chunkSize int
+
+	started    bool
+	windows    chan *eventWindow
+	window     *eventWindow
+	page       eventPage
+	index      int
+	event      EmittedEvent
+	checkpoint EventCheckpoint
+	err        error
+}
+
+// eventWindow is a range of blocks whose pages are fetched by a single goroutine.
+type eventWindow struct {
+	from  uint64
+	pages chan eventPage
+}
+
+// eventPage is a page of events, or the error that stopped the fetching of a window.
+type eventPage struct {
+	// token is the continuation token the page was fetched with
+	token string
+	// skipped is the number of events of the page dropped when resuming from a checkpoint
+	skipped int
+	events  []EmittedEvent
+	err     error
+}
+
+// NewEventIterator creates an EventIterator over the events matching the filter.
+// An unset FromBlock starts from the block 0, and an unset ToBlock ends at the latest block.
+// Nothing is fetched until the first call to Next.
+//
+// Parameters:
+// - ctx: The context of the iteration, cancelling it stops the iterator
+// - provider: The provider used to fetch the events
+// - filter: The filter of the events
+// - opts: The options of the iterator
+// Returns:
+// - *EventIterator: the iterator, to be closed when no longer used
+func NewEventIterator(ctx context.Context, provider RpcProvider, filter EventFilter, opts EventIteratorOptions) *EventIterator {
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = defaultEventChunkSize
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = defaultEventConcurrency
+	}
+	if filter.FromBlock == (BlockID{}) {
+		filter.FromBlock = BlockID{Number: uint64Ptr(0)}
+	}
+	if filter.ToBlock == (BlockID{}) {
+		filter.ToBlock = BlockID{Tag: "latest"}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return &EventIterator{
+		provider:  provider,
+		filter:    filter,
+		opts:      opts,
+		ctx:       ctx,
+		cancel:    cancel,
+		chunkSize: opts.ChunkSize,
+	}
+}
+
+// Next moves to the next event. It returns false when there are no more events,
+// or when the iteration failed, in which case Err returns the error.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - bool: true if Event returns a new event
+func (it *EventIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if !it.started {
+		it.started = true
+		if err := it.start(); err != nil {
+			it.err = err
+			return false
+		}
+	}
+
+	for it.index >= len(it.page.events) {
+		if !it.nextPage() {
+			return false
+		}
+	}
+	it.event = it.page.events[it.index]
+	it.index++
+	it.checkpoint.Skip = it.page.skipped + it.index
+	return true
+}
+
+// Event returns the current event.
+func (it *EventIterator) Event() EmittedEvent {
+	return it.event
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *EventIterator) Err() error {
+	return it.err
+}
+
+// Checkpoint returns the position following the current event. An iterator created
+// with this checkpoint delivers the events following the current one.
+func (it *EventIterator) Checkpoint() EventCheckpoint {
+	return it.checkpoint
+}
+
+// Close stops the fetching of the events.
+func (it *EventIterator) Close() {
+	it.cancel()
+}
+
+// nextPage waits for the next page, moving to the next window when the current one is exhausted.
+func (it *EventIterator) nextPage() bool {
+	for {
+		if it.window == nil {
+			select {
+			case window, ok := <-it.windows:
+				if !ok {
+					return false
+				}
+				it.window = window
+			case <-it.ctx.Done():
+				it.err = it.ctx.Err()
+				return false
+			}
+		}
+
+		select {
+		case page, ok := <-it.window.pages:
+			if !ok {
+				it.window = nil
+				continue
+			}
+			if page.err != nil {
+				it.err = page.err
+				it.cancel()
+				return false
+			}
+			it.page, it.index = page, 0
+			it.checkpoint = EventCheckpoint{BlockNumber: it.window.from, ContinuationToken: page.token, Skip: page.skipped}
+			return true
+		case <-it.ctx.Done():
+			it.err = it.ctx.Err()
+			return false
+		}
+	}
+}
+
+// start resolves the block range and starts fetching the windows.
+func (it *EventIterator) start() error {
+	from, err := it.blockNumber(it.filter.FromBlock, true)
+	if err != nil {
+		return err
+	}
+	var token string
+	var skip int
+	resumed := it.opts.Checkpoint != nil
+	if resumed {
+		from, token, skip = it.opts.Checkpoint.BlockNumber, it.opts.Checkpoint.ContinuationToken, it.opts.Checkpoint.Skip
+	}
+	it.checkpoint = EventCheckpoint{BlockNumber: from, ContinuationToken: token, Skip: skip}
+
+	// without windows, or with a range ending before its start, the range is read as a whole
+	to := from
+	if it.opts.WindowSize > 0 {
+		if to, err = it.blockNumber(it.filter.ToBlock, false); err != nil {
+			return err
+		}
+	}
+
+	it.windows = make(chan *eventWindow, it.opts.Concurrency)
+	go func() {
+		defer close(it.windows)
+		slots := make(chan struct{}, it.opts.Concurrency)
+		for start := from; ; start += it.opts.WindowSize {
+			filter := it.filter
+			if resumed || start != from {
+				filter.FromBlock = BlockID{Number: uint64Ptr(start)}
+			}
+			last := it.opts.WindowSize == 0 || to-start < it.opts.WindowSize || start > to
+			if !last {
+				filter.ToBlock = BlockID{Number: uint64Ptr(start + it.opts.WindowSize - 1)}
+			}
+
+			select {
+			case slots <- struct{}{}:
+			case <-it.ctx.Done():
+				return
+			}
+			window := &eventWindow{from: start, pages: make(chan eventPage, 1)}
+			go func(token string, skip int) {
+				defer func() { <-slots }()
+				it.fetch(window, filter, token, skip)
+			}(token, skip)
+			token, skip = "", 0
+
+			select {
+			case it.windows <- window:
+			case <-it.ctx.Done():
+				return
+			}
+			if last {
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// fetch fetches the pages of a window, dropping the first skip events.
+func (it *EventIterator) fetch(window *eventWindow, filter EventFilter, token string, skip int) {
+	defer close(window.pages)
+	send := func(page eventPage) bool {
+		select {
+		case window.pages <- page:
+			return true
+		case <-it.ctx.Done():
+			return false
+		}
+	}
+
+	for {
+		chunkSize := it.currentChunkSize()
+		chunk, err := it.provider.Events(it.ctx, EventsInput{
+			EventFilter:       filter,
+			ResultPageRequest: ResultPageRequest{ContinuationToken: token, ChunkSize: chunkSize},
+		})
+		if err != nil {
+			var rpcErr *RPCError
+			if errors.As(err, &rpcErr) && rpcErr.Code == ErrPageSizeTooBig.Code && chunkSize > 1 {
+				it.shrinkChunkSize(chunkSize)
+				continue
+			}
+			send(eventPage{err: err})
+			return
+		}
+
+		page := eventPage{token: token, events: chunk.Events}
+		if skip > 0 {
+			page.skipped = min(skip, len(page.events))
+			page.events = page.events[page.skipped:]
+			skip -= page.skipped
+		}
+		if !send(page) || chunk.ContinuationToken == "" {
+			return
+		}
+		token = chunk.ContinuationToken
+	}
+}
+
+// currentChunkSize returns the chunk size to request, shared by all the windows.
+func (it *EventIterator) currentChunkSize() int {
+	it.chunkMu.Lock()
+	defer it.chunkMu.Unlock()
+	return it.chunkSize
+}
+
+// shrinkChunkSize halves the chunk size after the node refused a page of the given size.
+func (it *EventIterator) shrinkChunkSize(refused int) {
+	it.chunkMu.Lock()
+	defer it.chunkMu.Unlock()
+	if it.chunkSize >= refused {
+		it.chunkSize = max(refused/2, 1)
+	}
+}
+
+// blockNumber returns the number of a block of the filter. The pending block
+// is given the number following the latest block when it starts the range.
+func (it *EventIterator) blockNumber(blockID BlockID, start bool) (uint64, error) {
+	switch {
+	case blockID.Number != nil:
+		return *blockID.Number, nil
+	case blockID.Hash != nil:
+		block, err := FetchBlockWithTxHashes(it.ctx, it.provider, blockID)
+		if err != nil {
+			return 0, err
+		}
+		return block.Header().BlockNumber, nil
+	case blockID.Tag == "latest" || blockID.Tag == "pending":
+		latest, err := it.provider.BlockNumber(it.ctx)
+		if err != nil {
+			return 0, err
+		}
+		if blockID.Tag == "pending" && start {
+			return latest + 1, nil
+		}
+		return latest, nil
+	default:
+		return 0, ErrInvalidBlockID
+	}
+}
+
+// uint64Ptr returns a pointer to a copy of n.
+func uint64Ptr(n uint64) *uint64 {
+	return &n
+}
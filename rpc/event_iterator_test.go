@@ -0,0 +1,202 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/stretchr/testify/require"
+)
+
+// eventsCloser is a CallCloser serving the events of a chain whose blocks each emit the same number of events.
+// The continuation tokens are the position of the next event, as "block:index".
+type eventsCloser struct {
+	latest       uint64
+	perBlock     int
+	maxChunkSize int
+
+	mu         sync.Mutex
+	chunkSizes []int
+}
+
+func (c *eventsCloser) Close() {}
+
+func (c *eventsCloser) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	switch method {
+	case "starknet_specVersion":
+		return json.Unmarshal([]byte(`"0.7.1"`), result)
+	case "starknet_blockNumber":
+		return json.Unmarshal([]byte(fmt.Sprint(c.latest)), result)
+	case "starknet_getEvents":
+	default:
+		return errNotFound
+	}
+
+	input := args[0].(EventsInput)
+	c.mu.Lock()
+	c.chunkSizes = append(c.chunkSizes, input.ChunkSize)
+	c.mu.Unlock()
+	if input.ChunkSize > c.maxChunkSize {
+		return ErrPageSizeTooBig
+	}
+
+	from, to := c.latest+1, c.latest
+	if input.FromBlock.Number != nil {
+		from = *input.FromBlock.Number
+	}
+	if input.ToBlock.Number != nil {
+		to = *input.ToBlock.Number
+	}
+	block, index := from, 0
+	if input.ContinuationToken != "" {
+		if _, err := fmt.Sscanf(input.ContinuationToken, "%d:%d", &block, &index); err != nil {
+			return ErrInvalidContinuationToken
+		}
+	}
+
+	var chunk EventChunk
+	for ; block <= to; block, index = block+1, 0 {
+		for ; index < c.perBlock; index++ {
+			if len(chunk.Events) == input.ChunkSize {
+				chunk.ContinuationToken = fmt.Sprintf("%d:%d", block, index)
+				return remarshal(chunk, result)
+			}
+			chunk.Events = append(chunk.Events, EmittedEvent{
+				BlockNumber: block,
+				Event:       Event{Data: []*felt.Felt{new(felt.Felt).SetUint64(uint64(index))}},
+			})
+		}
+	}
+	return remarshal(chunk, result)
+}
+
+// collectEvents iterates over all the events, returning the block number and index of each one.
+func collectEvents(t *testing.T, it *EventIterator, limit int) [][2]uint64 {
+	var events [][2]uint64
+	for (limit < 0 || len(events) < limit) && it.Next() {
+		event := it.Event()
+		events = append(events, [2]uint64{event.BlockNumber, event.Data[0].Bits()[0]})
+	}
+	require.NoError(t, it.Err())
+	return events
+}
+
+// expectedEvents returns the block number and index of the events of the blocks from to to.
+func expectedEvents(from, to uint64, perBlock int) [][2]uint64 {
+	var events [][2]uint64
+	for block := from; block <= to; block++ {
+		for index := 0; index < perBlock; index++ {
+			events = append(events, [2]uint64{block, uint64(index)})
+		}
+	}
+	return events
+}
+
+func TestEventIterator(t *testing.T) {
+	type testSetType struct {
+		Filter  EventFilter
+		Options EventIteratorOptions
+		From    uint64
+		To      uint64
+	}
+	testSet := []testSetType{
+		{
+			Filter:  EventFilter{FromBlock: BlockID{Number: uint64Ptr(3)}, ToBlock: BlockID{Number: uint64Ptr(40)}},
+			Options: EventIteratorOptions{ChunkSize: 7},
+			From:    3,
+			To:      40,
+		},
+		{
+			Filter:  EventFilter{FromBlock: BlockID{Number: uint64Ptr(0)}, ToBlock: BlockID{Tag: "latest"}},
+			Options: EventIteratorOptions{ChunkSize: 4, WindowSize: 6, Concurrency: 3},
+			From:    0,
+			To:      50,
+		},
+		{
+			Filter:  EventFilter{FromBlock: BlockID{Number: uint64Ptr(45)}, ToBlock: BlockID{Number: uint64Ptr(45)}},
+			Options: EventIteratorOptions{WindowSize: 10},
+			From:    45,
+			To:      45,
+		},
+		{
+			// the unset blocks are the whole chain
+			Filter:  EventFilter{},
+			Options: EventIteratorOptions{ChunkSize: 20},
+			From:    0,
+			To:      50,
+		},
+		{
+			Filter:  EventFilter{FromBlock: BlockID{Number: uint64Ptr(30)}},
+			Options: EventIteratorOptions{ChunkSize: 5, WindowSize: 8, Concurrency: 2},
+			From:    30,
+			To:      50,
+		},
+	}
+
+	for _, test := range testSet {
+		closer := &eventsCloser{latest: 50, perBlock: 3, maxChunkSize: 100}
+		it := NewEventIterator(context.Background(), &Provider{c: closer}, test.Filter, test.Options)
+		require.Equal(t, expectedEvents(test.From, test.To, 3), collectEvents(t, it, -1))
+		it.Close()
+	}
+}
+
+func TestEventIteratorPageSizeTooBig(t *testing.T) {
+	closer := &eventsCloser{latest: 20, perBlock: 2, maxChunkSize: 5}
+	filter := EventFilter{FromBlock: BlockID{Number: uint64Ptr(0)}, ToBlock: BlockID{Tag: "latest"}}
+	it := NewEventIterator(context.Background(), &Provider{c: closer}, filter, EventIteratorOptions{ChunkSize: 40})
+	defer it.Close()
+
+	require.Equal(t, expectedEvents(0, 20, 2), collectEvents(t, it, -1))
+	require.Equal(t, []int{40, 20, 10, 5}, closer.chunkSizes[:4])
+	for _, chunkSize := range closer.chunkSizes[4:] {
+		require.Equal(t, 5, chunkSize)
+	}
+}
+
+func TestEventIteratorCheckpoint(t *testing.T) {
+	filter := EventFilter{FromBlock: BlockID{Number: uint64Ptr(2)}, ToBlock: BlockID{Number: uint64Ptr(30)}}
+	for _, opts := range []EventIteratorOptions{
+		{ChunkSize: 4},
+		{ChunkSize: 4, WindowSize: 5},
+	} {
+		closer := &eventsCloser{latest: 30, perBlock: 3, maxChunkSize: 100}
+		provider := &Provider{c: closer}
+
+		it := NewEventIterator(context.Background(), provider, filter, opts)
+		events := collectEvents(t, it, 23)
+		checkpoint := it.Checkpoint()
+		it.Close()
+
+		// the checkpoint goes through JSON, as it would be persisted
+		raw, err := json.Marshal(checkpoint)
+		require.NoError(t, err)
+		var resumed EventCheckpoint
+		require.NoError(t, json.Unmarshal(raw, &resumed))
+
+		opts.Checkpoint = &resumed
+		opts.ChunkSize = 2
+		it = NewEventIterator(context.Background(), provider, filter, opts)
+		events = append(events, collectEvents(t, it, -1)...)
+		it.Close()
+		require.Equal(t, expectedEvents(2, 30, 3), events)
+	}
+}
+
+func TestEventIteratorError(t *testing.T) {
+	filter := EventFilter{FromBlock: BlockID{Number: uint64Ptr(0)}, ToBlock: BlockID{Number: uint64Ptr(10)}}
+	closer := &eventsCloser{latest: 10, perBlock: 1, maxChunkSize: 0}
+	it := NewEventIterator(context.Background(), &Provider{c: closer}, filter, EventIteratorOptions{ChunkSize: 1})
+	defer it.Close()
+
+	require.False(t, it.Next())
+	require.Equal(t, ErrPageSizeTooBig.Code, it.Err().(*RPCError).Code)
+
+	it = NewEventIterator(context.Background(), &Provider{c: closer}, EventFilter{FromBlock: BlockID{Tag: "unknown"}}, EventIteratorOptions{})
+	defer it.Close()
+	require.False(t, it.Next())
+	require.Equal(t, ErrInvalidBlockID, it.Err())
+}
@@ -14,7 +14,7 @@ import (
 // - error: An error if any
 func (provider *Provider) Events(ctx context.Context, input EventsInput) (*EventChunk, error) {
 	var result EventChunk
-	if err := do(ctx, provider.c, "starknet_getEvents", &result, input); err != nil {
+	if err := provider.do(ctx, "starknet_getEvents", &result, input); err != nil {
 		return nil, tryUnwrapToRPCErr(err, ErrPageSizeTooBig, ErrInvalidContinuationToken, ErrBlockNotFound, ErrTooManyKeysInFilter)
 	}
 	return &result, nil
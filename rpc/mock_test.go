@@ -397,11 +397,21 @@ func mock_starknet_getTransactionReceipt(result interface{}, method string, args
 	transaction := InvokeTransactionReceipt(CommonTransactionReceipt{
 		TransactionHash: arg0Felt,
 		FinalityStatus:  TxnFinalityStatusAcceptedOnL1,
+		ExecutionStatus: TxnExecutionStatusSUCCEEDED,
+		Type:            TransactionType_Invoke,
 		Events: []Event{{
 			FromAddress: fromAddressFelt,
 		}},
 	})
-	outputContent, err := json.Marshal(transaction)
+	blockHash, err := utils.HexToFelt("0xbeef")
+	if err != nil {
+		return err
+	}
+	outputContent, err := json.Marshal(&TransactionReceiptWithBlockInfo{
+		UnknownTransactionReceipt: UnknownTransactionReceipt{transaction},
+		BlockHash:                 blockHash,
+		BlockNumber:               1,
+	})
 	if err != nil {
 		return err
 	}
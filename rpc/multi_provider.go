@@ -0,0 +1,594 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+var ErrNoQuorum = errors.New("no quorum reached between the providers")
+
+// MultiProviderPolicy defines how a MultiProvider spreads the calls over its providers.
+type MultiProviderPolicy int
+
+const (
+	// PolicyRoundRobin sends each call to the next healthy provider.
+	PolicyRoundRobin MultiProviderPolicy = iota
+	// PolicyPrimaryFallback sends the calls to the first healthy provider,
+	// the next ones being only used when it fails.
+	PolicyPrimaryFallback
+	// PolicyQuorum sends the read calls to all the healthy providers and returns
+	// the response given by at least Quorum of them. Writes follow PolicyPrimaryFallback.
+	PolicyQuorum
+)
+
+// MultiProviderOptions configures a MultiProvider. The zero values select the defaults.
+type MultiProviderOptions struct {
+	Policy MultiProviderPolicy
+	// Quorum is the number of matching responses needed by PolicyQuorum,
+	// a majority of the healthy providers by default. With a Quorum, the
+	// calls fail with ErrNoQuorum when fewer providers are healthy.
+	Quorum int
+	// MaxRetries is the number of retries of a failed read call, 3 by default.
+	// A negative value disables the retries.
+	MaxRetries int
+	// InitialBackoff is the delay before the first retry, 100ms by default.
+	// It doubles at each retry, up to MaxBackoff.
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum delay between two retries, 5s by default
+	MaxBackoff time.Duration
+	// MaxBlockLag is the number of blocks a provider can be behind the highest
+	// block number of the other providers before being ejected, 5 by default
+	MaxBlockLag uint64
+	// HealthCheckInterval is the interval between two health checks. When zero,
+	// the health is only checked by calling CheckHealth.
+	HealthCheckInterval time.Duration
+}
+
+// NodeHealth is the health of a provider of a MultiProvider, as of the last health check.
+type NodeHealth struct {
+	Healthy     bool
+	BlockNumber uint64
+	Err         error
+}
+
+// MultiProvider is a RpcProvider spreading the calls over several providers,
+// retrying the failed calls and ejecting the providers behind the chain tip.
+type MultiProvider struct {
+	providers []RpcProvider
+	opts      MultiProviderOptions
+
+	mu     sync.Mutex
+	health []NodeHealth
+	next   int
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+var _ RpcProvider = &MultiProvider{}
+
+// NewMultiProvider creates a MultiProvider on top of the given providers.
+// All the providers are considered healthy until the first health check.
+//
+// Parameters:
+// - providers: the underlying providers, in order of preference for PolicyPrimaryFallback
+// - opts: the options of the MultiProvider
+// Returns:
+// - *MultiProvider: a new MultiProvider
+// - error: an error if no provider is given or the options are invalid
+func NewMultiProvider(providers []RpcProvider, opts MultiProviderOptions) (*MultiProvider, error) {
+	if len(providers) == 0 {
+		return nil, errors.New("at least one provider is required")
+	}
+	if opts.Quorum > len(providers) {
+		return nil, fmt.Errorf("quorum %d is higher than the number of providers %d", opts.Quorum, len(providers))
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = 3
+	}
+	if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	}
+	if opts.InitialBackoff == 0 {
+		opts.InitialBackoff = 100 * time.Millisecond
+	}
+	if opts.MaxBackoff == 0 {
+		opts.MaxBackoff = 5 * time.Second
+	}
+	if opts.MaxBlockLag == 0 {
+		opts.MaxBlockLag = 5
+	}
+
+	mp := &MultiProvider{
+		providers: providers,
+		opts:      opts,
+		health:    make([]NodeHealth, len(providers)),
+		stop:      make(chan struct{}),
+	}
+	for i := range mp.health {
+		mp.health[i].Healthy = true
+	}
+	if opts.HealthCheckInterval > 0 {
+		go mp.healthLoop()
+	}
+	return mp, nil
+}
+
+// Close stops the background health checks.
+func (mp *MultiProvider) Close() {
+	mp.stopOnce.Do(func() { close(mp.stop) })
+}
+
+// Health returns the health of each provider, in the order they were given.
+func (mp *MultiProvider) Health() []NodeHealth {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	return append([]NodeHealth(nil), mp.health...)
+}
+
+// healthLoop checks the health of the providers until the MultiProvider is closed.
+func (mp *MultiProvider) healthLoop() {
+	ticker := time.NewTicker(mp.opts.HealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-mp.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), mp.opts.HealthCheckInterval)
+			mp.CheckHealth(ctx)
+			cancel()
+		}
+	}
+}
+
+// CheckHealth queries the block number and the sync status of every provider.
+// The providers that fail to answer, or whose block number is more than
+// MaxBlockLag blocks behind the highest one, are ejected until the next check.
+//
+// Parameters:
+// - ctx: The context.Context object for the requests
+// Returns:
+//
+//	none
+func (mp *MultiProvider) CheckHealth(ctx context.Context) {
+	health := make([]NodeHealth, len(mp.providers))
+	var wg sync.WaitGroup
+	for i, p := range mp.providers {
+		wg.Add(1)
+		go func(i int, p RpcProvider) {
+			defer wg.Done()
+			health[i] = mp.checkNode(ctx, p)
+		}(i, p)
+	}
+	wg.Wait()
+
+	var tip uint64
+	for _, h := range health {
+		if h.Err == nil && h.BlockNumber > tip {
+			tip = h.BlockNumber
+		}
+	}
+	for i := range health {
+		if health[i].Err != nil {
+			continue
+		}
+		if tip-health[i].BlockNumber > mp.opts.MaxBlockLag {
+			health[i].Err = fmt.Errorf("provider is %d blocks behind the tip", tip-health[i].BlockNumber)
+			continue
+		}
+		health[i].Healthy = true
+	}
+
+	mp.mu.Lock()
+	mp.health = health
+	mp.mu.Unlock()
+}
+
+// checkNode queries the block number and the sync status of a provider.
+func (mp *MultiProvider) checkNode(ctx context.Context, p RpcProvider) NodeHealth {
+	blockNumber, err := p.BlockNumber(ctx)
+	if err != nil {
+		return NodeHealth{Err: err}
+	}
+	status, err := p.Syncing(ctx)
+	if err != nil {
+		return NodeHealth{BlockNumber: blockNumber, Err: err}
+	}
+	if status.SyncStatus {
+		current, errCurrent := strconv.ParseUint(string(status.CurrentBlockNum), 0, 64)
+		highest, errHighest := strconv.ParseUint(string(status.HighestBlockNum), 0, 64)
+		if errCurrent == nil && errHighest == nil && highest > current+mp.opts.MaxBlockLag {
+			return NodeHealth{BlockNumber: blockNumber, Err: fmt.Errorf("provider is syncing, %d blocks behind", highest-current)}
+		}
+	}
+	return NodeHealth{BlockNumber: blockNumber}
+}
+
+// Capabilities returns the capabilities of the first provider, the nodes of a MultiProvider being
+// expected to follow the same spec.
+//
+// Parameters:
+// - ctx: The context.Context object for the request
+// Returns:
+// - Capabilities: the capabilities of the first provider
+func (mp *MultiProvider) Capabilities(ctx context.Context) Capabilities {
+	return CapabilitiesOf(ctx, mp.providers[0])
+}
+
+// order returns the providers to use for a call, in order of preference.
+// When no provider is healthy, all the providers are used.
+func (mp *MultiProvider) order() []RpcProvider {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+
+	healthy := make([]RpcProvider, 0, len(mp.providers))
+	for i, p := range mp.providers {
+		if mp.health[i].Healthy {
+			healthy = append(healthy, p)
+		}
+	}
+	if len(healthy) == 0 {
+		healthy = append(healthy, mp.providers...)
+	}
+	if mp.opts.Policy != PolicyRoundRobin {
+		return healthy
+	}
+
+	start := mp.next % len(healthy)
+	mp.next++
+	ordered := make([]RpcProvider, 0, len(healthy))
+	ordered = append(ordered, healthy[start:]...)
+	return append(ordered, healthy[:start]...)
+}
+
+// isRetryable tells whether a call that failed with err can be sent again.
+// Only the transport errors and the internal errors of the nodes are retried,
+// the other errors of the spec being deterministic.
+func isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) {
+		return true
+	}
+	return rpcErr.Code == InternalError || rpcErr.Code == ErrUnexpectedError.Code
+}
+
+// isNotSent tells whether a call that failed with err never reached the node,
+// the connection to it not being established, so that it cannot have been
+// executed and can be sent to another node.
+func isNotSent(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// execute runs a call following the policy of the MultiProvider. The calls
+// that are not idempotent are sent once: they only move to the next provider
+// when the previous one never received them, and stop after any response or
+// any error that leaves open whether the node received them.
+func execute[T any](ctx context.Context, mp *MultiProvider, idempotent bool, call func(RpcProvider) (T, error)) (T, error) {
+	providers := mp.order()
+	if !idempotent {
+		return sendOnce(providers, call)
+	}
+	if mp.opts.Policy == PolicyQuorum {
+		return executeQuorum(ctx, mp, providers, call)
+	}
+	return retry(ctx, mp, providers, call)
+}
+
+// sendOnce runs a call that is not idempotent on the first provider that
+// receives it.
+func sendOnce[T any](providers []RpcProvider, call func(RpcProvider) (T, error)) (T, error) {
+	var result T
+	var err error
+	for _, provider := range providers {
+		result, err = call(provider)
+		if err == nil || !isNotSent(err) {
+			return result, err
+		}
+	}
+	return result, err
+}
+
+// retry runs a call until it succeeds or fails with an error that is not
+// retryable, moving to the next provider and backing off after each failure.
+func retry[T any](ctx context.Context, mp *MultiProvider, providers []RpcProvider, call func(RpcProvider) (T, error)) (T, error) {
+	backoff := mp.opts.InitialBackoff
+	var result T
+	var err error
+	for attempt := 0; attempt <= mp.opts.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return result, ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if backoff > mp.opts.MaxBackoff {
+				backoff = mp.opts.MaxBackoff
+			}
+		}
+		result, err = call(providers[attempt%len(providers)])
+		if err == nil || !isRetryable(err) {
+			return result, err
+		}
+	}
+	return result, err
+}
+
+// executeQuorum sends a call to all the providers and returns the response
+// given by at least Quorum of them. Errors count as responses, so that a
+// deterministic error such as ErrHashNotFound can reach the quorum.
+// It fails with ErrNoQuorum when fewer providers than Quorum are healthy.
+func executeQuorum[T any](ctx context.Context, mp *MultiProvider, providers []RpcProvider, call func(RpcProvider) (T, error)) (T, error) {
+	quorum := mp.opts.Quorum
+	if quorum == 0 {
+		quorum = len(providers)/2 + 1
+	}
+	if quorum > len(providers) {
+		var zero T
+		return zero, fmt.Errorf("%w: %d healthy providers for a quorum of %d", ErrNoQuorum, len(providers), quorum)
+	}
+
+	type response struct {
+		result T
+		err    error
+	}
+	responses := make(chan response, len(providers))
+	for _, p := range providers {
+		go func(p RpcProvider) {
+			result, err := retry(ctx, mp, []RpcProvider{p}, call)
+			responses <- response{result, err}
+		}(p)
+	}
+
+	counts := map[string]int{}
+	var lastErr error
+	for range providers {
+		resp := <-responses
+		var key []byte
+		var err error
+		if resp.err != nil {
+			lastErr = resp.err
+			if isRetryable(resp.err) {
+				continue
+			}
+			key, err = json.Marshal(struct{ Err string }{resp.err.Error()})
+		} else {
+			key, err = json.Marshal(resp.result)
+		}
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		counts[string(key)]++
+		if counts[string(key)] >= quorum {
+			return resp.result, resp.err
+		}
+	}
+
+	var zero T
+	if lastErr != nil {
+		return zero, fmt.Errorf("%w: %v", ErrNoQuorum, lastErr)
+	}
+	return zero, ErrNoQuorum
+}
+
+// AddInvokeTransaction sends the invoke transaction to a single provider, moving to the next one only when it could not be reached.
+func (mp *MultiProvider) AddInvokeTransaction(ctx context.Context, invokeTxn BroadcastInvokeTxnType) (*AddInvokeTransactionResponse, error) {
+	return execute(ctx, mp, false, func(p RpcProvider) (*AddInvokeTransactionResponse, error) {
+		return p.AddInvokeTransaction(ctx, invokeTxn)
+	})
+}
+
+// AddDeclareTransaction sends the declare transaction to a single provider, moving to the next one only when it could not be reached.
+func (mp *MultiProvider) AddDeclareTransaction(ctx context.Context, declareTransaction BroadcastDeclareTxnType) (*AddDeclareTransactionResponse, error) {
+	return execute(ctx, mp, false, func(p RpcProvider) (*AddDeclareTransactionResponse, error) {
+		return p.AddDeclareTransaction(ctx, declareTransaction)
+	})
+}
+
+// AddDeployAccountTransaction sends the deploy account transaction to a single provider, moving to the next one only when it could not be reached.
+func (mp *MultiProvider) AddDeployAccountTransaction(ctx context.Context, deployAccountTransaction BroadcastAddDeployTxnType) (*AddDeployAccountTransactionResponse, error) {
+	return execute(ctx, mp, false, func(p RpcProvider) (*AddDeployAccountTransactionResponse, error) {
+		return p.AddDeployAccountTransaction(ctx, deployAccountTransaction)
+	})
+}
+
+// BlockHashAndNumber implements RpcProvider.
+func (mp *MultiProvider) BlockHashAndNumber(ctx context.Context) (*BlockHashAndNumberOutput, error) {
+	return execute(ctx, mp, true, func(p RpcProvider) (*BlockHashAndNumberOutput, error) {
+		return p.BlockHashAndNumber(ctx)
+	})
+}
+
+// BlockNumber implements RpcProvider.
+func (mp *MultiProvider) BlockNumber(ctx context.Context) (uint64, error) {
+	return execute(ctx, mp, true, func(p RpcProvider) (uint64, error) {
+		return p.BlockNumber(ctx)
+	})
+}
+
+// BlockTransactionCount implements RpcProvider.
+func (mp *MultiProvider) BlockTransactionCount(ctx context.Context, blockID BlockID) (uint64, error) {
+	return execute(ctx, mp, true, func(p RpcProvider) (uint64, error) {
+		return p.BlockTransactionCount(ctx, blockID)
+	})
+}
+
+// BlockWithTxHashes implements RpcProvider.
+func (mp *MultiProvider) BlockWithTxHashes(ctx context.Context, blockID BlockID) (interface{}, error) {
+	return execute(ctx, mp, true, func(p RpcProvider) (interface{}, error) {
+		return p.BlockWithTxHashes(ctx, blockID)
+	})
+}
+
+// BlockWithTxs implements RpcProvider.
+func (mp *MultiProvider) BlockWithTxs(ctx context.Context, blockID BlockID) (interface{}, error) {
+	return execute(ctx, mp, true, func(p RpcProvider) (interface{}, error) {
+		return p.BlockWithTxs(ctx, blockID)
+	})
+}
+
+// BlockWithReceipts implements RpcProvider.
+func (mp *MultiProvider) BlockWithReceipts(ctx context.Context, blockID BlockID) (interface{}, error) {
+	return execute(ctx, mp, true, func(p RpcProvider) (interface{}, error) {
+		return p.BlockWithReceipts(ctx, blockID)
+	})
+}
+
+// Call implements RpcProvider.
+func (mp *MultiProvider) Call(ctx context.Context, call FunctionCall, block BlockID) ([]*felt.Felt, error) {
+	return execute(ctx, mp, true, func(p RpcProvider) ([]*felt.Felt, error) {
+		return p.Call(ctx, call, block)
+	})
+}
+
+// ChainID implements RpcProvider.
+func (mp *MultiProvider) ChainID(ctx context.Context) (string, error) {
+	return execute(ctx, mp, true, func(p RpcProvider) (string, error) {
+		return p.ChainID(ctx)
+	})
+}
+
+// Class implements RpcProvider.
+func (mp *MultiProvider) Class(ctx context.Context, blockID BlockID, classHash *felt.Felt) (ClassOutput, error) {
+	return execute(ctx, mp, true, func(p RpcProvider) (ClassOutput, error) {
+		return p.Class(ctx, blockID, classHash)
+	})
+}
+
+// ClassAt implements RpcProvider.
+func (mp *MultiProvider) ClassAt(ctx context.Context, blockID BlockID, contractAddress *felt.Felt) (ClassOutput, error) {
+	return execute(ctx, mp, true, func(p RpcProvider) (ClassOutput, error) {
+		return p.ClassAt(ctx, blockID, contractAddress)
+	})
+}
+
+// ClassHashAt implements RpcProvider.
+func (mp *MultiProvider) ClassHashAt(ctx context.Context, blockID BlockID, contractAddress *felt.Felt) (*felt.Felt, error) {
+	return execute(ctx, mp, true, func(p RpcProvider) (*felt.Felt, error) {
+		return p.ClassHashAt(ctx, blockID, contractAddress)
+	})
+}
+
+// EstimateFee implements RpcProvider.
+func (mp *MultiProvider) EstimateFee(ctx context.Context, requests []BroadcastTxn, simulationFlags []SimulationFlag, blockID BlockID) ([]FeeEstimate, error) {
+	return execute(ctx, mp, true, func(p RpcProvider) ([]FeeEstimate, error) {
+		return p.EstimateFee(ctx, requests, simulationFlags, blockID)
+	})
+}
+
+// EstimateMessageFee implements RpcProvider.
+func (mp *MultiProvider) EstimateMessageFee(ctx context.Context, msg MsgFromL1, blockID BlockID) (*FeeEstimate, error) {
+	return execute(ctx, mp, true, func(p RpcProvider) (*FeeEstimate, error) {
+		return p.EstimateMessageFee(ctx, msg, blockID)
+	})
+}
+
+// Events implements RpcProvider.
+func (mp *MultiProvider) Events(ctx context.Context, input EventsInput) (*EventChunk, error) {
+	return execute(ctx, mp, true, func(p RpcProvider) (*EventChunk, error) {
+		return p.Events(ctx, input)
+	})
+}
+
+// GetTransactionStatus implements RpcProvider.
+func (mp *MultiProvider) GetTransactionStatus(ctx context.Context, transactionHash *felt.Felt) (*TxnStatusResp, error) {
+	return execute(ctx, mp, true, func(p RpcProvider) (*TxnStatusResp, error) {
+		return p.GetTransactionStatus(ctx, transactionHash)
+	})
+}
+
+// Nonce implements RpcProvider.
+func (mp *MultiProvider) Nonce(ctx context.Context, blockID BlockID, contractAddress *felt.Felt) (*felt.Felt, error) {
+	return execute(ctx, mp, true, func(p RpcProvider) (*felt.Felt, error) {
+		return p.Nonce(ctx, blockID, contractAddress)
+	})
+}
+
+// SimulateTransactions implements RpcProvider.
+func (mp *MultiProvider) SimulateTransactions(ctx context.Context, blockID BlockID, txns []Transaction, simulationFlags []SimulationFlag) ([]SimulatedTransaction, error) {
+	return execute(ctx, mp, true, func(p RpcProvider) ([]SimulatedTransaction, error) {
+		return p.SimulateTransactions(ctx, blockID, txns, simulationFlags)
+	})
+}
+
+// StateUpdate implements RpcProvider.
+func (mp *MultiProvider) StateUpdate(ctx context.Context, blockID BlockID) (*StateUpdateOutput, error) {
+	return execute(ctx, mp, true, func(p RpcProvider) (*StateUpdateOutput, error) {
+		return p.StateUpdate(ctx, blockID)
+	})
+}
+
+// StorageAt implements RpcProvider.
+func (mp *MultiProvider) StorageAt(ctx context.Context, contractAddress *felt.Felt, key string, blockID BlockID) (string, error) {
+	return execute(ctx, mp, true, func(p RpcProvider) (string, error) {
+		return p.StorageAt(ctx, contractAddress, key, blockID)
+	})
+}
+
+// SpecVersion implements RpcProvider.
+func (mp *MultiProvider) SpecVersion(ctx context.Context) (string, error) {
+	return execute(ctx, mp, true, func(p RpcProvider) (string, error) {
+		return p.SpecVersion(ctx)
+	})
+}
+
+// Syncing implements RpcProvider.
+func (mp *MultiProvider) Syncing(ctx context.Context) (*SyncStatus, error) {
+	return execute(ctx, mp, true, func(p RpcProvider) (*SyncStatus, error) {
+		return p.Syncing(ctx)
+	})
+}
+
+// TraceBlockTransactions implements RpcProvider.
+func (mp *MultiProvider) TraceBlockTransactions(ctx context.Context, blockID BlockID) ([]Trace, error) {
+	return execute(ctx, mp, true, func(p RpcProvider) ([]Trace, error) {
+		return p.TraceBlockTransactions(ctx, blockID)
+	})
+}
+
+// TransactionByBlockIdAndIndex implements RpcProvider.
+func (mp *MultiProvider) TransactionByBlockIdAndIndex(ctx context.Context, blockID BlockID, index uint64) (Transaction, error) {
+	return execute(ctx, mp, true, func(p RpcProvider) (Transaction, error) {
+		return p.TransactionByBlockIdAndIndex(ctx, blockID, index)
+	})
+}
+
+// TransactionByHash implements RpcProvider.
+func (mp *MultiProvider) TransactionByHash(ctx context.Context, hash *felt.Felt) (Transaction, error) {
+	return execute(ctx, mp, true, func(p RpcProvider) (Transaction, error) {
+		return p.TransactionByHash(ctx, hash)
+	})
+}
+
+// TransactionReceipt implements RpcProvider.
+func (mp *MultiProvider) TransactionReceipt(ctx context.Context, transactionHash *felt.Felt) (*TransactionReceiptWithBlockInfo, error) {
+	return execute(ctx, mp, true, func(p RpcProvider) (*TransactionReceiptWithBlockInfo, error) {
+		return p.TransactionReceipt(ctx, transactionHash)
+	})
+}
+
+// TraceTransaction implements RpcProvider.
+func (mp *MultiProvider) TraceTransaction(ctx context.Context, transactionHash *felt.Felt) (TxnTrace, error) {
+	return execute(ctx, mp, true, func(p RpcProvider) (TxnTrace, error) {
+		return p.TraceTransaction(ctx, transactionHash)
+	})
+}
@@ -0,0 +1,267 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedCloser is a CallCloser counting the calls, failing the first ones
+// and answering some methods with fixed results before falling back to the mock.
+// The spec version negotiation is not counted.
+type scriptedCloser struct {
+	mu       sync.Mutex
+	calls    int
+	failures int
+	err      error
+	results  map[string]string
+	args     [][]interface{}
+}
+
+func (c *scriptedCloser) Close() {}
+
+func (c *scriptedCloser) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if method == "starknet_specVersion" {
+		if raw, ok := c.results[method]; ok {
+			return json.Unmarshal([]byte(raw), result)
+		}
+		return json.Unmarshal([]byte(`"0.7.1"`), result)
+	}
+	c.mu.Lock()
+	c.calls++
+	c.args = append(c.args, args)
+	failing := c.failures == -1 || c.calls <= c.failures
+	c.mu.Unlock()
+	if failing {
+		if c.err != nil {
+			return c.err
+		}
+		return errors.New("connection refused")
+	}
+	if raw, ok := c.results[method]; ok {
+		return json.Unmarshal([]byte(raw), result)
+	}
+	return (&rpcMock{}).CallContext(ctx, result, method, args...)
+}
+
+func (c *scriptedCloser) callCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
+
+// newScriptedProviders creates a Provider on top of each scriptedCloser.
+func newScriptedProviders(closers ...*scriptedCloser) []RpcProvider {
+	providers := make([]RpcProvider, len(closers))
+	for i, c := range closers {
+		providers[i] = &Provider{c: c}
+	}
+	return providers
+}
+
+func TestMultiProviderRoundRobin(t *testing.T) {
+	results := map[string]string{"starknet_blockNumber": "1234"}
+	first, second := &scriptedCloser{results: results}, &scriptedCloser{results: results}
+	mp, err := NewMultiProvider(newScriptedProviders(first, second), MultiProviderOptions{Policy: PolicyRoundRobin})
+	require.NoError(t, err)
+	defer mp.Close()
+
+	for i := 0; i < 4; i++ {
+		blockNumber, err := mp.BlockNumber(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, uint64(1234), blockNumber)
+	}
+	require.Equal(t, 2, first.callCount())
+	require.Equal(t, 2, second.callCount())
+}
+
+func TestMultiProviderPrimaryFallback(t *testing.T) {
+	type testSetType struct {
+		Primary         *scriptedCloser
+		ExpectedErr     error
+		ExpectedPrimary int
+		ExpectedBackup  int
+	}
+	testSet := []testSetType{
+		{
+			// a healthy primary gets all the calls
+			Primary:         &scriptedCloser{},
+			ExpectedPrimary: 1,
+			ExpectedBackup:  0,
+		},
+		{
+			// transport errors are retried on the backup
+			Primary:         &scriptedCloser{failures: -1},
+			ExpectedPrimary: 2,
+			ExpectedBackup:  2,
+		},
+		{
+			// errors of the spec are not retried
+			Primary:         &scriptedCloser{failures: -1, err: ErrContractNotFound},
+			ExpectedErr:     ErrContractNotFound,
+			ExpectedPrimary: 1,
+			ExpectedBackup:  0,
+		},
+	}
+
+	for _, test := range testSet {
+		backup := &scriptedCloser{failures: -1}
+		mp, err := NewMultiProvider(newScriptedProviders(test.Primary, backup), MultiProviderOptions{
+			Policy:         PolicyPrimaryFallback,
+			InitialBackoff: time.Millisecond,
+		})
+		require.NoError(t, err)
+
+		_, err = mp.Nonce(context.Background(), BlockID{Tag: "latest"}, new(felt.Felt).SetUint64(1))
+		if test.ExpectedErr != nil {
+			require.Equal(t, test.ExpectedErr, err)
+		} else if test.ExpectedBackup > 0 {
+			require.Error(t, err)
+		} else {
+			require.NoError(t, err)
+		}
+		require.Equal(t, test.ExpectedPrimary, test.Primary.callCount())
+		require.Equal(t, test.ExpectedBackup, backup.callCount())
+	}
+
+	results := map[string]string{"starknet_blockNumber": "1234"}
+	primary, backup := &scriptedCloser{failures: 1, results: results}, &scriptedCloser{results: results}
+	mp, err := NewMultiProvider(newScriptedProviders(primary, backup), MultiProviderOptions{
+		Policy:         PolicyPrimaryFallback,
+		InitialBackoff: time.Millisecond,
+	})
+	require.NoError(t, err)
+	blockNumber, err := mp.BlockNumber(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(1234), blockNumber)
+	require.Equal(t, 1, primary.callCount())
+	require.Equal(t, 1, backup.callCount())
+}
+
+func TestMultiProviderAddTransactionNotRetried(t *testing.T) {
+	primary, backup := &scriptedCloser{failures: -1}, &scriptedCloser{}
+	mp, err := NewMultiProvider(newScriptedProviders(primary, backup), MultiProviderOptions{
+		Policy:         PolicyPrimaryFallback,
+		InitialBackoff: time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	_, err = mp.AddInvokeTransaction(context.Background(), BroadcastInvokev1Txn{})
+	require.Error(t, err)
+	require.Equal(t, 1, primary.callCount())
+	require.Equal(t, 0, backup.callCount())
+}
+
+func TestMultiProviderAddTransactionNotSent(t *testing.T) {
+	refused := &url.Error{Op: "Post", URL: "http://localhost:1", Err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}}
+	primary, backup := &scriptedCloser{failures: -1, err: refused}, &scriptedCloser{}
+	mp, err := NewMultiProvider(newScriptedProviders(primary, backup), MultiProviderOptions{Policy: PolicyPrimaryFallback})
+	require.NoError(t, err)
+
+	_, err = mp.AddInvokeTransaction(context.Background(), BroadcastInvokev1Txn{})
+	require.NoError(t, err)
+	require.Equal(t, 1, primary.callCount())
+	require.Equal(t, 1, backup.callCount())
+
+	// a node that cannot be dialed at all
+	server := httptest.NewServer(http.NotFoundHandler())
+	unreachable, err := NewProvider(server.URL)
+	require.NoError(t, err)
+	server.Close()
+	backup = &scriptedCloser{}
+	mp, err = NewMultiProvider(append([]RpcProvider{unreachable}, newScriptedProviders(backup)...), MultiProviderOptions{Policy: PolicyPrimaryFallback})
+	require.NoError(t, err)
+
+	_, err = mp.AddInvokeTransaction(context.Background(), BroadcastInvokev1Txn{})
+	require.NoError(t, err)
+	require.Equal(t, 1, backup.callCount())
+}
+
+func TestMultiProviderQuorum(t *testing.T) {
+	honest := func() *scriptedCloser {
+		return &scriptedCloser{results: map[string]string{"starknet_blockNumber": "100"}}
+	}
+	liar := &scriptedCloser{results: map[string]string{"starknet_blockNumber": "999"}}
+
+	mp, err := NewMultiProvider(newScriptedProviders(honest(), liar, honest()), MultiProviderOptions{Policy: PolicyQuorum})
+	require.NoError(t, err)
+	blockNumber, err := mp.BlockNumber(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), blockNumber)
+
+	mp, err = NewMultiProvider(newScriptedProviders(honest(), liar, honest()), MultiProviderOptions{Policy: PolicyQuorum, Quorum: 3})
+	require.NoError(t, err)
+	_, err = mp.BlockNumber(context.Background())
+	require.ErrorIs(t, err, ErrNoQuorum)
+
+	// a configured quorum is not lowered when too few providers are healthy
+	tip := &scriptedCloser{results: map[string]string{"starknet_blockNumber": "100", "starknet_syncing": "false"}}
+	behind := &scriptedCloser{results: map[string]string{"starknet_blockNumber": "90", "starknet_syncing": "false"}}
+	down := &scriptedCloser{failures: -1}
+	mp, err = NewMultiProvider(newScriptedProviders(tip, behind, down), MultiProviderOptions{Policy: PolicyQuorum, Quorum: 2, MaxBlockLag: 5, MaxRetries: -1})
+	require.NoError(t, err)
+	mp.CheckHealth(context.Background())
+	_, err = mp.BlockNumber(context.Background())
+	require.ErrorIs(t, err, ErrNoQuorum)
+
+	mp, err = NewMultiProvider(newScriptedProviders(tip, behind, down), MultiProviderOptions{Policy: PolicyQuorum, MaxBlockLag: 5, MaxRetries: -1})
+	require.NoError(t, err)
+	mp.CheckHealth(context.Background())
+	blockNumber, err = mp.BlockNumber(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), blockNumber)
+}
+
+func TestMultiProviderHealthCheck(t *testing.T) {
+	tip := &scriptedCloser{results: map[string]string{"starknet_blockNumber": "100", "starknet_syncing": "false"}}
+	behind := &scriptedCloser{results: map[string]string{"starknet_blockNumber": "90", "starknet_syncing": "false"}}
+	syncing := &scriptedCloser{results: map[string]string{
+		"starknet_blockNumber": "100",
+		"starknet_syncing":     `{"starting_block_num":1,"current_block_num":16,"highest_block_num":100}`,
+	}}
+	down := &scriptedCloser{failures: -1}
+	// the older nodes send the block numbers as hex strings
+	catchingUp := &scriptedCloser{results: map[string]string{
+		"starknet_blockNumber": "100",
+		"starknet_syncing":     `{"starting_block_num":"0x1","current_block_num":"0x5f","highest_block_num":"0x64"}`,
+	}}
+
+	mp, err := NewMultiProvider(newScriptedProviders(tip, behind, syncing, down, catchingUp), MultiProviderOptions{
+		Policy:      PolicyRoundRobin,
+		MaxBlockLag: 5,
+	})
+	require.NoError(t, err)
+	mp.CheckHealth(context.Background())
+
+	health := mp.Health()
+	require.True(t, health[0].Healthy)
+	require.Equal(t, uint64(100), health[0].BlockNumber)
+	require.False(t, health[1].Healthy)
+	require.Error(t, health[1].Err)
+	require.False(t, health[2].Healthy)
+	require.ErrorContains(t, health[2].Err, "syncing, 84 blocks behind")
+	require.False(t, health[3].Healthy)
+	require.True(t, health[4].Healthy)
+
+	before := []int{tip.callCount(), behind.callCount(), syncing.callCount(), down.callCount(), catchingUp.callCount()}
+	for i := 0; i < 4; i++ {
+		_, err := mp.BlockNumber(context.Background())
+		require.NoError(t, err)
+	}
+	require.Equal(t, before[0]+2, tip.callCount())
+	require.Equal(t, before[4]+2, catchingUp.callCount())
+	require.Equal(t, before[1], behind.callCount())
+	require.Equal(t, before[2], syncing.callCount())
+	require.Equal(t, before[3], down.callCount())
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/cookiejar"
+	"sync"
 
 	"github.com/NethermindEth/juno/core/felt"
 	ethrpc "github.com/ethereum/go-ethereum/rpc"
@@ -18,8 +19,11 @@ var (
 
 // Provider provides the provider for starknet.go/rpc implementation.
 type Provider struct {
-	c       callCloser
+	c       CallCloser
 	chainID string
+
+	specMu       sync.Mutex
+	capabilities *Capabilities
 }
 
 // NewProvider creates a new rpc Provider instance.
@@ -40,6 +44,17 @@ func NewProvider(url string, options ...ethrpc.ClientOption) (*Provider, error)
 	return &Provider{c: c}, nil
 }
 
+// NewProviderWithTransport creates a new rpc Provider sending its calls through the given transport,
+// such as a recording or replaying transport of the rpctest package.
+//
+// Parameters:
+// - transport: the CallCloser the calls are sent to
+// Returns:
+// - *Provider: the provider
+func NewProviderWithTransport(transport CallCloser) *Provider {
+	return &Provider{c: transport}
+}
+
 //go:generate mockgen -destination=../mocks/mock_rpc_provider.go -package=mocks -source=provider.go api
 type RpcProvider interface {
 	AddInvokeTransaction(ctx context.Context, invokeTxn BroadcastInvokeTxnType) (*AddInvokeTransactionResponse, error)
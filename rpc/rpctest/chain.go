@@ -0,0 +1,711 @@
+package rpctest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/hash"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// Txn is a transaction sent to the server, with the fields it looks at.
+type Txn struct {
+	Type                rpc.TransactionType        `json:"type"`
+	Version             rpc.TransactionVersion     `json:"version"`
+	SenderAddress       *felt.Felt                 `json:"sender_address,omitempty"`
+	Nonce               *felt.Felt                 `json:"nonce,omitempty"`
+	MaxFee              *felt.Felt                 `json:"max_fee,omitempty"`
+	ResourceBounds      *rpc.ResourceBoundsMapping `json:"resource_bounds,omitempty"`
+	Calldata            []*felt.Felt               `json:"calldata,omitempty"`
+	ClassHash           *felt.Felt                 `json:"class_hash,omitempty"`
+	ContractAddressSalt *felt.Felt                 `json:"contract_address_salt,omitempty"`
+	ConstructorCalldata []*felt.Felt               `json:"constructor_calldata,omitempty"`
+	ContractClass       json.RawMessage            `json:"contract_class,omitempty"`
+}
+
+// IsV3 returns true for the transactions paying their fee in STRK.
+func (txn Txn) IsV3() bool {
+	return txn.Version == rpc.TransactionV3 || txn.Version == rpc.TransactionV3WithQueryBit
+}
+
+// maxFee returns the maximum fee the sender agrees to pay, nil if the transaction does not give it.
+func (txn Txn) maxFee() (*big.Int, error) {
+	if !txn.IsV3() {
+		if txn.MaxFee == nil {
+			return nil, nil
+		}
+		return txn.MaxFee.BigInt(new(big.Int)), nil
+	}
+	if txn.ResourceBounds == nil {
+		return nil, nil
+	}
+	amount, ok := new(big.Int).SetString(string(txn.ResourceBounds.L1Gas.MaxAmount), 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid l1_gas max_amount %q", txn.ResourceBounds.L1Gas.MaxAmount)
+	}
+	price, ok := new(big.Int).SetString(string(txn.ResourceBounds.L1Gas.MaxPricePerUnit), 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid l1_gas max_price_per_unit %q", txn.ResourceBounds.L1Gas.MaxPricePerUnit)
+	}
+	return amount.Mul(amount, price), nil
+}
+
+// DefaultFeeRule estimates every transaction at 1000 units of L1 gas priced 1, in FRI for the V3
+// transactions and in WEI for the others.
+//
+// Parameters:
+// - txn: the transaction
+// Returns:
+// - rpc.FeeEstimate: the fee estimate
+func DefaultFeeRule(txn Txn) rpc.FeeEstimate {
+	unit := rpc.UnitWei
+	if txn.IsV3() {
+		unit = rpc.UnitStrk
+	}
+	return rpc.FeeEstimate{
+		GasConsumed:     new(felt.Felt).SetUint64(1000),
+		GasPrice:        new(felt.Felt).SetUint64(1),
+		DataGasConsumed: new(felt.Felt),
+		DataGasPrice:    new(felt.Felt).SetUint64(1),
+		OverallFee:      new(felt.Felt).SetUint64(1000),
+		FeeUnit:         unit,
+	}
+}
+
+// contract is a deployed contract.
+type contract struct {
+	classHash *felt.Felt
+	nonce     *felt.Felt
+	storage   map[felt.Felt]*felt.Felt
+}
+
+// block is a block of the chain.
+type block struct {
+	header rpc.BlockHeader
+	txns   []*felt.Felt
+}
+
+// txnRecord is an accepted transaction.
+type txnRecord struct {
+	txn     map[string]interface{}
+	receipt receipt
+}
+
+// receipt is the receipt of an accepted transaction, with the block including it.
+type receipt struct {
+	rpc.CommonTransactionReceipt
+	ContractAddress *felt.Felt `json:"contract_address,omitempty"`
+	BlockHash       *felt.Felt `json:"block_hash"`
+	BlockNumber     uint64     `json:"block_number"`
+}
+
+// callKey identifies the function of a contract.
+type callKey struct {
+	address  felt.Felt
+	selector felt.Felt
+}
+
+// chainMethods answers the methods of the in-memory chain. They are called with the lock of the server held.
+var chainMethods = map[string]func(s *Server, params []json.RawMessage) (interface{}, error){
+	"starknet_specVersion":                 (*Server).specVersion,
+	"starknet_chainId":                     (*Server).chainIDMethod,
+	"starknet_blockNumber":                 (*Server).blockNumber,
+	"starknet_blockHashAndNumber":          (*Server).blockHashAndNumber,
+	"starknet_getBlockWithTxHashes":        (*Server).blockWithTxHashes,
+	"starknet_getBlockWithTxs":             (*Server).blockWithTxs,
+	"starknet_getNonce":                    (*Server).nonce,
+	"starknet_getStorageAt":                (*Server).storageAt,
+	"starknet_getClassHashAt":              (*Server).classHashAt,
+	"starknet_getClass":                    (*Server).class,
+	"starknet_call":                        (*Server).call,
+	"starknet_estimateFee":                 (*Server).estimateFee,
+	"starknet_addInvokeTransaction":        (*Server).addInvokeTransaction,
+	"starknet_addDeployAccountTransaction": (*Server).addDeployAccountTransaction,
+	"starknet_addDeclareTransaction":       (*Server).addDeclareTransaction,
+	"starknet_getTransactionByHash":        (*Server).transactionByHash,
+	"starknet_getTransactionReceipt":       (*Server).transactionReceipt,
+	"starknet_getTransactionStatus":        (*Server).transactionStatus,
+}
+
+// AddContract deploys a contract, such as an account, with a zero nonce and an empty storage.
+//
+// Parameters:
+// - address: the address of the contract
+// - classHash: the class hash of the contract
+func (s *Server) AddContract(address, classHash *felt.Felt) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.contracts[*address] = &contract{classHash: classHash, nonce: new(felt.Felt), storage: make(map[felt.Felt]*felt.Felt)}
+}
+
+// SetNonce sets the nonce of a contract, deploying it with a zero class hash if needed.
+func (s *Server) SetNonce(address, nonce *felt.Felt) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.contract(address).nonce = nonce
+}
+
+// Nonce returns the nonce of a contract, nil if it is not deployed.
+func (s *Server) Nonce(address *felt.Felt) *felt.Felt {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.contracts[*address]; ok {
+		return c.nonce
+	}
+	return nil
+}
+
+// SetStorage sets a storage value of a contract, deploying it with a zero class hash if needed.
+func (s *Server) SetStorage(address, key, value *felt.Felt) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.contract(address).storage[*key] = value
+}
+
+// SetCallResult sets the result of the starknet_call calls to a function of a contract, whatever their calldata.
+//
+// Parameters:
+// - address: the address of the contract
+// - selector: the selector of the function
+// - result: the values returned by the function
+func (s *Server) SetCallResult(address, selector *felt.Felt, result ...*felt.Felt) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls[callKey{address: *address, selector: *selector}] = result
+}
+
+// SetEventRule sets the rule giving the events emitted by the accepted transactions, which emit no event by default.
+func (s *Server) SetEventRule(rule EventRule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.eventRule = rule
+}
+
+// MineBlock adds an empty block to the chain.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - uint64: the number of the new block
+func (s *Server) MineBlock() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mineBlock(nil).header.BlockNumber
+}
+
+// BlockNumber returns the number of the latest block.
+func (s *Server) BlockNumber() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.latest().header.BlockNumber
+}
+
+// contract returns a contract, deploying it with a zero class hash if needed.
+func (s *Server) contract(address *felt.Felt) *contract {
+	c, ok := s.contracts[*address]
+	if !ok {
+		c = &contract{classHash: new(felt.Felt), nonce: new(felt.Felt), storage: make(map[felt.Felt]*felt.Felt)}
+		s.contracts[*address] = c
+	}
+	return c
+}
+
+// latest returns the latest block.
+func (s *Server) latest() *block {
+	return s.blocks[len(s.blocks)-1]
+}
+
+// mineBlock adds a block including the given transactions.
+func (s *Server) mineBlock(txns []*felt.Felt) *block {
+	number := uint64(len(s.blocks))
+	parentHash := new(felt.Felt)
+	if number > 0 {
+		parentHash = s.latest().header.BlockHash
+	}
+	blockHash, _ := curve.Curve.StarknetKeccak([]byte(fmt.Sprint("block ", number)))
+	b := &block{
+		header: rpc.BlockHeader{
+			BlockHash:        blockHash,
+			ParentHash:       parentHash,
+			BlockNumber:      number,
+			NewRoot:          new(felt.Felt),
+			Timestamp:        1700000000 + number,
+			SequencerAddress: new(felt.Felt),
+			L1GasPrice:       rpc.ResourcePrice{PriceInFRI: new(felt.Felt).SetUint64(1), PriceInWei: new(felt.Felt).SetUint64(1)},
+			L1DataGasPrice:   rpc.ResourcePrice{PriceInFRI: new(felt.Felt).SetUint64(1), PriceInWei: new(felt.Felt).SetUint64(1)},
+			L1DAMode:         rpc.L1DAModeBlob,
+			StarknetVersion:  "0.13.1",
+		},
+		txns: txns,
+	}
+	if b.txns == nil {
+		b.txns = []*felt.Felt{}
+	}
+	s.blocks = append(s.blocks, b)
+	return b
+}
+
+// findBlock returns the block of a block id, the pending block being the latest one.
+func (s *Server) findBlock(param json.RawMessage) (*block, error) {
+	var tag string
+	if json.Unmarshal(param, &tag) == nil {
+		if tag == "latest" || tag == "pending" {
+			return s.latest(), nil
+		}
+		return nil, rpc.Err(rpc.InvalidParams, fmt.Sprintf("invalid block tag %q", tag))
+	}
+
+	var id struct {
+		Number *uint64    `json:"block_number"`
+		Hash   *felt.Felt `json:"block_hash"`
+	}
+	if err := json.Unmarshal(param, &id); err != nil {
+		return nil, rpc.Err(rpc.InvalidParams, err.Error())
+	}
+	switch {
+	case id.Number != nil:
+		if *id.Number < uint64(len(s.blocks)) {
+			return s.blocks[*id.Number], nil
+		}
+	case id.Hash != nil:
+		for _, b := range s.blocks {
+			if b.header.BlockHash.Equal(id.Hash) {
+				return b, nil
+			}
+		}
+	default:
+		return nil, rpc.Err(rpc.InvalidParams, "invalid block id")
+	}
+	return nil, rpc.ErrBlockNotFound
+}
+
+// isPending returns true if a block id is the pending tag.
+func isPending(param json.RawMessage) bool {
+	return string(bytes.TrimSpace(param)) == `"pending"`
+}
+
+// stateBlock checks the count of the parameters of a state query, and the block id at the given position.
+func (s *Server) stateBlock(params []json.RawMessage, count, position int) error {
+	if len(params) != count {
+		return rpc.Err(rpc.InvalidParams, fmt.Sprintf("expected %d parameters, got %d", count, len(params)))
+	}
+	_, err := s.findBlock(params[position])
+	return err
+}
+
+func (s *Server) specVersion(params []json.RawMessage) (interface{}, error) {
+	return s.version, nil
+}
+
+func (s *Server) chainIDMethod(params []json.RawMessage) (interface{}, error) {
+	return encodeShortString(s.chainID), nil
+}
+
+func (s *Server) blockNumber(params []json.RawMessage) (interface{}, error) {
+	return s.latest().header.BlockNumber, nil
+}
+
+func (s *Server) blockHashAndNumber(params []json.RawMessage) (interface{}, error) {
+	latest := s.latest()
+	return rpc.BlockHashAndNumberOutput{BlockNumber: latest.header.BlockNumber, BlockHash: latest.header.BlockHash}, nil
+}
+
+// blockHeader returns the header of a block id, as a pending block header for the pending tag.
+func (s *Server) blockHeader(params []json.RawMessage) (interface{}, *block, error) {
+	if len(params) != 1 {
+		return nil, nil, rpc.Err(rpc.InvalidParams, "expected a block id")
+	}
+	if isPending(params[0]) {
+		latest := s.latest().header
+		return rpc.PendingBlockHeader{
+			ParentHash:       latest.BlockHash,
+			Timestamp:        latest.Timestamp + 1,
+			SequencerAddress: latest.SequencerAddress,
+			L1GasPrice:       latest.L1GasPrice,
+			StarknetVersion:  latest.StarknetVersion,
+			L1DataGasPrice:   latest.L1DataGasPrice,
+			L1DAMode:         latest.L1DAMode,
+		}, &block{txns: []*felt.Felt{}}, nil
+	}
+	b, err := s.findBlock(params[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	return b.header, b, nil
+}
+
+func (s *Server) blockWithTxHashes(params []json.RawMessage) (interface{}, error) {
+	header, b, err := s.blockHeader(params)
+	if err != nil {
+		return nil, err
+	}
+	if pending, ok := header.(rpc.PendingBlockHeader); ok {
+		return rpc.PendingBlockTxHashes{PendingBlockHeader: pending, Transactions: b.txns}, nil
+	}
+	return rpc.BlockTxHashes{BlockHeader: b.header, Status: rpc.BlockStatus_AcceptedOnL2, Transactions: b.txns}, nil
+}
+
+func (s *Server) blockWithTxs(params []json.RawMessage) (interface{}, error) {
+	header, b, err := s.blockHeader(params)
+	if err != nil {
+		return nil, err
+	}
+	txns := make([]map[string]interface{}, len(b.txns))
+	for i, txnHash := range b.txns {
+		txns[i] = s.txns[*txnHash].txn
+	}
+	result := map[string]interface{}{}
+	if err := remarshal(header, &result); err != nil {
+		return nil, err
+	}
+	if _, ok := header.(rpc.PendingBlockHeader); !ok {
+		result["status"] = rpc.BlockStatus_AcceptedOnL2
+	}
+	result["transactions"] = txns
+	return result, nil
+}
+
+func (s *Server) nonce(params []json.RawMessage) (interface{}, error) {
+	if err := s.stateBlock(params, 2, 0); err != nil {
+		return nil, err
+	}
+	c, err := s.deployed(params[1])
+	if err != nil {
+		return nil, err
+	}
+	return c.nonce, nil
+}
+
+func (s *Server) storageAt(params []json.RawMessage) (interface{}, error) {
+	if err := s.stateBlock(params, 3, 2); err != nil {
+		return nil, err
+	}
+	c, err := s.deployed(params[0])
+	if err != nil {
+		return nil, err
+	}
+	var key felt.Felt
+	if err := json.Unmarshal(params[1], &key); err != nil {
+		return nil, rpc.Err(rpc.InvalidParams, err.Error())
+	}
+	if value, ok := c.storage[key]; ok {
+		return value, nil
+	}
+	return new(felt.Felt), nil
+}
+
+func (s *Server) classHashAt(params []json.RawMessage) (interface{}, error) {
+	if err := s.stateBlock(params, 2, 0); err != nil {
+		return nil, err
+	}
+	c, err := s.deployed(params[1])
+	if err != nil {
+		return nil, err
+	}
+	return c.classHash, nil
+}
+
+func (s *Server) class(params []json.RawMessage) (interface{}, error) {
+	if err := s.stateBlock(params, 2, 0); err != nil {
+		return nil, err
+	}
+	var classHash felt.Felt
+	if err := json.Unmarshal(params[1], &classHash); err != nil {
+		return nil, rpc.Err(rpc.InvalidParams, err.Error())
+	}
+	class, ok := s.classes[classHash]
+	if !ok {
+		return nil, rpc.ErrClassHashNotFound
+	}
+	return class, nil
+}
+
+func (s *Server) call(params []json.RawMessage) (interface{}, error) {
+	if err := s.stateBlock(params, 2, 1); err != nil {
+		return nil, err
+	}
+	var call rpc.FunctionCall
+	if err := json.Unmarshal(params[0], &call); err != nil || call.ContractAddress == nil || call.EntryPointSelector == nil {
+		return nil, rpc.Err(rpc.InvalidParams, "invalid function call")
+	}
+	if _, ok := s.contracts[*call.ContractAddress]; !ok {
+		return nil, rpc.ErrContractNotFound
+	}
+	result, ok := s.calls[callKey{address: *call.ContractAddress, selector: *call.EntryPointSelector}]
+	if !ok {
+		return nil, withData(rpc.ErrContractError, map[string]string{"revert_error": "Entry point not found in contract"})
+	}
+	return result, nil
+}
+
+func (s *Server) estimateFee(params []json.RawMessage) (interface{}, error) {
+	if err := s.stateBlock(params, 3, 2); err != nil {
+		return nil, err
+	}
+	var requests []Txn
+	if err := json.Unmarshal(params[0], &requests); err != nil {
+		return nil, rpc.Err(rpc.InvalidParams, err.Error())
+	}
+	estimates := make([]rpc.FeeEstimate, len(requests))
+	for i, txn := range requests {
+		estimates[i] = s.feeRule(txn)
+	}
+	return estimates, nil
+}
+
+func (s *Server) addInvokeTransaction(params []json.RawMessage) (interface{}, error) {
+	txn, err := s.checkTxn(params, rpc.TransactionType_Invoke)
+	if err != nil {
+		return nil, err
+	}
+	if txn.SenderAddress == nil {
+		return nil, rpc.Err(rpc.InvalidParams, "missing sender_address")
+	}
+	sender, ok := s.contracts[*txn.SenderAddress]
+	if !ok {
+		return nil, withData(rpc.ErrValidationFailure, "the sender is not deployed")
+	}
+	if err := checkNonce(sender, txn); err != nil {
+		return nil, err
+	}
+
+	txnHash, err := s.accept(params[0], txn, nil)
+	if err != nil {
+		return nil, err
+	}
+	return rpc.AddInvokeTransactionResponse{TransactionHash: txnHash}, nil
+}
+
+func (s *Server) addDeployAccountTransaction(params []json.RawMessage) (interface{}, error) {
+	txn, err := s.checkTxn(params, rpc.TransactionType_DeployAccount)
+	if err != nil {
+		return nil, err
+	}
+	if txn.ClassHash == nil || txn.ContractAddressSalt == nil {
+		return nil, rpc.Err(rpc.InvalidParams, "missing class_hash or contract_address_salt")
+	}
+	address, err := contracts.PrecomputeAddress(new(felt.Felt), txn.ContractAddressSalt, txn.ClassHash, txn.ConstructorCalldata)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := s.contracts[*address]; ok {
+		return nil, withData(rpc.ErrValidationFailure, "the account is already deployed")
+	}
+	if !txn.Nonce.IsZero() {
+		return nil, rpc.ErrInvalidTransactionNonce
+	}
+
+	txnHash, err := s.accept(params[0], txn, address)
+	if err != nil {
+		return nil, err
+	}
+	s.contracts[*address] = &contract{classHash: txn.ClassHash, nonce: new(felt.Felt).SetUint64(1), storage: make(map[felt.Felt]*felt.Felt)}
+	return rpc.AddDeployAccountTransactionResponse{TransactionHash: txnHash, ContractAddress: address}, nil
+}
+
+func (s *Server) addDeclareTransaction(params []json.RawMessage) (interface{}, error) {
+	txn, err := s.checkTxn(params, rpc.TransactionType_Declare)
+	if err != nil {
+		return nil, err
+	}
+	if txn.Version == rpc.TransactionV1 {
+		return nil, rpc.ErrUnsupportedTxVersion
+	}
+	var class rpc.ContractClass
+	if err := json.Unmarshal(txn.ContractClass, &class); err != nil {
+		return nil, withData(rpc.ErrInvalidContractClass, err.Error())
+	}
+	classHash, err := hash.ClassHash(class)
+	if err != nil {
+		return nil, withData(rpc.ErrInvalidContractClass, err.Error())
+	}
+	if _, ok := s.classes[*classHash]; ok {
+		return nil, rpc.ErrClassAlreadyDeclared
+	}
+	if txn.SenderAddress == nil {
+		return nil, rpc.Err(rpc.InvalidParams, "missing sender_address")
+	}
+	sender, ok := s.contracts[*txn.SenderAddress]
+	if !ok {
+		return nil, withData(rpc.ErrValidationFailure, "the sender is not deployed")
+	}
+	if err := checkNonce(sender, txn); err != nil {
+		return nil, err
+	}
+
+	txnHash, err := s.accept(params[0], txn, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.classes[*classHash] = txn.ContractClass
+	return rpc.AddDeclareTransactionResponse{TransactionHash: txnHash, ClassHash: classHash}, nil
+}
+
+// checkTxn decodes the transaction of an add transaction call, checking its type, version and fee.
+func (s *Server) checkTxn(params []json.RawMessage, txnType rpc.TransactionType) (Txn, error) {
+	var txn Txn
+	if len(params) != 1 {
+		return txn, rpc.Err(rpc.InvalidParams, "expected a transaction")
+	}
+	if err := json.Unmarshal(params[0], &txn); err != nil {
+		return txn, rpc.Err(rpc.InvalidParams, err.Error())
+	}
+	if txn.Type != txnType {
+		return txn, rpc.Err(rpc.InvalidParams, fmt.Sprintf("expected a %s transaction, got %s", txnType, txn.Type))
+	}
+	if txn.Nonce == nil {
+		return txn, rpc.Err(rpc.InvalidParams, "missing nonce")
+	}
+	switch txn.Version {
+	case rpc.TransactionV1, rpc.TransactionV2, rpc.TransactionV3:
+	case rpc.TransactionV1WithQueryBit, rpc.TransactionV2WithQueryBit, rpc.TransactionV3WithQueryBit:
+		return txn, withData(rpc.ErrValidationFailure, "the query versions can only be estimated or simulated")
+	default:
+		return txn, rpc.ErrUnsupportedTxVersion
+	}
+
+	maxFee, err := txn.maxFee()
+	if err != nil {
+		return txn, rpc.Err(rpc.InvalidParams, err.Error())
+	}
+	if maxFee != nil && maxFee.Cmp(s.feeRule(txn).OverallFee.BigInt(new(big.Int))) < 0 {
+		return txn, rpc.ErrInsufficientMaxFee
+	}
+	return txn, nil
+}
+
+// checkNonce checks that the nonce of a transaction is the nonce of its sender.
+func checkNonce(sender *contract, txn Txn) error {
+	if !sender.nonce.Equal(txn.Nonce) {
+		return withData(rpc.ErrInvalidTransactionNonce, fmt.Sprintf("expected nonce %s, got %s", sender.nonce, txn.Nonce))
+	}
+	return nil
+}
+
+// accept includes a checked transaction in a new block, incrementing the nonce of its sender.
+// The transaction hash is not the one of the protocol: it is derived from the encoded transaction.
+func (s *Server) accept(raw json.RawMessage, txn Txn, contractAddress *felt.Felt) (*felt.Felt, error) {
+	canonical, err := canonicalParams(raw)
+	if err != nil {
+		return nil, rpc.Err(rpc.InvalidParams, err.Error())
+	}
+	txnHash, err := curve.Curve.StarknetKeccak(canonical)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := s.txns[*txnHash]; ok {
+		return nil, rpc.ErrDuplicateTx
+	}
+
+	record := &txnRecord{txn: map[string]interface{}{}}
+	if err := json.Unmarshal(canonical, &record.txn); err != nil {
+		return nil, err
+	}
+	delete(record.txn, "contract_class")
+	record.txn["transaction_hash"] = txnHash
+
+	if txn.SenderAddress != nil {
+		sender := s.contracts[*txn.SenderAddress]
+		sender.nonce = new(felt.Felt).Add(sender.nonce, new(felt.Felt).SetUint64(1))
+	}
+	b := s.mineBlock([]*felt.Felt{txnHash})
+
+	estimate := s.feeRule(txn)
+	events := []rpc.Event{}
+	if s.eventRule != nil {
+		events = append(events, s.eventRule(txn)...)
+	}
+	record.receipt = receipt{
+		CommonTransactionReceipt: rpc.CommonTransactionReceipt{
+			TransactionHash: txnHash,
+			ActualFee:       rpc.FeePayment{Amount: estimate.OverallFee, Unit: estimate.FeeUnit},
+			ExecutionStatus: rpc.TxnExecutionStatusSUCCEEDED,
+			FinalityStatus:  rpc.TxnFinalityStatusAcceptedOnL2,
+			Type:            txn.Type,
+			MessagesSent:    []rpc.MsgToL1{},
+			Events:          events,
+		},
+		ContractAddress: contractAddress,
+		BlockHash:       b.header.BlockHash,
+		BlockNumber:     b.header.BlockNumber,
+	}
+	s.txns[*txnHash] = record
+	return txnHash, nil
+}
+
+// findTxn returns the accepted transaction of a transaction hash parameter.
+func (s *Server) findTxn(params []json.RawMessage) (*txnRecord, error) {
+	var txnHash felt.Felt
+	if len(params) != 1 {
+		return nil, rpc.Err(rpc.InvalidParams, "expected a transaction hash")
+	}
+	if err := json.Unmarshal(params[0], &txnHash); err != nil {
+		return nil, rpc.Err(rpc.InvalidParams, err.Error())
+	}
+	record, ok := s.txns[txnHash]
+	if !ok {
+		return nil, rpc.ErrHashNotFound
+	}
+	return record, nil
+}
+
+func (s *Server) transactionByHash(params []json.RawMessage) (interface{}, error) {
+	record, err := s.findTxn(params)
+	if err != nil {
+		return nil, err
+	}
+	return record.txn, nil
+}
+
+func (s *Server) transactionReceipt(params []json.RawMessage) (interface{}, error) {
+	record, err := s.findTxn(params)
+	if err != nil {
+		return nil, err
+	}
+	return record.receipt, nil
+}
+
+func (s *Server) transactionStatus(params []json.RawMessage) (interface{}, error) {
+	record, err := s.findTxn(params)
+	if err != nil {
+		return nil, err
+	}
+	return rpc.TxnStatusResp{
+		ExecutionStatus: record.receipt.ExecutionStatus,
+		FinalityStatus:  rpc.TxnStatus(record.receipt.FinalityStatus),
+	}, nil
+}
+
+// deployed returns the contract of an address parameter.
+func (s *Server) deployed(param json.RawMessage) (*contract, error) {
+	var address felt.Felt
+	if err := json.Unmarshal(param, &address); err != nil {
+		return nil, rpc.Err(rpc.InvalidParams, err.Error())
+	}
+	c, ok := s.contracts[address]
+	if !ok {
+		return nil, rpc.ErrContractNotFound
+	}
+	return c, nil
+}
+
+// withData returns a copy of a node error with the given data.
+func withData(err *rpc.RPCError, data interface{}) *rpc.RPCError {
+	return &rpc.RPCError{Code: err.Code, Message: err.Message, Data: data}
+}
+
+// remarshal converts a value to another type through its JSON encoding.
+func remarshal(from, to interface{}) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, to)
+}
@@ -0,0 +1,91 @@
+package rpctest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// Interaction is a JSON-RPC call and its response, as stored in a golden file.
+type Interaction struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *rpc.RPCError   `json:"error,omitempty"`
+}
+
+// err returns the error of the call, if any. The errors without code are transport errors.
+func (i Interaction) err() error {
+	switch {
+	case i.Error == nil:
+		return nil
+	case i.Error.Code == 0:
+		return errors.New(i.Error.Message)
+	default:
+		return &rpc.RPCError{Code: i.Error.Code, Message: i.Error.Message, Data: i.Error.Data}
+	}
+}
+
+// encodeParams encodes the arguments of a call, the same arguments always giving the same bytes.
+func encodeParams(args []interface{}) (json.RawMessage, error) {
+	if args == nil {
+		args = []interface{}{}
+	}
+	data, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+	return canonicalParams(data)
+}
+
+// canonicalParams re-encodes JSON parameters with sorted keys and without spaces,
+// so that they can be compared whatever the field order of the encoded types.
+func canonicalParams(params json.RawMessage) (json.RawMessage, error) {
+	decoder := json.NewDecoder(bytes.NewReader(params))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	return json.Marshal(decoded)
+}
+
+// encodeError converts the error of a call to the RPCError stored in a golden file.
+// The errors of the node keep their code, message and data.
+func encodeError(err error) *rpc.RPCError {
+	var rpcErr rpc.RPCError
+	if data, marshalErr := json.Marshal(err); marshalErr == nil {
+		if json.Unmarshal(data, &rpcErr) == nil && rpcErr.Code != 0 {
+			return &rpcErr
+		}
+	}
+	return &rpc.RPCError{Message: err.Error()}
+}
+
+// readGoldenFile reads the interactions of a golden file.
+func readGoldenFile(path string) ([]Interaction, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var interactions []Interaction
+	if err := json.Unmarshal(data, &interactions); err != nil {
+		return nil, err
+	}
+	return interactions, nil
+}
+
+// writeGoldenFile writes the interactions to a golden file, indented to keep the diffs readable.
+func writeGoldenFile(path string, interactions []Interaction) error {
+	if interactions == nil {
+		interactions = []Interaction{}
+	}
+	data, err := json.MarshalIndent(interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,91 @@
+package rpctest
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// Recorder is a rpc.CallCloser forwarding the calls to another transport and
+// recording them, to be written to a golden file by Save.
+//
+//	client, _ := rpc.NewClient(url)
+//	recorder := rpctest.NewRecorder(client, "testdata/deploy.json")
+//	defer recorder.Save()
+//	provider := rpc.NewProviderWithTransport(recorder)
+type Recorder struct {
+	transport rpc.CallCloser
+	path      string
+
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+var _ rpc.CallCloser = &Recorder{}
+
+// NewRecorder creates a Recorder on top of a transport.
+//
+// Parameters:
+// - transport: the transport the calls are forwarded to, such as the client returned by rpc.NewClient
+// - path: the golden file written by Save
+// Returns:
+// - *Recorder: the recorder
+func NewRecorder(transport rpc.CallCloser, path string) *Recorder {
+	return &Recorder{transport: transport, path: path}
+}
+
+// CallContext implements rpc.CallCloser, recording the call and its response.
+func (r *Recorder) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	params, err := encodeParams(args)
+	if err != nil {
+		return err
+	}
+
+	var raw json.RawMessage
+	callErr := r.transport.CallContext(ctx, &raw, method, args...)
+	interaction := Interaction{Method: method, Params: params}
+	if callErr != nil {
+		// a cancelled call did not reach the node, there is nothing to replay
+		if ctx.Err() != nil {
+			return callErr
+		}
+		interaction.Error = encodeError(callErr)
+	} else {
+		interaction.Result = raw
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, interaction)
+	r.mu.Unlock()
+
+	if callErr != nil {
+		return callErr
+	}
+	return json.Unmarshal(raw, result)
+}
+
+// Interactions returns the calls recorded so far.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction{}, r.interactions...)
+}
+
+// Save writes the recorded calls to the golden file.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - error: an error if the file cannot be written
+func (r *Recorder) Save() error {
+	return writeGoldenFile(r.path, r.Interactions())
+}
+
+// Close implements rpc.CallCloser, closing the underlying transport. It does not save the recorded calls.
+func (r *Recorder) Close() {
+	r.transport.Close()
+}
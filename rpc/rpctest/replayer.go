@@ -0,0 +1,103 @@
+package rpctest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+var ErrNoRecordedCall = errors.New("no recorded call matches the request")
+
+// Replayer is a rpc.CallCloser answering the calls with the responses of a golden file,
+// without any network access. A call matches a recorded one when they have the same
+// method and parameters. The identical calls get the recorded responses in order,
+// the last one being repeated once they are exhausted, so that polling loops end.
+type Replayer struct {
+	mu        sync.Mutex
+	responses map[string][]Interaction
+	served    map[string]int
+}
+
+var _ rpc.CallCloser = &Replayer{}
+
+// NewReplayer creates a Replayer serving the calls recorded in a golden file.
+//
+// Parameters:
+// - path: the golden file, as written by Recorder.Save
+// Returns:
+// - *Replayer: the replayer
+// - error: an error if the file cannot be read
+func NewReplayer(path string) (*Replayer, error) {
+	interactions, err := readGoldenFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayerFromInteractions(interactions)
+}
+
+// NewReplayerFromInteractions creates a Replayer serving the given calls.
+//
+// Parameters:
+// - interactions: the recorded calls, in the order they were made
+// Returns:
+// - *Replayer: the replayer
+// - error: an error if the parameters of a call are not valid JSON
+func NewReplayerFromInteractions(interactions []Interaction) (*Replayer, error) {
+	r := &Replayer{
+		responses: make(map[string][]Interaction),
+		served:    make(map[string]int),
+	}
+	for _, interaction := range interactions {
+		params, err := compactParams(interaction.Params)
+		if err != nil {
+			return nil, err
+		}
+		key := interaction.Method + string(params)
+		r.responses[key] = append(r.responses[key], interaction)
+	}
+	return r, nil
+}
+
+// CallContext implements rpc.CallCloser, answering with the next recorded response of the call.
+func (r *Replayer) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	params, err := encodeParams(args)
+	if err != nil {
+		return err
+	}
+
+	key := method + string(params)
+	r.mu.Lock()
+	responses := r.responses[key]
+	served := r.served[key]
+	if served < len(responses) {
+		r.served[key]++
+	}
+	r.mu.Unlock()
+	if len(responses) == 0 {
+		return fmt.Errorf("%w: %s %s", ErrNoRecordedCall, method, params)
+	}
+
+	interaction := responses[min(served, len(responses)-1)]
+	if err := interaction.err(); err != nil {
+		return err
+	}
+	return json.Unmarshal(interaction.Result, result)
+}
+
+// Close implements rpc.CallCloser.
+func (r *Replayer) Close() {}
+
+// compactParams returns the canonical form of recorded parameters, which may have been edited by hand.
+func compactParams(params json.RawMessage) (json.RawMessage, error) {
+	if len(params) == 0 {
+		return encodeParams(nil)
+	}
+	return canonicalParams(params)
+}
@@ -0,0 +1,262 @@
+package rpctest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// HandlerFunc answers a JSON-RPC method, given its positional parameters.
+// Returning a *rpc.RPCError sends it to the client as is, any other error is sent as an internal error.
+type HandlerFunc func(params []json.RawMessage) (interface{}, error)
+
+// FeeRule gives the fee estimate of a transaction. It is used to answer starknet_estimateFee,
+// and the transactions whose max fee is below the estimate are rejected with rpc.ErrInsufficientMaxFee.
+type FeeRule func(txn Txn) rpc.FeeEstimate
+
+// EventRule gives the events emitted by an accepted transaction, such as the events of the
+// contracts it calls, since the server does not execute the transactions.
+type EventRule func(txn Txn) []rpc.Event
+
+// Server is an in-process Starknet JSON-RPC server backed by an in-memory chain, to test the code
+// using a real rpc.Provider without running a node.
+//
+// The transactions sent to the server are checked against the nonce of their sender and the fee
+// given by the fee rule, then each of them is included in a new block and succeeds. They are not
+// executed: their effects, other than the nonce increment and the deployment of accounts and
+// classes, are set with SetStorage, SetCallResult and SetEventRule. The state queries answer with
+// the current state whatever the block.
+//
+//	server := rpctest.NewServer()
+//	defer server.Close()
+//	server.AddContract(accountAddress, accountClassHash)
+//	server.FailNext("starknet_addInvokeTransaction", rpc.ErrInsufficientAccountBalance)
+//	provider, _ := server.Provider()
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	chainID   string
+	version   string
+	blocks    []*block
+	contracts map[felt.Felt]*contract
+	classes   map[felt.Felt]json.RawMessage
+	txns      map[felt.Felt]*txnRecord
+	calls     map[callKey][]*felt.Felt
+	feeRule   FeeRule
+	eventRule EventRule
+	failures  map[string][]*rpc.RPCError
+	handlers  map[string]HandlerFunc
+}
+
+// NewServer starts a Server on a local port, with a chain made of its genesis block.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *Server: the server, to be closed at the end of the test
+func NewServer() *Server {
+	s := &Server{
+		chainID:   "SN_SEPOLIA",
+		version:   "0.7.1",
+		contracts: make(map[felt.Felt]*contract),
+		classes:   make(map[felt.Felt]json.RawMessage),
+		txns:      make(map[felt.Felt]*txnRecord),
+		calls:     make(map[callKey][]*felt.Felt),
+		feeRule:   DefaultFeeRule,
+		failures:  make(map[string][]*rpc.RPCError),
+		handlers:  make(map[string]HandlerFunc),
+	}
+	s.mineBlock(nil)
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Provider returns a rpc.Provider connected to the server.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *rpc.Provider: the provider
+// - error: an error if the provider cannot be created
+func (s *Server) Provider() (*rpc.Provider, error) {
+	return rpc.NewProvider(s.URL)
+}
+
+// SetChainID sets the chain id returned by starknet_chainId, "SN_SEPOLIA" by default.
+func (s *Server) SetChainID(chainID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chainID = chainID
+}
+
+// SetSpecVersion sets the version returned by starknet_specVersion, "0.7.1" by default.
+func (s *Server) SetSpecVersion(version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version = version
+}
+
+// SetFeeRule sets the rule giving the fee estimates, DefaultFeeRule by default.
+func (s *Server) SetFeeRule(rule FeeRule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.feeRule = rule
+}
+
+// FailNext makes the next call of a method fail with the given error. The errors of the
+// successive calls to FailNext for the same method are returned by the successive calls.
+//
+// Parameters:
+// - method: the JSON-RPC method, such as "starknet_addInvokeTransaction"
+// - err: the error returned to the client, such as rpc.ErrInvalidTransactionNonce
+func (s *Server) FailNext(method string, err *rpc.RPCError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[method] = append(s.failures[method], err)
+}
+
+// Handle answers a method with the given handler instead of the in-memory chain.
+// The handler is called without holding the lock of the server, so it may call its methods.
+//
+// Parameters:
+// - method: the JSON-RPC method, such as "starknet_getEvents"
+// - handler: the function answering the method
+func (s *Server) Handle(method string, handler HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method] = handler
+}
+
+// request is a JSON-RPC request.
+type request struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+// response is a JSON-RPC response.
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpc.RPCError   `json:"error,omitempty"`
+}
+
+// serveHTTP answers a JSON-RPC request or batch of requests.
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	var body json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: rpc.Err(rpc.InvalidJSON, err.Error())})
+		return
+	}
+
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		var batch []request
+		if err := json.Unmarshal(body, &batch); err != nil {
+			writeJSON(w, response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: rpc.Err(rpc.InvalidRequest, err.Error())})
+			return
+		}
+		responses := make([]response, len(batch))
+		for i, req := range batch {
+			responses[i] = s.answer(req)
+		}
+		writeJSON(w, responses)
+		return
+	}
+
+	var req request
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeJSON(w, response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: rpc.Err(rpc.InvalidRequest, err.Error())})
+		return
+	}
+	writeJSON(w, s.answer(req))
+}
+
+// answer returns the response to a request.
+func (s *Server) answer(req request) response {
+	resp := response{JSONRPC: "2.0", ID: req.ID}
+	result, err := s.dispatch(req.Method, req.Params)
+	if err != nil {
+		var rpcErr *rpc.RPCError
+		if !errors.As(err, &rpcErr) {
+			rpcErr = rpc.Err(rpc.InternalError, err.Error())
+		}
+		resp.Error = rpcErr
+		return resp
+	}
+	resp.Result, err = json.Marshal(result)
+	if err != nil {
+		resp.Error = rpc.Err(rpc.InternalError, err.Error())
+	}
+	return resp
+}
+
+// dispatch calls the handler of a method, after the failures injected by FailNext.
+func (s *Server) dispatch(method string, rawParams json.RawMessage) (interface{}, error) {
+	var params []json.RawMessage
+	if len(rawParams) > 0 && string(rawParams) != "null" {
+		if err := json.Unmarshal(rawParams, &params); err != nil {
+			return nil, rpc.Err(rpc.InvalidParams, "only positional parameters are supported")
+		}
+	}
+
+	s.mu.Lock()
+	if failures := s.failures[method]; len(failures) > 0 {
+		s.failures[method] = failures[1:]
+		s.mu.Unlock()
+		return nil, failures[0]
+	}
+	handler, ok := s.handlers[method]
+	s.mu.Unlock()
+	if ok {
+		return handler(params)
+	}
+
+	chainHandler, ok := chainMethods[method]
+	if !ok {
+		return nil, rpc.Err(rpc.MethodNotFound, method)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return chainHandler(s, params)
+}
+
+// writeJSON writes a JSON response.
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// decodeParams decodes the positional parameters of a call into the given pointers.
+// The missing trailing parameters leave their pointer unchanged.
+func decodeParams(params []json.RawMessage, targets ...interface{}) error {
+	if len(params) > len(targets) {
+		return rpc.Err(rpc.InvalidParams, fmt.Sprintf("expected at most %d parameters, got %d", len(targets), len(params)))
+	}
+	for i, param := range params {
+		if err := json.Unmarshal(param, targets[i]); err != nil {
+			return rpc.Err(rpc.InvalidParams, err.Error())
+		}
+	}
+	return nil
+}
+
+// encodeShortString encodes a short string, such as a chain id, as a hexadecimal felt.
+func encodeShortString(s string) string {
+	return "0x" + hex.EncodeToString([]byte(s))
+}
@@ -0,0 +1,155 @@
+package rpctest_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/rpc/rpctest"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// newAccount returns an account deployed on the server.
+func newAccount(t *testing.T, server *rpctest.Server) *account.Account {
+	provider, err := server.Provider()
+	require.NoError(t, err)
+	ks, pub, _ := account.GetRandomKeys()
+	address := utils.TestHexToFelt(t, "0x1234")
+	server.AddContract(address, utils.TestHexToFelt(t, "0xc1a55"))
+
+	acnt, err := account.NewAccount(provider, address, pub.String(), ks, 2)
+	require.NoError(t, err)
+	return acnt
+}
+
+// invoke signs and sends an invoke transaction of the account with the given nonce and max fee.
+func invoke(t *testing.T, acnt *account.Account, nonce, maxFee uint64) (*rpc.AddInvokeTransactionResponse, error) {
+	calldata, err := acnt.FmtCalldata([]rpc.FunctionCall{{
+		ContractAddress:    utils.TestHexToFelt(t, "0x4dead"),
+		EntryPointSelector: utils.GetSelectorFromNameFelt("transfer"),
+		Calldata:           []*felt.Felt{new(felt.Felt).SetUint64(1)},
+	}})
+	require.NoError(t, err)
+	txn := rpc.InvokeTxnV1{
+		Type:          rpc.TransactionType_Invoke,
+		Version:       rpc.TransactionV1,
+		SenderAddress: acnt.AccountAddress,
+		Nonce:         new(felt.Felt).SetUint64(nonce),
+		MaxFee:        new(felt.Felt).SetUint64(maxFee),
+		Calldata:      calldata,
+	}
+	require.NoError(t, acnt.SignInvokeTransaction(context.Background(), &txn))
+	return acnt.AddInvokeTransaction(context.Background(), rpc.BroadcastInvokev1Txn{InvokeTxnV1: txn})
+}
+
+func TestServerInvoke(t *testing.T) {
+	server := rpctest.NewServer()
+	defer server.Close()
+	acnt := newAccount(t, server)
+	ctx := context.Background()
+
+	chainID, err := acnt.ChainID(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "SN_SEPOLIA", chainID)
+
+	nonce, err := acnt.Nonce(ctx, rpc.BlockID{Tag: "latest"}, acnt.AccountAddress)
+	require.NoError(t, err)
+	require.Equal(t, new(felt.Felt), nonce)
+
+	resp, err := invoke(t, acnt, 0, 2000)
+	require.NoError(t, err)
+	require.Equal(t, new(felt.Felt).SetUint64(1), server.Nonce(acnt.AccountAddress))
+
+	receipt, err := acnt.WaitForTransactionReceipt(ctx, resp.TransactionHash, time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, rpc.TxnExecutionStatusSUCCEEDED, receipt.GetExecutionStatus())
+	require.Equal(t, uint(1), receipt.BlockNumber)
+
+	block, err := rpc.FetchBlockWithTxHashes(ctx, acnt, rpc.BlockID{Tag: "latest"})
+	require.NoError(t, err)
+	require.Equal(t, []*felt.Felt{resp.TransactionHash}, block.TransactionHashes())
+
+	txn, err := acnt.TransactionByHash(ctx, resp.TransactionHash)
+	require.NoError(t, err)
+	require.Equal(t, acnt.AccountAddress, txn.(rpc.InvokeTxnV1).SenderAddress)
+}
+
+func TestServerErrors(t *testing.T) {
+	server := rpctest.NewServer()
+	defer server.Close()
+	acnt := newAccount(t, server)
+
+	type testSetType struct {
+		Nonce         uint64
+		MaxFee        uint64
+		Inject        *rpc.RPCError
+		ExpectedError *rpc.RPCError
+	}
+	testSet := []testSetType{
+		{Nonce: 1, MaxFee: 1000, ExpectedError: rpc.ErrInvalidTransactionNonce},
+		{Nonce: 0, MaxFee: 999, ExpectedError: rpc.ErrInsufficientMaxFee},
+		{Nonce: 0, MaxFee: 1000, Inject: rpc.ErrInsufficientAccountBalance, ExpectedError: rpc.ErrInsufficientAccountBalance},
+	}
+	for _, test := range testSet {
+		if test.Inject != nil {
+			server.FailNext("starknet_addInvokeTransaction", test.Inject)
+		}
+		_, err := invoke(t, acnt, test.Nonce, test.MaxFee)
+		require.Error(t, err)
+		require.Equal(t, test.ExpectedError.Code, err.(*rpc.RPCError).Code)
+	}
+	require.Equal(t, new(felt.Felt), server.Nonce(acnt.AccountAddress))
+
+	// the injected failures are consumed
+	_, err := invoke(t, acnt, 0, 1000)
+	require.NoError(t, err)
+}
+
+func TestServerState(t *testing.T) {
+	server := rpctest.NewServer()
+	defer server.Close()
+	provider, err := server.Provider()
+	require.NoError(t, err)
+	ctx := context.Background()
+	latest := rpc.BlockID{Tag: "latest"}
+
+	address := utils.TestHexToFelt(t, "0x4dead")
+	selector := utils.GetSelectorFromNameFelt("balanceOf")
+	server.SetStorage(address, selector, new(felt.Felt).SetUint64(7))
+	server.SetCallResult(address, selector, new(felt.Felt).SetUint64(7), new(felt.Felt))
+
+	value, err := provider.StorageAt(ctx, address, "balanceOf", latest)
+	require.NoError(t, err)
+	require.Equal(t, "0x7", value)
+
+	result, err := provider.Call(ctx, rpc.FunctionCall{ContractAddress: address, EntryPointSelector: selector}, latest)
+	require.NoError(t, err)
+	require.Equal(t, []*felt.Felt{new(felt.Felt).SetUint64(7), new(felt.Felt)}, result)
+
+	_, err = provider.ClassHashAt(ctx, latest, utils.TestHexToFelt(t, "0x1"))
+	require.Equal(t, rpc.ErrContractNotFound.Code, err.(*rpc.RPCError).Code)
+
+	require.Equal(t, uint64(1), server.MineBlock())
+	number := uint64(2)
+	_, err = provider.Nonce(ctx, rpc.BlockID{Number: &number}, address)
+	require.Equal(t, rpc.ErrBlockNotFound.Code, err.(*rpc.RPCError).Code)
+
+	estimates, err := provider.EstimateFee(ctx, []rpc.BroadcastTxn{rpc.BroadcastInvokev1Txn{InvokeTxnV1: rpc.InvokeTxnV1{
+		Type: rpc.TransactionType_Invoke, Version: rpc.TransactionV1WithQueryBit, SenderAddress: address,
+		Nonce: new(felt.Felt), MaxFee: new(felt.Felt), Calldata: []*felt.Felt{}, Signature: []*felt.Felt{},
+	}}}, []rpc.SimulationFlag{}, latest)
+	require.NoError(t, err)
+	require.Equal(t, []rpc.FeeEstimate{rpctest.DefaultFeeRule(rpctest.Txn{})}, estimates)
+
+	server.Handle("starknet_blockNumber", func(params []json.RawMessage) (interface{}, error) {
+		return 42, nil
+	})
+	blockNumber, err := provider.BlockNumber(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), blockNumber)
+}
@@ -0,0 +1,323 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RPCSpec is a minor version of the Starknet JSON-RPC specification, such as "0.7".
+type RPCSpec string
+
+const (
+	RPCSpecV0_6 RPCSpec = "0.6"
+	RPCSpecV0_7 RPCSpec = "0.7"
+	RPCSpecV0_8 RPCSpec = "0.8"
+
+	// DefaultRPCSpec is the spec the types of this package follow. It is also
+	// used when the spec version of the node cannot be detected.
+	DefaultRPCSpec = RPCSpecV0_7
+)
+
+// rpcSpecs lists the supported specs, from the oldest to the newest.
+var rpcSpecs = []RPCSpec{RPCSpecV0_6, RPCSpecV0_7, RPCSpecV0_8}
+
+// methodsAddedIn gives the first spec providing a method, for the methods that are not part of all the supported specs.
+var methodsAddedIn = map[string]RPCSpec{
+	"starknet_getBlockWithReceipts":         RPCSpecV0_7,
+	"starknet_getMessagesStatus":            RPCSpecV0_8,
+	"starknet_getStorageProof":              RPCSpecV0_8,
+	"starknet_getCompiledCasm":              RPCSpecV0_8,
+	"starknet_subscribeNewHeads":            RPCSpecV0_8,
+	"starknet_subscribeEvents":              RPCSpecV0_8,
+	"starknet_subscribeTransactionStatus":   RPCSpecV0_8,
+	"starknet_subscribePendingTransactions": RPCSpecV0_8,
+	"starknet_unsubscribe":                  RPCSpecV0_8,
+}
+
+// index returns the position of the spec in rpcSpecs.
+func (spec RPCSpec) index() int {
+	for i, s := range rpcSpecs {
+		if s == spec {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseRPCSpec returns the supported spec closest to a version reported by a node.
+// The versions older than the oldest supported spec map to it, and the newer ones to the newest.
+//
+// Parameters:
+// - version: the version reported by the node, such as "0.7.1"
+// Returns:
+// - RPCSpec: the spec used to talk to the node
+// - bool: false if the version could not be parsed
+func parseRPCSpec(version string) (RPCSpec, bool) {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if len(parts) < 2 {
+		return DefaultRPCSpec, false
+	}
+	spec := RPCSpec(parts[0] + "." + parts[1])
+	if spec.index() >= 0 {
+		return spec, true
+	}
+	if spec < rpcSpecs[0] {
+		return rpcSpecs[0], true
+	}
+	return rpcSpecs[len(rpcSpecs)-1], true
+}
+
+// Capabilities describes what a provider can do with the node it talks to.
+type Capabilities struct {
+	// SpecVersion is the version reported by the node, empty if it could not be detected
+	SpecVersion string
+	// Spec is the spec used to encode the requests and decode the responses
+	Spec RPCSpec
+}
+
+// RequiresL1DataGas tells whether the V3 transactions sent to the node must declare and sign the bounds
+// of the L1 data gas, in the L1DataGas of their ResourceBoundsMapping.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - bool: true for the nodes following spec v0.8 and later
+func (c Capabilities) RequiresL1DataGas() bool {
+	return c.Spec.index() >= RPCSpecV0_8.index()
+}
+
+// Supports tells whether a JSON-RPC method is part of the spec of the node.
+//
+// Parameters:
+// - method: the name of the method, such as "starknet_getBlockWithReceipts"
+// Returns:
+// - bool: true if the method is available
+func (c Capabilities) Supports(method string) bool {
+	addedIn, ok := methodsAddedIn[method]
+	return !ok || c.Spec.index() >= addedIn.index()
+}
+
+// Capabilities returns the capabilities of the provider, detecting the spec
+// version of the node on first use. When the node does not implement
+// starknet_specVersion, the provider assumes it follows DefaultRPCSpec. The
+// detection is only kept once the node answered: after another error, such as
+// a transport error or a cancelled ctx, DefaultRPCSpec is used for this call
+// and the detection is retried by the next one.
+//
+// Parameters:
+// - ctx: The context.Context object for the request
+// Returns:
+// - Capabilities: the capabilities of the provider
+func (provider *Provider) Capabilities(ctx context.Context) Capabilities {
+	provider.specMu.Lock()
+	cached := provider.capabilities
+	provider.specMu.Unlock()
+	if cached != nil {
+		return *cached
+	}
+
+	caps := Capabilities{Spec: DefaultRPCSpec}
+	var version string
+	err := do(ctx, provider.c, "starknet_specVersion", &version)
+	if err == nil {
+		if spec, ok := parseRPCSpec(version); ok {
+			caps = Capabilities{SpecVersion: version, Spec: spec}
+		}
+	}
+	if err != nil && !isMethodNotFound(err) {
+		return caps
+	}
+
+	provider.specMu.Lock()
+	defer provider.specMu.Unlock()
+	if provider.capabilities == nil {
+		provider.capabilities = &caps
+	}
+	return *provider.capabilities
+}
+
+// capabilitiesProvider is a provider detecting the spec of its node.
+type capabilitiesProvider interface {
+	Capabilities(ctx context.Context) Capabilities
+}
+
+// CapabilitiesOf returns the capabilities of a provider detecting the spec of its node, such as a Provider,
+// a CachingProvider or a MultiProvider, and the capabilities of DefaultRPCSpec for the other providers.
+//
+// Parameters:
+// - ctx: The context.Context object for the request
+// - provider: the provider
+// Returns:
+// - Capabilities: the capabilities of the provider
+func CapabilitiesOf(ctx context.Context, provider RpcProvider) Capabilities {
+	if p, ok := provider.(capabilitiesProvider); ok {
+		return p.Capabilities(ctx)
+	}
+	return Capabilities{Spec: DefaultRPCSpec}
+}
+
+// isMethodNotFound tells whether an error is the answer of a node that does not implement a method.
+func isMethodNotFound(err error) bool {
+	var codeErr interface{ ErrorCode() int }
+	if errors.As(err, &codeErr) {
+		return codeErr.ErrorCode() == MethodNotFound
+	}
+	var rpcErr *RPCError
+	return errors.As(err, &rpcErr) && rpcErr.Code == MethodNotFound
+}
+
+// do performs a call with the spec of the node. The requests are checked against
+// the spec, and the responses are converted to the shapes of DefaultRPCSpec,
+// which the types of this package follow.
+//
+// Parameters:
+// - ctx: represents the current execution context
+// - method: the string representing the RPC method to be called
+// - data: the interface{} to store the result of the RPC call
+// - args: variadic and can be used to pass additional arguments to the RPC method
+// Returns:
+// - error: ErrUnsupportedSpecVersion if the method is not part of the spec of the node or the request does not
+// follow it, or an error of the call
+func (provider *Provider) do(ctx context.Context, method string, data interface{}, args ...interface{}) error {
+	caps := provider.Capabilities(ctx)
+	if !caps.Supports(method) {
+		return ErrUnsupportedSpecVersion
+	}
+	if err := checkRequest(caps, method, args); err != nil {
+		return err
+	}
+	if caps.Spec == DefaultRPCSpec {
+		return do(ctx, provider.c, method, data, args...)
+	}
+
+	var raw json.RawMessage
+	if err := do(ctx, provider.c, method, &raw, args...); err != nil {
+		return err
+	}
+	raw, err := decodeResponse(caps.Spec, method, raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, data)
+}
+
+// checkRequest checks that the V3 transactions of a call declare the bounds of the L1 data gas if and only
+// if the node requires them. The transactions are signed, so they are rejected rather than converted.
+func checkRequest(caps Capabilities, method string, args []interface{}) error {
+	var txnsArg int
+	switch method {
+	case "starknet_addInvokeTransaction", "starknet_addDeclareTransaction", "starknet_addDeployAccountTransaction", "starknet_estimateFee":
+		txnsArg = 0
+	case "starknet_simulateTransactions":
+		txnsArg = 1
+	default:
+		return nil
+	}
+	if len(args) <= txnsArg {
+		return nil
+	}
+
+	var txns interface{}
+	if err := remarshal(args[txnsArg], &txns); err != nil {
+		return err
+	}
+	var err error
+	forEach(txns, func(txn map[string]interface{}) {
+		bounds, ok := txn["resource_bounds"].(map[string]interface{})
+		if !ok || err != nil {
+			return
+		}
+		switch _, declared := bounds["l1_data_gas"]; {
+		case caps.RequiresL1DataGas() && !declared:
+			err = unsupportedRequest(fmt.Sprintf("the V3 transactions sent to a node following spec %s must declare the bounds of the L1 data gas", caps.Spec))
+		case !caps.RequiresL1DataGas() && declared:
+			err = unsupportedRequest(fmt.Sprintf("the V3 transactions sent to a node following spec %s cannot declare the bounds of the L1 data gas", caps.Spec))
+		}
+	})
+	return err
+}
+
+// unsupportedRequest returns ErrUnsupportedSpecVersion with the reason why the spec of the node rejects a request.
+func unsupportedRequest(reason string) *RPCError {
+	return &RPCError{Code: ErrUnsupportedSpecVersion.Code, Message: ErrUnsupportedSpecVersion.Message, Data: reason}
+}
+
+// decodeResponse converts the result of a call from the shapes of the given spec to the ones of DefaultRPCSpec.
+func decodeResponse(spec RPCSpec, method string, raw json.RawMessage) (json.RawMessage, error) {
+	var adapt func(spec RPCSpec, result map[string]interface{})
+	switch method {
+	case "starknet_getBlockWithTxHashes", "starknet_getBlockWithTxs", "starknet_getBlockWithReceipts":
+		adapt = adaptBlock
+	case "starknet_estimateFee", "starknet_estimateMessageFee":
+		adapt = adaptFeeEstimate
+	case "starknet_simulateTransactions":
+		adapt = func(spec RPCSpec, result map[string]interface{}) {
+			forEach(result["fee_estimation"], func(fee map[string]interface{}) { adaptFeeEstimate(spec, fee) })
+		}
+	default:
+		return raw, nil
+	}
+
+	var result interface{}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, err
+	}
+	forEach(result, func(m map[string]interface{}) { adapt(spec, m) })
+	return json.Marshal(result)
+}
+
+// forEach calls fn on v if it is a JSON object, or on each object of v if it is an array.
+func forEach(v interface{}, fn func(map[string]interface{})) {
+	switch casted := v.(type) {
+	case map[string]interface{}:
+		fn(casted)
+	case []interface{}:
+		for _, item := range casted {
+			if m, ok := item.(map[string]interface{}); ok {
+				fn(m)
+			}
+		}
+	}
+}
+
+// adaptBlock converts a block. Blocks older than v0.7 have no l1_da_mode, their data being published in calldata.
+// Since v0.8, the execution resources of the receipts and the traces hold the total gas consumed instead of the
+// data availability gas, which is decoded in the TotalL1Gas, TotalL1DataGas and L2Gas of ExecutionResources.
+func adaptBlock(spec RPCSpec, block map[string]interface{}) {
+	if spec == RPCSpecV0_6 {
+		if _, ok := block["l1_da_mode"]; !ok {
+			block["l1_da_mode"] = "CALLDATA"
+		}
+	}
+}
+
+// adaptFeeEstimate converts a fee estimate. Since v0.8 the l1 gas fields are
+// prefixed with l1_ and the l2 gas is estimated separately.
+func adaptFeeEstimate(spec RPCSpec, fee map[string]interface{}) {
+	switch spec {
+	case RPCSpecV0_6:
+		for _, field := range []string{"data_gas_consumed", "data_gas_price"} {
+			if _, ok := fee[field]; !ok {
+				fee[field] = "0x0"
+			}
+		}
+	case RPCSpecV0_8:
+		renames := map[string]string{
+			"l1_gas_consumed":      "gas_consumed",
+			"l1_gas_price":         "gas_price",
+			"l1_data_gas_consumed": "data_gas_consumed",
+			"l1_data_gas_price":    "data_gas_price",
+		}
+		for from, to := range renames {
+			if v, ok := fee[from]; ok {
+				fee[to] = v
+				delete(fee, from)
+			}
+		}
+	}
+}
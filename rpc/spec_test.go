@@ -0,0 +1,182 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRPCSpec(t *testing.T) {
+	type testSetType struct {
+		Version      string
+		ExpectedSpec RPCSpec
+		ExpectedOK   bool
+	}
+	testSet := []testSetType{
+		{Version: "0.6.0", ExpectedSpec: RPCSpecV0_6, ExpectedOK: true},
+		{Version: "0.7.1", ExpectedSpec: RPCSpecV0_7, ExpectedOK: true},
+		{Version: "v0.8.0-rc1", ExpectedSpec: RPCSpecV0_8, ExpectedOK: true},
+		{Version: "0.5.1", ExpectedSpec: RPCSpecV0_6, ExpectedOK: true},
+		{Version: "0.9.0", ExpectedSpec: RPCSpecV0_8, ExpectedOK: true},
+		{Version: "unknown", ExpectedSpec: DefaultRPCSpec, ExpectedOK: false},
+	}
+
+	for _, test := range testSet {
+		spec, ok := parseRPCSpec(test.Version)
+		require.Equal(t, test.ExpectedSpec, spec, test.Version)
+		require.Equal(t, test.ExpectedOK, ok, test.Version)
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	// the mock does not implement starknet_specVersion
+	provider := &Provider{c: &rpcMock{}}
+	caps := provider.Capabilities(context.Background())
+	require.Equal(t, Capabilities{Spec: DefaultRPCSpec}, caps)
+	require.True(t, caps.Supports("starknet_getBlockWithReceipts"))
+	require.False(t, caps.Supports("starknet_subscribeNewHeads"))
+
+	closer := &scriptedCloser{results: map[string]string{"starknet_specVersion": `"0.6.0"`}}
+	provider = &Provider{c: closer}
+	caps = provider.Capabilities(context.Background())
+	require.Equal(t, Capabilities{SpecVersion: "0.6.0", Spec: RPCSpecV0_6}, caps)
+
+	_, err := provider.BlockWithReceipts(context.Background(), BlockID{Tag: "latest"})
+	require.Equal(t, ErrUnsupportedSpecVersion, err)
+	require.Equal(t, 0, closer.callCount())
+}
+
+// specCloser answers starknet_specVersion with its errors, one per call, then with its version.
+type specCloser struct {
+	errs    []error
+	version string
+	calls   int
+}
+
+func (c *specCloser) Close() {}
+
+func (c *specCloser) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if method != "starknet_specVersion" {
+		return errNotFound
+	}
+	c.calls++
+	if len(c.errs) > 0 {
+		err := c.errs[0]
+		c.errs = c.errs[1:]
+		return err
+	}
+	return json.Unmarshal([]byte(c.version), result)
+}
+
+func TestCapabilitiesDetection(t *testing.T) {
+	// the transient errors are not kept, the detection is retried by the next call
+	closer := &specCloser{errs: []error{context.Canceled, errors.New("connection refused")}, version: `"0.8.0"`}
+	provider := &Provider{c: closer}
+	require.Equal(t, Capabilities{Spec: DefaultRPCSpec}, provider.Capabilities(context.Background()))
+	require.Equal(t, Capabilities{Spec: DefaultRPCSpec}, provider.Capabilities(context.Background()))
+	require.Equal(t, Capabilities{SpecVersion: "0.8.0", Spec: RPCSpecV0_8}, provider.Capabilities(context.Background()))
+	require.Equal(t, Capabilities{SpecVersion: "0.8.0", Spec: RPCSpecV0_8}, provider.Capabilities(context.Background()))
+	require.Equal(t, 3, closer.calls)
+
+	// a node without starknet_specVersion follows DefaultRPCSpec
+	closer = &specCloser{errs: []error{Err(MethodNotFound, nil)}, version: `"0.8.0"`}
+	provider = &Provider{c: closer}
+	require.Equal(t, Capabilities{Spec: DefaultRPCSpec}, provider.Capabilities(context.Background()))
+	require.Equal(t, Capabilities{Spec: DefaultRPCSpec}, provider.Capabilities(context.Background()))
+	require.Equal(t, 1, closer.calls)
+}
+
+func TestSpecV0_6Adapters(t *testing.T) {
+	closer := &scriptedCloser{results: map[string]string{
+		"starknet_specVersion": `"0.6.0"`,
+		"starknet_estimateFee": `[{"gas_consumed":"0x10","gas_price":"0x2","overall_fee":"0x20","unit":"WEI"}]`,
+		"starknet_getBlockWithTxHashes": `{
+			"status":"ACCEPTED_ON_L2","block_hash":"0x1","parent_hash":"0x0","block_number":1,"new_root":"0x0",
+			"timestamp":1,"sequencer_address":"0x0","l1_gas_price":{"price_in_wei":"0x1"},"starknet_version":"0.13.0",
+			"transactions":[]
+		}`,
+	}}
+	provider := &Provider{c: closer}
+
+	fees, err := provider.EstimateFee(context.Background(), []BroadcastTxn{}, []SimulationFlag{}, BlockID{Tag: "latest"})
+	require.NoError(t, err)
+	require.Equal(t, new(felt.Felt).SetUint64(0x10), fees[0].GasConsumed)
+	require.Equal(t, &felt.Zero, fees[0].DataGasConsumed)
+	require.Equal(t, &felt.Zero, fees[0].DataGasPrice)
+
+	block, err := provider.BlockWithTxHashes(context.Background(), BlockID{Tag: "latest"})
+	require.NoError(t, err)
+	require.Equal(t, L1DAModeCalldata, block.(*BlockTxHashes).L1DAMode)
+}
+
+func TestSpecV0_8Adapters(t *testing.T) {
+	closer := &scriptedCloser{results: map[string]string{
+		"starknet_specVersion": `"0.8.0"`,
+		"starknet_estimateFee": `[{
+			"l1_gas_consumed":"0x10","l1_gas_price":"0x2","l1_data_gas_consumed":"0x3","l1_data_gas_price":"0x4",
+			"l2_gas_consumed":"0x5","l2_gas_price":"0x6","overall_fee":"0x20","unit":"FRI"
+		}]`,
+		"starknet_getTransactionReceipt": `{
+			"type":"INVOKE","transaction_hash":"0x1","actual_fee":{"amount":"0x1","unit":"FRI"},
+			"execution_status":"SUCCEEDED","finality_status":"ACCEPTED_ON_L2","block_hash":"0x2","block_number":1,
+			"messages_sent":[],"events":[],"execution_resources":{"l1_gas":10,"l1_data_gas":20,"l2_gas":30}
+		}`,
+		"starknet_addInvokeTransaction": `{"transaction_hash":"0x1"}`,
+	}}
+	provider := &Provider{c: closer}
+
+	fees, err := provider.EstimateFee(context.Background(), []BroadcastTxn{}, []SimulationFlag{}, BlockID{Tag: "latest"})
+	require.NoError(t, err)
+	require.Equal(t, new(felt.Felt).SetUint64(0x10), fees[0].GasConsumed)
+	require.Equal(t, new(felt.Felt).SetUint64(0x2), fees[0].GasPrice)
+	require.Equal(t, new(felt.Felt).SetUint64(0x3), fees[0].DataGasConsumed)
+	require.Equal(t, new(felt.Felt).SetUint64(0x4), fees[0].DataGasPrice)
+	require.Equal(t, new(felt.Felt).SetUint64(0x5), fees[0].L2GasConsumed)
+	require.Equal(t, new(felt.Felt).SetUint64(0x6), fees[0].L2GasPrice)
+
+	receipt, err := provider.TransactionReceipt(context.Background(), utils.TestHexToFelt(t, "0x1"))
+	require.NoError(t, err)
+	resources := receipt.TransactionReceipt.(InvokeTransactionReceipt).ExecutionResources
+	require.Equal(t, DataAvailability{}, resources.DataAvailability)
+	require.Equal(t, uint(10), resources.TotalL1Gas)
+	require.Equal(t, uint(20), resources.TotalL1DataGas)
+	require.Equal(t, uint(30), resources.L2Gas)
+
+	// the signed transactions are sent as is, with the bounds of the L1 data gas required since v0.8
+	_, err = provider.AddInvokeTransaction(context.Background(), BroadcastInvokev3Txn{
+		InvokeTxnV3: InvokeTxnV3{Type: TransactionType_Invoke, Version: TransactionV3},
+	})
+	var rpcErr *RPCError
+	require.ErrorAs(t, err, &rpcErr)
+	require.Equal(t, ErrUnsupportedSpecVersion.Code, rpcErr.Code)
+	require.Equal(t, ErrUnsupportedSpecVersion.Message, rpcErr.Message)
+	require.Contains(t, rpcErr.Data, "must declare the bounds of the L1 data gas")
+
+	dataGas := &ResourceBounds{MaxAmount: "0x3", MaxPricePerUnit: "0x4"}
+	_, err = provider.AddInvokeTransaction(context.Background(), BroadcastInvokev3Txn{
+		InvokeTxnV3: InvokeTxnV3{Type: TransactionType_Invoke, Version: TransactionV3, ResourceBounds: ResourceBoundsMapping{L1DataGas: dataGas}},
+	})
+	require.NoError(t, err)
+	sent, err := json.Marshal(closer.args[len(closer.args)-1][0])
+	require.NoError(t, err)
+	var txn struct {
+		ResourceBounds ResourceBoundsMapping `json:"resource_bounds"`
+	}
+	require.NoError(t, json.Unmarshal(sent, &txn))
+	require.Equal(t, dataGas, txn.ResourceBounds.L1DataGas)
+
+	// the older nodes reject them
+	closer = &scriptedCloser{results: map[string]string{"starknet_specVersion": `"0.7.1"`}}
+	provider = &Provider{c: closer}
+	_, err = provider.AddInvokeTransaction(context.Background(), BroadcastInvokev3Txn{
+		InvokeTxnV3: InvokeTxnV3{Type: TransactionType_Invoke, Version: TransactionV3, ResourceBounds: ResourceBoundsMapping{L1DataGas: dataGas}},
+	})
+	require.ErrorAs(t, err, &rpcErr)
+	require.Contains(t, rpcErr.Data, "cannot declare the bounds of the L1 data gas")
+	require.Equal(t, 0, closer.callCount())
+}
@@ -9,7 +9,7 @@ import (
 )
 
 type spy struct {
-	callCloser
+	CallCloser
 	s     []byte
 	mock  bool
 	debug bool
@@ -17,8 +17,8 @@ type spy struct {
 
 // NewSpy creates a new spy object.
 //
-// It takes a client callCloser as the first parameter and an optional debug parameter.
-// The client callCloser is the interface that the spy will be based on.
+// It takes a client CallCloser as the first parameter and an optional debug parameter.
+// The client CallCloser is the interface that the spy will be based on.
 // The debug parameter is a variadic parameter that specifies whether debug mode is enabled.
 //
 // Parameters:
@@ -26,21 +26,21 @@ type spy struct {
 // - debug: a boolean flag indicating whether to print debug information
 // Returns:
 // - spy: a new spy object
-func NewSpy(client callCloser, debug ...bool) *spy {
+func NewSpy(client CallCloser, debug ...bool) *spy {
 	d := false
 	if len(debug) > 0 {
 		d = debug[0]
 	}
 	if _, ok := client.(*rpcMock); ok {
 		return &spy{
-			callCloser: client,
+			CallCloser: client,
 			s:          []byte{},
 			mock:       true,
 			debug:      d,
 		}
 	}
 	return &spy{
-		callCloser: client,
+		CallCloser: client,
 		s:          []byte{},
 		debug:      d,
 	}
@@ -57,7 +57,7 @@ func NewSpy(client callCloser, debug ...bool) *spy {
 // - error: an error if any occurred during the function call
 func (s *spy) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
 	if s.mock {
-		return s.callCloser.CallContext(ctx, result, method, args...)
+		return s.CallCloser.CallContext(ctx, result, method, args...)
 	}
 	raw := json.RawMessage{}
 	if s.debug {
@@ -66,7 +66,7 @@ func (s *spy) CallContext(ctx context.Context, result interface{}, method string
 			fmt.Printf("   arg[%d].(%T): %+v\n", k, v, v)
 		}
 	}
-	err := s.callCloser.CallContext(ctx, &raw, method, args...)
+	err := s.CallCloser.CallContext(ctx, &raw, method, args...)
 	if err != nil {
 		return err
 	}
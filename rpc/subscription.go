@@ -0,0 +1,241 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+const (
+	subscriptionReorgMethod = "starknet_subscriptionReorg"
+	// maxReorgBuffer is the number of unread reorg notifications a subscription
+	// holds, the oldest ones being dropped to make room for the new ones.
+	maxReorgBuffer = 100
+)
+
+type subscriptionNotification struct {
+	method string
+	result json.RawMessage
+}
+
+// Subscription is a handle on an active subscription of a WsProvider.
+// Notifications are delivered on the channel returned along with it; the
+// channel is closed when the subscription ends.
+type Subscription struct {
+	client *wsClient
+	id     string
+
+	queue chan subscriptionNotification
+	reorg chan *ReorgEvent
+	err   chan error
+	quit  chan struct{}
+
+	failOnce sync.Once
+	unsubMu  sync.Mutex
+}
+
+// newSubscription creates a Subscription for the given client.
+func newSubscription(client *wsClient) *Subscription {
+	return &Subscription{
+		client: client,
+		queue:  make(chan subscriptionNotification, maxSubscriptionBuffer),
+		reorg:  make(chan *ReorgEvent, maxReorgBuffer),
+		err:    make(chan error, 1),
+		quit:   make(chan struct{}),
+	}
+}
+
+// ID returns the identifier of the subscription given by the node.
+func (sub *Subscription) ID() string {
+	return sub.id
+}
+
+// Err returns a channel that receives the error that ended the subscription,
+// such as a closed connection or a consumer too slow to keep up with the
+// notifications. The channel is closed without any value when Unsubscribe is called.
+func (sub *Subscription) Err() <-chan error {
+	return sub.err
+}
+
+// Reorg returns a channel that receives the reorg notifications of the subscription.
+// It holds the last 100 unread notifications, so that leaving it unread never ends the
+// subscription. The channel is closed when the subscription ends.
+func (sub *Subscription) Reorg() <-chan *ReorgEvent {
+	return sub.reorg
+}
+
+// Unsubscribe cancels the subscription on the node and closes its channels.
+// It can be called several times.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - error: an error if the node failed to cancel the subscription
+func (sub *Subscription) Unsubscribe() error {
+	sub.unsubMu.Lock()
+	defer sub.unsubMu.Unlock()
+
+	select {
+	case <-sub.quit:
+		return nil
+	default:
+	}
+
+	var ok bool
+	msg, err := sub.client.send(context.Background(), "starknet_unsubscribe", map[string]interface{}{"subscription_id": sub.id}, nil)
+	sub.fail(nil)
+	if err != nil {
+		return unwrapWsErr(err, ErrInvalidSubscriptionID)
+	}
+	if err := json.Unmarshal(msg.Result, &ok); err != nil {
+		return Err(InternalError, err.Error())
+	}
+	if !ok {
+		return Err(InternalError, "node refused to unsubscribe")
+	}
+	return nil
+}
+
+// fail ends the subscription. A nil error means the subscription was cancelled by the caller.
+func (sub *Subscription) fail(err error) {
+	sub.failOnce.Do(func() {
+		sub.client.removeSubscription(sub)
+		if err != nil {
+			sub.err <- err
+		}
+		close(sub.err)
+		close(sub.quit)
+	})
+}
+
+// forward decodes the notifications of a subscription and sends them to ch until the subscription ends.
+func forward[T any](sub *Subscription, ch chan<- T) {
+	defer close(ch)
+	defer close(sub.reorg)
+	for {
+		select {
+		case <-sub.quit:
+			return
+		case n := <-sub.queue:
+			if n.method == subscriptionReorgMethod {
+				var reorg ReorgEvent
+				if err := json.Unmarshal(n.result, &reorg); err != nil {
+					sub.fail(Err(InternalError, err.Error()))
+					return
+				}
+				pushReorg(sub.reorg, &reorg)
+				continue
+			}
+
+			var v T
+			if err := json.Unmarshal(n.result, &v); err != nil {
+				sub.fail(Err(InternalError, err.Error()))
+				return
+			}
+			select {
+			case ch <- v:
+			case <-sub.quit:
+				return
+			}
+		}
+	}
+}
+
+// pushReorg sends a reorg notification to a channel of which it is the only sender,
+// dropping the oldest unread notification when the channel is full.
+func pushReorg(ch chan *ReorgEvent, reorg *ReorgEvent) {
+	select {
+	case ch <- reorg:
+		return
+	default:
+	}
+	select {
+	case <-ch:
+	default:
+	}
+	ch <- reorg
+}
+
+// unwrapWsErr converts the errors returned by the node to the given rpc errors.
+// Transport errors, such as a closed connection, are returned unchanged.
+func unwrapWsErr(err error, rpcErrors ...*RPCError) error {
+	var nodeErr *RPCError
+	if !errors.As(err, &nodeErr) {
+		return err
+	}
+	return tryUnwrapToRPCErr(nodeErr, rpcErrors...)
+}
+
+// subscribe sends a subscription request and starts forwarding its notifications to a typed channel.
+func subscribe[T any](ctx context.Context, client *wsClient, method string, params interface{}, rpcErrors ...*RPCError) (<-chan T, *Subscription, error) {
+	sub := newSubscription(client)
+	if _, err := client.send(ctx, method, params, sub); err != nil {
+		sub.fail(err)
+		return nil, nil, unwrapWsErr(err, rpcErrors...)
+	}
+	ch := make(chan T)
+	go forward(sub, ch)
+	return ch, sub, nil
+}
+
+// SubscribeNewHeads subscribes to the headers of the new blocks of the chain.
+//
+// Parameters:
+// - ctx: The context.Context object for the request
+// - blockID: The block from which the headers are sent, the latest block if nil
+// Returns:
+// - <-chan *BlockHeader: the channel receiving the block headers
+// - *Subscription: the subscription handle
+// - error: an error, if any
+func (provider *WsProvider) SubscribeNewHeads(ctx context.Context, blockID *BlockID) (<-chan *BlockHeader, *Subscription, error) {
+	params := map[string]interface{}{}
+	if blockID != nil {
+		params["block_id"] = blockID
+	}
+	return subscribe[*BlockHeader](ctx, provider.ws, "starknet_subscribeNewHeads", params, ErrTooManyBlocksBack, ErrBlockNotFound, ErrCallOnPending)
+}
+
+// SubscribeEvents subscribes to the events matching the given filter.
+//
+// Parameters:
+// - ctx: The context.Context object for the request
+// - input: The filter of the events
+// Returns:
+// - <-chan *EmittedEvent: the channel receiving the events
+// - *Subscription: the subscription handle
+// - error: an error, if any
+func (provider *WsProvider) SubscribeEvents(ctx context.Context, input EventSubscriptionInput) (<-chan *EmittedEvent, *Subscription, error) {
+	return subscribe[*EmittedEvent](ctx, provider.ws, "starknet_subscribeEvents", input, ErrTooManyKeysInFilter, ErrTooManyBlocksBack, ErrBlockNotFound, ErrCallOnPending)
+}
+
+// SubscribeTransactionStatus subscribes to the status changes of a transaction.
+//
+// Parameters:
+// - ctx: The context.Context object for the request
+// - transactionHash: The hash of the transaction
+// Returns:
+// - <-chan *NewTxnStatus: the channel receiving the status changes
+// - *Subscription: the subscription handle
+// - error: an error, if any
+func (provider *WsProvider) SubscribeTransactionStatus(ctx context.Context, transactionHash *felt.Felt) (<-chan *NewTxnStatus, *Subscription, error) {
+	params := map[string]interface{}{"transaction_hash": transactionHash}
+	return subscribe[*NewTxnStatus](ctx, provider.ws, "starknet_subscribeTransactionStatus", params)
+}
+
+// SubscribePendingTransactions subscribes to the transactions entering the pending block.
+//
+// Parameters:
+// - ctx: The context.Context object for the request
+// - input: The filter of the transactions
+// Returns:
+// - <-chan *PendingTxn: the channel receiving the transactions
+// - *Subscription: the subscription handle
+// - error: an error, if any
+func (provider *WsProvider) SubscribePendingTransactions(ctx context.Context, input PendingTxnsInput) (<-chan *PendingTxn, *Subscription, error) {
+	return subscribe[*PendingTxn](ctx, provider.ws, "starknet_subscribePendingTransactions", input, ErrTooManyAddressesInFilter)
+}
@@ -18,7 +18,7 @@ import (
 //   - error: an error if the transaction trace cannot be retrieved
 func (provider *Provider) TraceTransaction(ctx context.Context, transactionHash *felt.Felt) (TxnTrace, error) {
 	var rawTxnTrace map[string]any
-	if err := do(ctx, provider.c, "starknet_traceTransaction", &rawTxnTrace, transactionHash); err != nil {
+	if err := provider.do(ctx, "starknet_traceTransaction", &rawTxnTrace, transactionHash); err != nil {
 		return nil, tryUnwrapToRPCErr(err, ErrHashNotFound, ErrNoTraceAvailable)
 	}
 
@@ -71,7 +71,7 @@ func (provider *Provider) TraceTransaction(ctx context.Context, transactionHash
 // - error: an error if there was a problem retrieving the traces.
 func (provider *Provider) TraceBlockTransactions(ctx context.Context, blockID BlockID) ([]Trace, error) {
 	var output []Trace
-	if err := do(ctx, provider.c, "starknet_traceBlockTransactions", &output, blockID); err != nil {
+	if err := provider.do(ctx, "starknet_traceBlockTransactions", &output, blockID); err != nil {
 		return nil, tryUnwrapToRPCErr(err, ErrBlockNotFound)
 	}
 	return output, nil
@@ -85,7 +85,7 @@ func (provider *Provider) TraceBlockTransactions(ctx context.Context, blockID Bl
 func (provider *Provider) SimulateTransactions(ctx context.Context, blockID BlockID, txns []Transaction, simulationFlags []SimulationFlag) ([]SimulatedTransaction, error) {
 
 	var output []SimulatedTransaction
-	if err := do(ctx, provider.c, "starknet_simulateTransactions", &output, blockID, txns, simulationFlags); err != nil {
+	if err := provider.do(ctx, "starknet_simulateTransactions", &output, blockID, txns, simulationFlags); err != nil {
 		return nil, tryUnwrapToRPCErr(err, ErrTxnExec, ErrBlockNotFound)
 	}
 
@@ -86,7 +86,7 @@ func adaptTransaction(t TXN) (Transaction, error) {
 func (provider *Provider) TransactionByHash(ctx context.Context, hash *felt.Felt) (Transaction, error) {
 	// todo: update to return a custom Transaction type, then use adapt function
 	var tx TXN
-	if err := do(ctx, provider.c, "starknet_getTransactionByHash", &tx, hash); err != nil {
+	if err := provider.do(ctx, "starknet_getTransactionByHash", &tx, hash); err != nil {
 		return nil, tryUnwrapToRPCErr(err, ErrHashNotFound)
 	}
 	return adaptTransaction(tx)
@@ -103,7 +103,7 @@ func (provider *Provider) TransactionByHash(ctx context.Context, hash *felt.Felt
 // - error: An error, if any
 func (provider *Provider) TransactionByBlockIdAndIndex(ctx context.Context, blockID BlockID, index uint64) (Transaction, error) {
 	var tx TXN
-	if err := do(ctx, provider.c, "starknet_getTransactionByBlockIdAndIndex", &tx, blockID, index); err != nil {
+	if err := provider.do(ctx, "starknet_getTransactionByBlockIdAndIndex", &tx, blockID, index); err != nil {
 
 		return nil, tryUnwrapToRPCErr(err, ErrInvalidTxnIndex, ErrBlockNotFound)
 
@@ -121,7 +121,7 @@ func (provider *Provider) TransactionByBlockIdAndIndex(ctx context.Context, bloc
 // - error: an error if any
 func (provider *Provider) TransactionReceipt(ctx context.Context, transactionHash *felt.Felt) (*TransactionReceiptWithBlockInfo, error) {
 	var receipt TransactionReceiptWithBlockInfo
-	err := do(ctx, provider.c, "starknet_getTransactionReceipt", &receipt, transactionHash)
+	err := provider.do(ctx, "starknet_getTransactionReceipt", &receipt, transactionHash)
 	if err != nil {
 		return nil, tryUnwrapToRPCErr(err, ErrHashNotFound)
 	}
@@ -137,7 +137,7 @@ func (provider *Provider) TransactionReceipt(ctx context.Context, transactionHas
 // - error, if one arose.
 func (provider *Provider) GetTransactionStatus(ctx context.Context, transactionHash *felt.Felt) (*TxnStatusResp, error) {
 	var receipt TxnStatusResp
-	err := do(ctx, provider.c, "starknet_getTransactionStatus", &receipt, transactionHash)
+	err := provider.do(ctx, "starknet_getTransactionStatus", &receipt, transactionHash)
 	if err != nil {
 		return nil, tryUnwrapToRPCErr(err, ErrHashNotFound)
 	}
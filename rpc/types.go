@@ -108,6 +108,40 @@ type SyncStatus struct {
 	HighestBlockNum   NumAsHex   `json:"highest_block_num,omitempty"`
 }
 
+// syncingStatus is the status of a syncing node, whose block numbers are integers, as in the spec,
+// or hex strings, as sent by the older nodes.
+type syncingStatus struct {
+	StartingBlockHash *felt.Felt `json:"starting_block_hash,omitempty"`
+	StartingBlockNum  blockNum   `json:"starting_block_num,omitempty"`
+	CurrentBlockHash  *felt.Felt `json:"current_block_hash,omitempty"`
+	CurrentBlockNum   blockNum   `json:"current_block_num,omitempty"`
+	HighestBlockHash  *felt.Felt `json:"highest_block_hash,omitempty"`
+	HighestBlockNum   blockNum   `json:"highest_block_num,omitempty"`
+}
+
+// blockNum is a block number of a sync status, kept in the hex format of NumAsHex.
+type blockNum NumAsHex
+
+// UnmarshalJSON unmarshals a block number given as an integer or as a hex string.
+//
+// Parameters:
+// - data: the JSON block number
+// Returns:
+// - error: an error if the block number is neither an integer nor a string
+func (n *blockNum) UnmarshalJSON(data []byte) error {
+	var number uint64
+	if err := json.Unmarshal(data, &number); err == nil {
+		*n = blockNum(fmt.Sprintf("0x%x", number))
+		return nil
+	}
+	var hex string
+	if err := json.Unmarshal(data, &hex); err != nil {
+		return fmt.Errorf("invalid block number %s", data)
+	}
+	*n = blockNum(hex)
+	return nil
+}
+
 // MarshalJSON marshals the SyncStatus struct into JSON format.
 //
 // It returns a byte slice and an error. The byte slice represents the JSON
@@ -205,6 +239,12 @@ type FeeEstimate struct {
 
 	// Units in which the fee is given
 	FeeUnit FeePaymentUnit `json:"unit"`
+
+	// The L2 gas consumption of the transaction, only estimated by the nodes following spec v0.8 and later
+	L2GasConsumed *felt.Felt `json:"l2_gas_consumed,omitempty"`
+
+	// The L2 gas price that was used in the cost estimation, only given by the nodes following spec v0.8 and later
+	L2GasPrice *felt.Felt `json:"l2_gas_price,omitempty"`
 }
 
 type TxnExecutionStatus string
@@ -137,8 +137,40 @@ type BlockBodyWithReceipts struct {
 }
 
 type TransactionWithReceipt struct {
-	Transaction UnknownTransaction        `json:"transaction"`
-	Receipt     UnknownTransactionReceipt `json:"receipt"`
+	Transaction BlockTransaction   `json:"transaction"`
+	Receipt     TransactionReceipt `json:"receipt"`
+}
+
+// UnmarshalJSON decodes the transaction and the receipt into their concrete types.
+// The hash of the transaction is taken from the receipt when the node does not include it in the transaction.
+//
+// Parameters:
+// - data: The JSON data to be unmarshalled
+// Returns:
+// - error: An error if the unmarshalling process fails
+func (txn *TransactionWithReceipt) UnmarshalJSON(data []byte) error {
+	var dec struct {
+		Transaction map[string]interface{} `json:"transaction"`
+		Receipt     json.RawMessage        `json:"receipt"`
+	}
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+
+	receipt, err := unmarshalTransactionReceipt(dec.Receipt)
+	if err != nil {
+		return err
+	}
+	if _, ok := dec.Transaction["transaction_hash"]; !ok && dec.Transaction != nil {
+		dec.Transaction["transaction_hash"] = receipt.Hash()
+	}
+	transaction, err := unmarshalBlockTxn(dec.Transaction)
+	if err != nil {
+		return err
+	}
+
+	*txn = TransactionWithReceipt{Transaction: transaction, Receipt: receipt}
+	return nil
 }
 
 // The dynamic block being constructed by the sequencer. Note that this object will be deprecated upon decentralization.
@@ -176,6 +208,8 @@ type BlockHeader struct {
 	L1GasPrice ResourcePrice `json:"l1_gas_price"`
 	// The price of l1 data gas in the block
 	L1DataGasPrice ResourcePrice `json:"l1_data_gas_price"`
+	// The price of l2 gas in the block, only given by the nodes following spec v0.8 and later
+	L2GasPrice *ResourcePrice `json:"l2_gas_price,omitempty"`
 	// Specifies whether the data of this block is published via blob data or calldata
 	L1DAMode L1DAMode `json:"l1_da_mode"`
 	// Semver of the current Starknet protocol
@@ -229,6 +263,8 @@ type PendingBlockHeader struct {
 	StarknetVersion string `json:"starknet_version"`
 	// The price of l1 data gas in the block
 	L1DataGasPrice ResourcePrice `json:"l1_data_gas_price"`
+	// The price of l2 gas in the block, only given by the nodes following spec v0.8 and later
+	L2GasPrice *ResourcePrice `json:"l2_gas_price,omitempty"`
 	// Specifies whether the data of this block is published via blob data or calldata
 	L1DAMode L1DAMode `json:"l1_da_mode"`
 }
@@ -0,0 +1,198 @@
+package rpc
+
+import (
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// BlockResult is a block returned by the node. It is either accepted or pending, and
+// holds the hashes of its transactions, its transactions, or its transactions with their receipts,
+// depending on the method used to fetch it.
+type BlockResult struct {
+	block interface{}
+}
+
+// NewBlockResult wraps a block returned by BlockWithTxHashes, BlockWithTxs or BlockWithReceipts.
+//
+// Parameters:
+// - block: one of *BlockTxHashes, *PendingBlockTxHashes, *Block, *PendingBlock, *BlockWithReceipts or *PendingBlockWithReceipts
+// Returns:
+// - *BlockResult: the wrapped block
+// - error: an error if the block is not of a supported type
+func NewBlockResult(block interface{}) (*BlockResult, error) {
+	switch block.(type) {
+	case *BlockTxHashes, *PendingBlockTxHashes, *Block, *PendingBlock, *BlockWithReceipts, *PendingBlockWithReceipts:
+		return &BlockResult{block: block}, nil
+	default:
+		return nil, fmt.Errorf("unsupported block type: %T", block)
+	}
+}
+
+// Block returns the wrapped block, to be type-switched when the accessors are not enough.
+func (b *BlockResult) Block() interface{} {
+	return b.block
+}
+
+// IsPending tells whether the block is the pending block, which has no hash nor number yet.
+func (b *BlockResult) IsPending() bool {
+	switch b.block.(type) {
+	case *PendingBlockTxHashes, *PendingBlock, *PendingBlockWithReceipts:
+		return true
+	default:
+		return false
+	}
+}
+
+// Header returns the header of the block. The BlockHash, BlockNumber and NewRoot of the pending block are not set.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - BlockHeader: the header of the block
+func (b *BlockResult) Header() BlockHeader {
+	switch block := b.block.(type) {
+	case *BlockTxHashes:
+		return block.BlockHeader
+	case *Block:
+		return block.BlockHeader
+	case *BlockWithReceipts:
+		return block.BlockHeader
+	case *PendingBlockTxHashes:
+		return block.PendingBlockHeader.toBlockHeader()
+	case *PendingBlock:
+		return block.PendingBlockHeader.toBlockHeader()
+	case *PendingBlockWithReceipts:
+		return block.PendingBlockHeader.toBlockHeader()
+	default:
+		return BlockHeader{}
+	}
+}
+
+// Status returns the status of the block, BlockStatus_Pending for the pending block.
+func (b *BlockResult) Status() BlockStatus {
+	switch block := b.block.(type) {
+	case *BlockTxHashes:
+		return block.Status
+	case *Block:
+		return block.Status
+	case *BlockWithReceipts:
+		return block.BlockStatus
+	default:
+		return BlockStatus_Pending
+	}
+}
+
+// TransactionHashes returns the hashes of the transactions of the block, whatever the method used to fetch it.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []*felt.Felt: the hashes of the transactions, in the order of the block
+func (b *BlockResult) TransactionHashes() []*felt.Felt {
+	switch block := b.block.(type) {
+	case *BlockTxHashes:
+		return block.Transactions
+	case *PendingBlockTxHashes:
+		return block.Transactions
+	}
+
+	txns := b.Transactions()
+	hashes := make([]*felt.Felt, len(txns))
+	for i, txn := range txns {
+		hashes[i] = txn.Hash()
+	}
+	return hashes
+}
+
+// Transactions returns the transactions of the block. It is nil when the block was fetched with BlockWithTxHashes.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - BlockTransactions: the transactions, in the order of the block
+func (b *BlockResult) Transactions() BlockTransactions {
+	switch block := b.block.(type) {
+	case *Block:
+		return block.Transactions
+	case *PendingBlock:
+		return block.BlockTransactions
+	case *BlockWithReceipts:
+		return block.BlockBodyWithReceipts.transactions()
+	case *PendingBlockWithReceipts:
+		return block.BlockBodyWithReceipts.transactions()
+	default:
+		return nil
+	}
+}
+
+// Receipts returns the receipts of the transactions of the block. It is nil unless the block was fetched with BlockWithReceipts.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []TransactionReceipt: the receipts, in the order of the transactions of the block
+func (b *BlockResult) Receipts() []TransactionReceipt {
+	switch block := b.block.(type) {
+	case *BlockWithReceipts:
+		return block.BlockBodyWithReceipts.receipts()
+	case *PendingBlockWithReceipts:
+		return block.BlockBodyWithReceipts.receipts()
+	default:
+		return nil
+	}
+}
+
+// transactions returns the transactions of the body.
+func (body BlockBodyWithReceipts) transactions() BlockTransactions {
+	txns := make(BlockTransactions, len(body.Transactions))
+	for i, txn := range body.Transactions {
+		txns[i] = txn.Transaction
+	}
+	return txns
+}
+
+// receipts returns the receipts of the body.
+func (body BlockBodyWithReceipts) receipts() []TransactionReceipt {
+	receipts := make([]TransactionReceipt, len(body.Transactions))
+	for i, txn := range body.Transactions {
+		receipts[i] = txn.Receipt
+	}
+	return receipts
+}
+
+// toBlockHeader returns the fields of the pending header in a BlockHeader.
+func (header PendingBlockHeader) toBlockHeader() BlockHeader {
+	return BlockHeader{
+		ParentHash:       header.ParentHash,
+		Timestamp:        header.Timestamp,
+		SequencerAddress: header.SequencerAddress,
+		L1GasPrice:       header.L1GasPrice,
+		L1DataGasPrice:   header.L1DataGasPrice,
+		L2GasPrice:       header.L2GasPrice,
+		L1DAMode:         header.L1DAMode,
+		StarknetVersion:  header.StarknetVersion,
+	}
+}
+
+// toPendingBlockHeader returns the fields of the header known for the pending block.
+func (header BlockHeader) toPendingBlockHeader() PendingBlockHeader {
+	return PendingBlockHeader{
+		ParentHash:       header.ParentHash,
+		Timestamp:        header.Timestamp,
+		SequencerAddress: header.SequencerAddress,
+		L1GasPrice:       header.L1GasPrice,
+		StarknetVersion:  header.StarknetVersion,
+		L1DataGasPrice:   header.L1DataGasPrice,
+		L2GasPrice:       header.L2GasPrice,
+		L1DAMode:         header.L1DAMode,
+	}
+}
@@ -167,3 +167,63 @@ func TestBlockWithReceipts(t *testing.T) {
 		})
 	}
 }
+
+// TestBlockResult tests the accessors of the BlockResult returned for accepted and pending blocks.
+func TestBlockResult(t *testing.T) {
+	provider := &Provider{c: &rpcMock{}}
+	ctx := context.Background()
+
+	block, err := FetchBlockWithReceipts(ctx, provider, BlockID{Tag: "latest"})
+	require.NoError(t, err)
+	require.False(t, block.IsPending())
+	require.Equal(t, BlockStatus_AcceptedOnL2, block.Status())
+	require.Equal(t, uint64(332275), block.Header().BlockNumber)
+
+	txns, receipts := block.Transactions(), block.Receipts()
+	require.NotEmpty(t, txns)
+	require.Len(t, receipts, len(txns))
+	require.IsType(t, BlockInvokeTxnV3{}, txns[0])
+	require.IsType(t, InvokeTransactionReceipt{}, receipts[0])
+	for i, hash := range block.TransactionHashes() {
+		require.Equal(t, receipts[i].Hash(), hash)
+	}
+
+	pending, err := FetchBlockWithTxHashes(ctx, provider, BlockID{Tag: "pending"})
+	require.NoError(t, err)
+	require.True(t, pending.IsPending())
+	require.Equal(t, BlockStatus_Pending, pending.Status())
+	require.Nil(t, pending.Header().BlockHash)
+	require.Equal(t, uint64(123), pending.Header().Timestamp)
+	require.Nil(t, pending.Transactions())
+	require.Nil(t, pending.Receipts())
+	require.Len(t, pending.TransactionHashes(), 2)
+
+	_, err = NewBlockResult(BlockTxHashes{})
+	require.Error(t, err)
+}
+
+// TestTransactionWithReceipt_Unmarshal tests that the transactions of a block with receipts are decoded to their concrete types.
+func TestTransactionWithReceipt_Unmarshal(t *testing.T) {
+	data := []byte(`{
+		"transaction": {
+			"type": "DEPLOY_ACCOUNT", "version": "0x3", "nonce": "0x0", "signature": [],
+			"contract_address_salt": "0x1", "constructor_calldata": [], "class_hash": "0x2",
+			"resource_bounds": {"l1_gas": {"max_amount": "0x1", "max_price_per_unit": "0x1"}, "l2_gas": {"max_amount": "0x0", "max_price_per_unit": "0x0"}},
+			"tip": "0x0", "paymaster_data": [], "nonce_data_availability_mode": "L1", "fee_data_availability_mode": "L1"
+		},
+		"receipt": {
+			"type": "DEPLOY_ACCOUNT", "transaction_hash": "0x3", "actual_fee": {"amount": "0x1", "unit": "FRI"},
+			"execution_status": "SUCCEEDED", "finality_status": "ACCEPTED_ON_L2", "messages_sent": [], "events": [],
+			"execution_resources": {"steps": 1, "data_availability": {"l1_gas": 0, "l1_data_gas": 1}},
+			"contract_address": "0x4"
+		}
+	}`)
+
+	var txn TransactionWithReceipt
+	require.NoError(t, json.Unmarshal(data, &txn))
+	deployAccount, ok := txn.Transaction.(BlockDeployAccountTxnV3)
+	require.True(t, ok, "should be BlockDeployAccountTxnV3, instead: %T", txn.Transaction)
+	require.Equal(t, "0x3", deployAccount.Hash().String())
+	require.Equal(t, "0x2", deployAccount.ClassHash.String())
+	require.IsType(t, DeployAccountTransactionReceipt{}, txn.Receipt)
+}
@@ -23,6 +23,7 @@ var _ BlockTransaction = BlockDeclareTxnV2{}
 var _ BlockTransaction = BlockDeclareTxnV3{}
 var _ BlockTransaction = BlockDeployTxn{}
 var _ BlockTransaction = BlockDeployAccountTxn{}
+var _ BlockTransaction = BlockDeployAccountTxnV3{}
 var _ BlockTransaction = BlockL1HandlerTxn{}
 
 // Hash returns the transaction hash of the BlockInvokeTxnV0.
@@ -70,6 +71,11 @@ func (tx BlockDeployAccountTxn) Hash() *felt.Felt {
 	return tx.TransactionHash
 }
 
+// Hash returns the Felt hash of the BlockDeployAccountTxnV3.
+func (tx BlockDeployAccountTxnV3) Hash() *felt.Felt {
+	return tx.TransactionHash
+}
+
 // Hash returns the hash of the BlockL1HandlerTxn.
 func (tx BlockL1HandlerTxn) Hash() *felt.Felt {
 	return tx.TransactionHash
@@ -125,6 +131,11 @@ type BlockDeployAccountTxn struct {
 	DeployAccountTxn
 }
 
+type BlockDeployAccountTxnV3 struct {
+	TransactionHash *felt.Felt `json:"transaction_hash"`
+	DeployAccountTxnV3
+}
+
 // UnmarshalJSON unmarshals the data into a BlockTransactions object.
 //
 // It takes a byte slice as the parameter, representing the JSON data to be unmarshalled.
@@ -191,6 +202,11 @@ func unmarshalBlockTxn(t interface{}) (BlockTransaction, error) {
 			err := remarshal(casted, &txn)
 			return txn, err
 		case TransactionType_DeployAccount:
+			if casted["version"].(string) == "0x3" {
+				var txn BlockDeployAccountTxnV3
+				err := remarshal(casted, &txn)
+				return txn, err
+			}
 			var txn BlockDeployAccountTxn
 			err := remarshal(casted, &txn)
 			return txn, err
@@ -121,3 +121,16 @@ type BroadcastDeployAccountTxnV3 struct {
 func (tx BroadcastDeployAccountTxnV3) GetConstructorCalldata() []*felt.Felt {
 	return tx.ConstructorCalldata
 }
+
+// broadcastVersion returns the version of a broadcast transaction, read from the transaction as it is
+// sent to the node, so that the pointers to the transactions and any other implementation of the
+// broadcast interfaces are covered. It returns an empty version if the transaction cannot be encoded.
+func broadcastVersion(txn BroadcastTxn) TransactionVersion {
+	var fields struct {
+		Version TransactionVersion `json:"version"`
+	}
+	if err := remarshal(txn, &fields); err != nil {
+		return ""
+	}
+	return fields.Version
+}
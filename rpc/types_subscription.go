@@ -0,0 +1,81 @@
+package rpc
+
+import (
+	"encoding/json"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// EventSubscriptionInput is the filter of a starknet_subscribeEvents subscription.
+type EventSubscriptionInput struct {
+	// FromAddress filters the events emitted by a contract, all the contracts if nil
+	FromAddress *felt.Felt `json:"from_address,omitempty"`
+	// Keys filters the events by their keys, following the same rules as EventFilter.Keys
+	Keys [][]*felt.Felt `json:"keys,omitempty"`
+	// BlockID is the block from which the events are sent, the latest block if nil
+	BlockID *BlockID `json:"block_id,omitempty"`
+}
+
+// PendingTxnsInput is the filter of a starknet_subscribePendingTransactions subscription.
+type PendingTxnsInput struct {
+	// TransactionDetails requests the full transactions instead of their hashes
+	TransactionDetails bool `json:"transaction_details,omitempty"`
+	// SenderAddress filters the transactions by sender, all the senders if empty
+	SenderAddress []*felt.Felt `json:"sender_address,omitempty"`
+}
+
+// NewTxnStatus is the notification sent by a starknet_subscribeTransactionStatus subscription.
+type NewTxnStatus struct {
+	TransactionHash *felt.Felt    `json:"transaction_hash"`
+	Status          TxnStatusResp `json:"status"`
+}
+
+// PendingTxn is the notification sent by a starknet_subscribePendingTransactions subscription.
+// Transaction is only set when the subscription was created with TransactionDetails.
+type PendingTxn struct {
+	TransactionHash *felt.Felt
+	Transaction     BlockTransaction
+}
+
+// UnmarshalJSON unmarshals the JSON data into a PendingTxn object.
+// The node either sends the hash of the transaction or the full transaction.
+//
+// Parameters:
+// - data: The JSON data to be unmarshalled
+// Returns:
+// - error: An error if the unmarshalling process fails
+func (txn *PendingTxn) UnmarshalJSON(data []byte) error {
+	var hash felt.Felt
+	if err := json.Unmarshal(data, &hash); err == nil {
+		*txn = PendingTxn{TransactionHash: &hash}
+		return nil
+	}
+
+	var dec map[string]interface{}
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+	t, err := unmarshalBlockTxn(dec)
+	if err != nil {
+		return err
+	}
+	var withHash struct {
+		TransactionHash *felt.Felt `json:"transaction_hash"`
+	}
+	if err := json.Unmarshal(data, &withHash); err != nil {
+		return err
+	}
+
+	*txn = PendingTxn{TransactionHash: withHash.TransactionHash, Transaction: t}
+	return nil
+}
+
+// ReorgEvent is the notification sent on every subscription when the chain
+// reorganises. The blocks from StartingBlock to EndingBlock (included) are no
+// longer part of the canonical chain and the data received for them must be discarded.
+type ReorgEvent struct {
+	StartingBlockHash   *felt.Felt `json:"starting_block_hash"`
+	StartingBlockNumber uint64     `json:"starting_block_number"`
+	EndingBlockHash     *felt.Felt `json:"ending_block_hash"`
+	EndingBlockNumber   uint64     `json:"ending_block_number"`
+}
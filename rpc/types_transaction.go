@@ -147,6 +147,9 @@ type ResourceBoundsMapping struct {
 	L1Gas ResourceBounds `json:"l1_gas"`
 	// The max amount and max price per unit of L2 gas used in this tx
 	L2Gas ResourceBounds `json:"l2_gas"`
+	// The max amount and max price per unit of L1 data gas used in this tx, declared and signed by the
+	// transactions sent to the nodes following spec v0.8 and later, and nil for the older nodes
+	L1DataGas *ResourceBounds `json:"l1_data_gas,omitempty"`
 }
 
 type DataAvailabilityMode string
@@ -169,8 +172,9 @@ func (da *DataAvailabilityMode) UInt64() (uint64, error) {
 type Resource string
 
 const (
-	ResourceL1Gas Resource = "L1_GAS"
-	ResourceL2Gas Resource = "L2_GAS"
+	ResourceL1Gas     Resource = "L1_GAS"
+	ResourceL2Gas     Resource = "L2_GAS"
+	ResourceL1DataGas Resource = "L1_DATA"
 )
 
 type ResourceBounds struct {
@@ -314,6 +318,11 @@ func unmarshalTxn(t interface{}) (Transaction, error) {
 			err := remarshal(casted, &txn)
 			return txn, err
 		case TransactionType_DeployAccount:
+			if casted["version"].(string) == "0x3" {
+				var txn DeployAccountTxnV3
+				err := remarshal(casted, &txn)
+				return txn, err
+			}
 			var txn DeployAccountTxn
 			err := remarshal(casted, &txn)
 			return txn, err
@@ -379,6 +388,10 @@ const (
 	TransactionV3WithQueryBit TransactionVersion = "0x100000000000000000000000000000003"
 )
 
+// queryBit is the offset of the query versions, 2^128, which are the versions of the transactions
+// signed to estimate their fee or simulate them, and which cannot be executed on-chain.
+var queryBit = new(big.Int).Lsh(big.NewInt(1), 128)
+
 // BigInt returns a big integer corresponding to the transaction version.
 //
 // Parameters:
@@ -389,12 +402,59 @@ const (
 // - *big.Int: a pointer to a big.Int
 // - error: an error if the conversion fails
 func (v *TransactionVersion) BigInt() (*big.Int, error) {
-	switch *v {
-	case TransactionV0:
-		return big.NewInt(0), nil
-	case TransactionV1:
-		return big.NewInt(1), nil
-	default:
-		return big.NewInt(-1), errors.New(fmt.Sprint("TransactionVersion %i not supported", *v))
+	version, ok := new(big.Int).SetString(string(*v), 0)
+	if !ok || version.Sign() < 0 {
+		return big.NewInt(-1), fmt.Errorf("TransactionVersion %s not supported", *v)
+	}
+	return version, nil
+}
+
+// IsQuery returns whether the version is a query version, such as TransactionV1WithQueryBit.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - bool: true for a query version
+func (v TransactionVersion) IsQuery() bool {
+	version, err := v.BigInt()
+	if err != nil {
+		return false
+	}
+	return version.Cmp(queryBit) >= 0
+}
+
+// WithQueryBit returns the query version of the version, such as TransactionV1WithQueryBit for
+// TransactionV1. A query version or an invalid version is returned as is.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - TransactionVersion: the query version
+func (v TransactionVersion) WithQueryBit() TransactionVersion {
+	version, err := v.BigInt()
+	if err != nil || version.Cmp(queryBit) >= 0 {
+		return v
+	}
+	return TransactionVersion(fmt.Sprintf("0x%x", version.Add(version, queryBit)))
+}
+
+// Base returns the version of which the version is the query version, such as TransactionV1
+// for TransactionV1WithQueryBit. A version which is not a query version is returned as is.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - TransactionVersion: the version without the query bit
+func (v TransactionVersion) Base() TransactionVersion {
+	version, err := v.BigInt()
+	if err != nil || version.Cmp(queryBit) < 0 {
+		return v
 	}
+	return TransactionVersion(fmt.Sprintf("0x%x", version.Sub(version, queryBit)))
 }
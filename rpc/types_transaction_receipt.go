@@ -46,6 +46,11 @@ func (tr CommonTransactionReceipt) GetExecutionStatus() TxnExecutionStatus {
 	return tr.ExecutionStatus
 }
 
+// GetFinalityStatus returns the finality status of the CommonTransactionReceipt.
+func (tr CommonTransactionReceipt) GetFinalityStatus() TxnFinalityStatus {
+	return tr.FinalityStatus
+}
+
 // TODO: check how we can move that type up in starknet.go/types
 type TransactionType string
 
@@ -121,6 +126,11 @@ func (tr InvokeTransactionReceipt) GetExecutionStatus() TxnExecutionStatus {
 	return tr.ExecutionStatus
 }
 
+// GetFinalityStatus returns the finality status of the InvokeTransactionReceipt.
+func (tr InvokeTransactionReceipt) GetFinalityStatus() TxnFinalityStatus {
+	return tr.FinalityStatus
+}
+
 // DeclareTransactionReceipt Declare Transaction Receipt
 type DeclareTransactionReceipt CommonTransactionReceipt
 
@@ -134,6 +144,11 @@ func (tr DeclareTransactionReceipt) GetExecutionStatus() TxnExecutionStatus {
 	return tr.ExecutionStatus
 }
 
+// GetFinalityStatus returns the finality status of the DeclareTransactionReceipt.
+func (tr DeclareTransactionReceipt) GetFinalityStatus() TxnFinalityStatus {
+	return tr.FinalityStatus
+}
+
 // DeployTransactionReceipt Deploy  Transaction Receipt
 type DeployTransactionReceipt struct {
 	CommonTransactionReceipt
@@ -151,6 +166,11 @@ func (tr DeployTransactionReceipt) GetExecutionStatus() TxnExecutionStatus {
 	return tr.ExecutionStatus
 }
 
+// GetFinalityStatus returns the finality status of the DeployTransactionReceipt.
+func (tr DeployTransactionReceipt) GetFinalityStatus() TxnFinalityStatus {
+	return tr.FinalityStatus
+}
+
 // DeployAccountTransactionReceipt Deploy Account Transaction Receipt
 type DeployAccountTransactionReceipt struct {
 	CommonTransactionReceipt
@@ -168,6 +188,11 @@ func (tr DeployAccountTransactionReceipt) GetExecutionStatus() TxnExecutionStatu
 	return tr.ExecutionStatus
 }
 
+// GetFinalityStatus returns the finality status of the DeployAccountTransactionReceipt.
+func (tr DeployAccountTransactionReceipt) GetFinalityStatus() TxnFinalityStatus {
+	return tr.FinalityStatus
+}
+
 // L1HandlerTransactionReceipt L1 Handler Transaction Receipt
 type L1HandlerTransactionReceipt struct {
 	MessageHash NumAsHex `json:"message_hash"`
@@ -184,6 +209,11 @@ func (tr L1HandlerTransactionReceipt) GetExecutionStatus() TxnExecutionStatus {
 	return tr.ExecutionStatus
 }
 
+// GetFinalityStatus returns the finality status of the L1HandlerTransactionReceipt.
+func (tr L1HandlerTransactionReceipt) GetFinalityStatus() TxnFinalityStatus {
+	return tr.FinalityStatus
+}
+
 type ComputationResources struct {
 	// The number of Cairo steps used
 	Steps int `json:"steps"`
@@ -221,6 +251,12 @@ func (er *ComputationResources) Validate() bool {
 type ExecutionResources struct {
 	ComputationResources
 	DataAvailability `json:"data_availability"`
+	// the total L1 gas consumed by the transaction, only given by the nodes following spec v0.8 and later
+	TotalL1Gas uint `json:"l1_gas,omitempty"`
+	// the total L1 data gas consumed by the transaction, only given by the nodes following spec v0.8 and later
+	TotalL1DataGas uint `json:"l1_data_gas,omitempty"`
+	// the L2 gas consumed by the transaction, only given by the nodes following spec v0.8 and later
+	L2Gas uint `json:"l2_gas,omitempty"`
 }
 
 type DataAvailability struct {
@@ -235,6 +271,12 @@ type TransactionReceipt interface {
 	GetExecutionStatus() TxnExecutionStatus
 }
 
+// finalityStatusReceipt is a TransactionReceipt reporting its finality status, as the receipts of this package do.
+type finalityStatusReceipt interface {
+	TransactionReceipt
+	GetFinalityStatus() TxnFinalityStatus
+}
+
 type OrderedMsg struct {
 	// The order of the message within the transaction
 	Order   int `json:"order"`
@@ -379,12 +421,13 @@ func (t *TransactionReceiptWithBlockInfo) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &aux); err != nil {
 		return err
 	}
-	blockHash, err := new(felt.Felt).SetString(aux.BlockHash)
-	if err != nil {
-		return err
+	if aux.BlockHash != "" {
+		blockHash, err := new(felt.Felt).SetString(aux.BlockHash)
+		if err != nil {
+			return err
+		}
+		t.BlockHash = blockHash
 	}
-
-	t.BlockHash = blockHash
 	t.BlockNumber = aux.BlockNumber
 
 	return nil
@@ -397,9 +440,12 @@ func (t *TransactionReceiptWithBlockInfo) MarshalJSON() ([]byte, error) {
 		BlockNumber uint   `json:"block_number,omitempty"`
 	}{
 		TransactionReceipt: t.UnknownTransactionReceipt.TransactionReceipt,
-		BlockHash:          t.BlockHash.String(),
 		BlockNumber:        t.BlockNumber,
 	}
+	// the receipts of pending transactions have no block hash
+	if t.BlockHash != nil {
+		aux.BlockHash = t.BlockHash.String()
+	}
 
 	return json.Marshal(aux)
 }
@@ -0,0 +1,48 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestTransactionVersionQueryBit tests the conversions between the versions and their query versions.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestTransactionVersionQueryBit(t *testing.T) {
+	type testSetType struct {
+		Version       TransactionVersion
+		ExpectedQuery TransactionVersion
+		ExpectedBase  TransactionVersion
+	}
+	testSet := []testSetType{
+		{Version: TransactionV0, ExpectedQuery: TransactionV0WithQueryBit, ExpectedBase: TransactionV0},
+		{Version: TransactionV1, ExpectedQuery: TransactionV1WithQueryBit, ExpectedBase: TransactionV1},
+		{Version: TransactionV2, ExpectedQuery: TransactionV2WithQueryBit, ExpectedBase: TransactionV2},
+		{Version: TransactionV3, ExpectedQuery: TransactionV3WithQueryBit, ExpectedBase: TransactionV3},
+		{Version: TransactionV1WithQueryBit, ExpectedQuery: TransactionV1WithQueryBit, ExpectedBase: TransactionV1},
+		{Version: TransactionV3WithQueryBit, ExpectedQuery: TransactionV3WithQueryBit, ExpectedBase: TransactionV3},
+	}
+	for _, test := range testSet {
+		require.Equal(t, test.ExpectedQuery, test.Version.WithQueryBit())
+		require.Equal(t, test.ExpectedBase, test.Version.Base())
+		require.Equal(t, test.Version != test.ExpectedBase, test.Version.IsQuery())
+
+		version, err := test.Version.BigInt()
+		require.NoError(t, err)
+		base, err := test.ExpectedBase.BigInt()
+		require.NoError(t, err)
+		require.Equal(t, test.Version.IsQuery(), version.Cmp(base) != 0)
+	}
+
+	invalid := TransactionVersion("v1")
+	require.False(t, invalid.IsQuery())
+	require.Equal(t, invalid, invalid.WithQueryBit())
+	require.Equal(t, invalid, invalid.Base())
+	_, err := invalid.BigInt()
+	require.Error(t, err)
+}
@@ -0,0 +1,308 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+var (
+	ErrConnectionClosed          = errors.New("websocket connection closed")
+	ErrSubscriptionQueueOverflow = errors.New("subscription queue overflow")
+)
+
+// maxSubscriptionBuffer is the number of notifications a subscription can
+// hold before the consumer is considered too slow and the subscription is dropped.
+const maxSubscriptionBuffer = 20000
+
+// WsProvider provides the websocket provider for starknet.go/rpc implementation.
+// On top of the request/response methods of Provider, it exposes the
+// starknet_subscribe* methods of the Starknet websocket API.
+type WsProvider struct {
+	*Provider
+	ws *wsClient
+}
+
+// NewWebsocketProvider creates a new websocket rpc Provider instance.
+//
+// Parameters:
+// - url: the websocket URL of the RPC endpoint (ws:// or wss://)
+// - header: optional HTTP headers sent with the websocket handshake
+// Returns:
+// - *WsProvider: a new WsProvider
+// - error: an error if any occurred while dialing the endpoint
+func NewWebsocketProvider(url string, header ...http.Header) (*WsProvider, error) {
+	var h http.Header
+	if len(header) > 0 {
+		h = header[0]
+	}
+	conn, _, err := websocket.DefaultDialer.DialContext(context.Background(), url, h)
+	if err != nil {
+		return nil, err
+	}
+	ws := newWsClient(conn)
+	return &WsProvider{
+		Provider: &Provider{c: ws},
+		ws:       ws,
+	}, nil
+}
+
+// Close closes the underlying websocket connection and terminates all the active subscriptions.
+func (provider *WsProvider) Close() {
+	provider.ws.Close()
+}
+
+// wsMessage is the envelope of every JSON-RPC message exchanged over the websocket.
+type wsMessage struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+type wsRequest struct {
+	Version string      `json:"jsonrpc"`
+	ID      uint64      `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+// wsNotificationParams is the content of the params field of a subscription notification.
+type wsNotificationParams struct {
+	SubscriptionID json.RawMessage `json:"subscription_id"`
+	Result         json.RawMessage `json:"result"`
+}
+
+// wsPendingCall is a request waiting for its response. When sub is set, the
+// request is a subscription request and the subscription is registered by the
+// read loop as soon as the response is received, so that no notification is lost.
+type wsPendingCall struct {
+	resp chan *wsMessage
+	sub  *Subscription
+}
+
+// wsClient is a JSON-RPC 2.0 client over a websocket connection, able to
+// dispatch the Starknet subscription notifications.
+type wsClient struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	nextID  uint64
+	pending map[uint64]*wsPendingCall
+	subs    map[string]*Subscription
+	err     error
+
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+var _ CallCloser = &wsClient{}
+
+// newWsClient creates a wsClient on top of an established connection and starts its read loop.
+func newWsClient(conn *websocket.Conn) *wsClient {
+	c := &wsClient{
+		conn:    conn,
+		pending: make(map[uint64]*wsPendingCall),
+		subs:    make(map[string]*Subscription),
+		closed:  make(chan struct{}),
+	}
+	go c.readLoop()
+	return c
+}
+
+// CallContext performs a JSON-RPC call over the websocket connection.
+//
+// Parameters:
+// - ctx: represents the current execution context
+// - result: the interface{} to store the result of the RPC call
+// - method: the string representing the RPC method to be called
+// - args: variadic and can be used to pass additional arguments to the RPC method
+// Returns:
+// - error: an error if any occurred during the function call
+func (c *wsClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if args == nil {
+		args = []interface{}{}
+	}
+	msg, err := c.send(ctx, method, args, nil)
+	if err != nil {
+		return err
+	}
+	if len(msg.Result) == 0 {
+		return nil
+	}
+	return json.Unmarshal(msg.Result, result)
+}
+
+// send writes a request and waits for its response.
+func (c *wsClient) send(ctx context.Context, method string, params interface{}, sub *Subscription) (*wsMessage, error) {
+	call := &wsPendingCall{resp: make(chan *wsMessage, 1), sub: sub}
+
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return nil, c.err
+	}
+	c.nextID++
+	id := c.nextID
+	c.pending[id] = call
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	c.writeMu.Lock()
+	err := c.conn.WriteJSON(wsRequest{Version: "2.0", ID: id, Method: method, Params: params})
+	c.writeMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case msg := <-call.resp:
+		if msg.Error != nil {
+			return nil, msg.Error
+		}
+		return msg, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.closed:
+		return nil, c.closeErr()
+	}
+}
+
+// readLoop reads the incoming messages and dispatches them to the pending calls
+// and to the active subscriptions until the connection is closed.
+func (c *wsClient) readLoop() {
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			c.closeWithErr(err)
+			return
+		}
+		var msg wsMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			continue
+		}
+		if msg.Method != "" {
+			c.dispatchNotification(&msg)
+			continue
+		}
+		c.dispatchResponse(&msg)
+	}
+}
+
+// dispatchResponse delivers a response to the call waiting for it.
+func (c *wsClient) dispatchResponse(msg *wsMessage) {
+	id, err := strconv.ParseUint(string(msg.ID), 10, 64)
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	call, ok := c.pending[id]
+	if !ok {
+		return
+	}
+	if call.sub != nil && msg.Error == nil {
+		subID, err := parseSubscriptionID(msg.Result)
+		if err != nil {
+			msg.Error = Err(InternalError, err.Error())
+		} else {
+			call.sub.id = subID
+			c.subs[subID] = call.sub
+		}
+	}
+	call.resp <- msg
+}
+
+// dispatchNotification queues a notification in the subscription it belongs to.
+func (c *wsClient) dispatchNotification(msg *wsMessage) {
+	var params wsNotificationParams
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		return
+	}
+	subID, err := parseSubscriptionID(params.SubscriptionID)
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	sub, ok := c.subs[subID]
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+	select {
+	case sub.queue <- subscriptionNotification{method: msg.Method, result: params.Result}:
+	default:
+		go sub.fail(ErrSubscriptionQueueOverflow)
+	}
+}
+
+// removeSubscription forgets a subscription so that its notifications are ignored.
+func (c *wsClient) removeSubscription(sub *Subscription) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.subs[sub.id] == sub {
+		delete(c.subs, sub.id)
+	}
+}
+
+// Close closes the websocket connection.
+func (c *wsClient) Close() {
+	c.closeWithErr(ErrConnectionClosed)
+}
+
+// closeWithErr closes the connection and terminates every subscription with the given error.
+func (c *wsClient) closeWithErr(err error) {
+	c.closeOnce.Do(func() {
+		c.mu.Lock()
+		if !errors.Is(err, ErrConnectionClosed) {
+			err = fmt.Errorf("%w: %v", ErrConnectionClosed, err)
+		}
+		c.err = err
+		subs := make([]*Subscription, 0, len(c.subs))
+		for _, sub := range c.subs {
+			subs = append(subs, sub)
+		}
+		c.subs = make(map[string]*Subscription)
+		c.mu.Unlock()
+
+		close(c.closed)
+		_ = c.conn.Close()
+		for _, sub := range subs {
+			sub.fail(err)
+		}
+	})
+}
+
+// closeErr returns the error that closed the connection.
+func (c *wsClient) closeErr() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// parseSubscriptionID parses a subscription ID, that nodes either send as a string or as a number.
+func parseSubscriptionID(raw json.RawMessage) (string, error) {
+	var id string
+	if err := json.Unmarshal(raw, &id); err == nil {
+		return id, nil
+	}
+	var num json.Number
+	if err := json.Unmarshal(raw, &num); err != nil {
+		return "", fmt.Errorf("invalid subscription id %s", raw)
+	}
+	return num.String(), nil
+}
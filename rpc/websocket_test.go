@@ -0,0 +1,370 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+// wsStandInRequest is a request received by the websocket stand-in.
+type wsStandInRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+// wsStandInReply is the reply of the websocket stand-in to a request. The
+// notifications are sent right after the response.
+type wsStandInReply struct {
+	result        interface{}
+	err           *RPCError
+	notifications []interface{}
+}
+
+// wsStandIn is an in-process websocket JSON-RPC node used to test the WsProvider.
+type wsStandIn struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	conn     *websocket.Conn
+	requests []wsStandInRequest
+}
+
+// newWsStandIn starts a websocket stand-in answering the requests with handle.
+//
+// Parameters:
+// - t: The testing.T object for testing purposes
+// - handle: the function building the reply to a request
+// Returns:
+// - *wsStandIn: the running stand-in
+func newWsStandIn(t *testing.T, handle func(req wsStandInRequest) wsStandInReply) *wsStandIn {
+	t.Helper()
+	s := &wsStandIn{}
+	upgrader := websocket.Upgrader{}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conn = conn
+		s.mu.Unlock()
+		for {
+			var req wsStandInRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.requests = append(s.requests, req)
+			s.mu.Unlock()
+
+			reply := handle(req)
+			resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+			if reply.err != nil {
+				resp["error"] = reply.err
+			} else {
+				resp["result"] = reply.result
+			}
+			if err := conn.WriteJSON(resp); err != nil {
+				return
+			}
+			for _, n := range reply.notifications {
+				if err := conn.WriteJSON(n); err != nil {
+					return
+				}
+			}
+		}
+	}))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+// url returns the websocket URL of the stand-in.
+func (s *wsStandIn) url() string {
+	return "ws" + strings.TrimPrefix(s.server.URL, "http")
+}
+
+// request returns the i-th request received by the stand-in.
+func (s *wsStandIn) request(i int) wsStandInRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[i]
+}
+
+// closeConn closes the connection with the client.
+func (s *wsStandIn) closeConn() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conn.Close()
+}
+
+// wsNotification builds a subscription notification.
+func wsNotification(method string, subID interface{}, result interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  method,
+		"params": map[string]interface{}{
+			"subscription_id": subID,
+			"result":          result,
+		},
+	}
+}
+
+// receive reads a value from ch or fails the test after a timeout.
+func receive[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+	select {
+	case v, ok := <-ch:
+		require.True(t, ok, "channel closed")
+		return v
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for a notification")
+	}
+	var zero T
+	return zero
+}
+
+func TestWebsocketProviderCall(t *testing.T) {
+	standIn := newWsStandIn(t, func(req wsStandInRequest) wsStandInReply {
+		var result string
+		require.NoError(t, mock_starknet_chainId(&result, req.Method))
+		return wsStandInReply{result: result}
+	})
+
+	provider, err := NewWebsocketProvider(standIn.url())
+	require.NoError(t, err)
+	defer provider.Close()
+
+	chainID, err := provider.ChainID(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "SN_SEPOLIA", chainID)
+	require.Equal(t, "starknet_chainId", standIn.request(0).Method)
+}
+
+func TestSubscribeNewHeads(t *testing.T) {
+	standIn := newWsStandIn(t, func(req wsStandInRequest) wsStandInReply {
+		switch req.Method {
+		case "starknet_subscribeNewHeads":
+			// the notifications are sent right after the subscription id and must not be lost
+			return wsStandInReply{
+				result: 42,
+				notifications: []interface{}{
+					wsNotification("starknet_subscriptionNewHeads", 42, map[string]interface{}{"block_hash": "0x1", "parent_hash": "0x0", "block_number": 1}),
+					wsNotification("starknet_subscriptionReorg", 42, map[string]interface{}{
+						"starting_block_hash":   "0x1",
+						"starting_block_number": 1,
+						"ending_block_hash":     "0x1",
+						"ending_block_number":   1,
+					}),
+					wsNotification("starknet_subscriptionNewHeads", 42, map[string]interface{}{"block_hash": "0x2", "parent_hash": "0x0", "block_number": 1}),
+				},
+			}
+		case "starknet_unsubscribe":
+			return wsStandInReply{result: true}
+		}
+		return wsStandInReply{err: ErrUnexpectedError}
+	})
+
+	provider, err := NewWebsocketProvider(standIn.url())
+	require.NoError(t, err)
+	defer provider.Close()
+
+	blockNumber := uint64(1)
+	heads, sub, err := provider.SubscribeNewHeads(context.Background(), &BlockID{Number: &blockNumber})
+	require.NoError(t, err)
+	require.Equal(t, "42", sub.ID())
+	require.JSONEq(t, `{"block_id":{"block_number":1}}`, string(standIn.request(0).Params))
+
+	head := receive(t, heads)
+	require.Equal(t, utils.TestHexToFelt(t, "0x1"), head.BlockHash)
+	reorg := receive(t, sub.Reorg())
+	require.Equal(t, uint64(1), reorg.StartingBlockNumber)
+	require.Equal(t, utils.TestHexToFelt(t, "0x1"), reorg.EndingBlockHash)
+	head = receive(t, heads)
+	require.Equal(t, utils.TestHexToFelt(t, "0x2"), head.BlockHash)
+
+	require.NoError(t, sub.Unsubscribe())
+	require.NoError(t, sub.Unsubscribe())
+	require.JSONEq(t, `{"subscription_id":"42"}`, string(standIn.request(1).Params))
+	_, ok := <-heads
+	require.False(t, ok)
+	err, ok = <-sub.Err()
+	require.False(t, ok)
+	require.Nil(t, err)
+}
+
+func TestSubscriptionUnreadReorgs(t *testing.T) {
+	// more reorgs than the subscription holds, followed by a header
+	notifications := []interface{}{}
+	for i := 1; i <= maxReorgBuffer+50; i++ {
+		notifications = append(notifications, wsNotification("starknet_subscriptionReorg", 42, map[string]interface{}{
+			"starting_block_hash":   "0x1",
+			"starting_block_number": i,
+			"ending_block_hash":     "0x1",
+			"ending_block_number":   i,
+		}))
+	}
+	notifications = append(notifications,
+		wsNotification("starknet_subscriptionNewHeads", 42, map[string]interface{}{"block_hash": "0x2", "parent_hash": "0x0", "block_number": 1}))
+	standIn := newWsStandIn(t, func(req wsStandInRequest) wsStandInReply {
+		return wsStandInReply{result: 42, notifications: notifications}
+	})
+
+	provider, err := NewWebsocketProvider(standIn.url())
+	require.NoError(t, err)
+	defer provider.Close()
+
+	heads, sub, err := provider.SubscribeNewHeads(context.Background(), nil)
+	require.NoError(t, err)
+
+	// the unread reorgs do not end the subscription
+	head := receive(t, heads)
+	require.Equal(t, utils.TestHexToFelt(t, "0x2"), head.BlockHash)
+	select {
+	case err := <-sub.Err():
+		t.Fatal("subscription ended:", err)
+	default:
+	}
+
+	// only the last reorgs are kept
+	require.Len(t, sub.Reorg(), maxReorgBuffer)
+	for i := 51; i <= maxReorgBuffer+50; i++ {
+		reorg := receive(t, sub.Reorg())
+		require.Equal(t, uint64(i), reorg.StartingBlockNumber)
+	}
+}
+
+func TestSubscribeEvents(t *testing.T) {
+	standIn := newWsStandIn(t, func(req wsStandInRequest) wsStandInReply {
+		return wsStandInReply{
+			result: "0xabc",
+			notifications: []interface{}{
+				wsNotification("starknet_subscriptionEvents", "0xabc", map[string]interface{}{
+					"from_address":     "0x49d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7",
+					"keys":             []string{"0x99cd8bde557814842a3121e8ddfd433a539b8c9f14bf31ebf108d12e6196e9"},
+					"data":             []string{"0x1", "0x2"},
+					"block_hash":       "0x59dbe64bf2e2f89f5f2958cff11044dca0c64dea2e37ec6eaad9a5f838793cb",
+					"block_number":     1472,
+					"transaction_hash": "0x568147c09d5e5db8dc703ce1da21eae47e9ad9c789bc2f2889c4413a38c579d",
+				}),
+			},
+		}
+	})
+
+	provider, err := NewWebsocketProvider(standIn.url())
+	require.NoError(t, err)
+	defer provider.Close()
+
+	address := utils.TestHexToFelt(t, "0x49d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7")
+	events, _, err := provider.SubscribeEvents(context.Background(), EventSubscriptionInput{FromAddress: address})
+	require.NoError(t, err)
+	require.Equal(t, "starknet_subscribeEvents", standIn.request(0).Method)
+	require.JSONEq(t, `{"from_address":"0x49d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7"}`, string(standIn.request(0).Params))
+
+	event := receive(t, events)
+	require.Equal(t, address, event.FromAddress)
+	require.Equal(t, uint64(1472), event.BlockNumber)
+	require.Len(t, event.Data, 2)
+}
+
+func TestSubscribeTransactionStatus(t *testing.T) {
+	standIn := newWsStandIn(t, func(req wsStandInRequest) wsStandInReply {
+		return wsStandInReply{
+			result: "1",
+			notifications: []interface{}{
+				wsNotification("starknet_subscriptionTransactionStatus", "1", map[string]interface{}{
+					"transaction_hash": "0x1234",
+					"status":           map[string]interface{}{"finality_status": "ACCEPTED_ON_L2", "execution_status": "SUCCEEDED"},
+				}),
+			},
+		}
+	})
+
+	provider, err := NewWebsocketProvider(standIn.url())
+	require.NoError(t, err)
+	defer provider.Close()
+
+	statuses, _, err := provider.SubscribeTransactionStatus(context.Background(), utils.TestHexToFelt(t, "0x1234"))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"transaction_hash":"0x1234"}`, string(standIn.request(0).Params))
+
+	status := receive(t, statuses)
+	require.Equal(t, utils.TestHexToFelt(t, "0x1234"), status.TransactionHash)
+	require.Equal(t, TxnStatus_Accepted_On_L2, status.Status.FinalityStatus)
+	require.Equal(t, TxnExecutionStatusSUCCEEDED, status.Status.ExecutionStatus)
+}
+
+func TestSubscribePendingTransactions(t *testing.T) {
+	standIn := newWsStandIn(t, func(req wsStandInRequest) wsStandInReply {
+		return wsStandInReply{
+			result: "1",
+			notifications: []interface{}{
+				wsNotification("starknet_subscriptionPendingTransactions", "1", "0x1234"),
+				wsNotification("starknet_subscriptionPendingTransactions", "1", map[string]interface{}{
+					"transaction_hash": "0x5678",
+					"type":             "INVOKE",
+					"version":          "0x1",
+					"max_fee":          "0x1",
+					"nonce":            "0x1",
+					"sender_address":   "0x1",
+					"signature":        []string{},
+					"calldata":         []string{},
+				}),
+			},
+		}
+	})
+
+	provider, err := NewWebsocketProvider(standIn.url())
+	require.NoError(t, err)
+	defer provider.Close()
+
+	txns, _, err := provider.SubscribePendingTransactions(context.Background(), PendingTxnsInput{TransactionDetails: true})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"transaction_details":true}`, string(standIn.request(0).Params))
+
+	txn := receive(t, txns)
+	require.Equal(t, utils.TestHexToFelt(t, "0x1234"), txn.TransactionHash)
+	require.Nil(t, txn.Transaction)
+
+	txn = receive(t, txns)
+	require.Equal(t, utils.TestHexToFelt(t, "0x5678"), txn.TransactionHash)
+	require.IsType(t, BlockInvokeTxnV1{}, txn.Transaction)
+}
+
+func TestSubscriptionErrors(t *testing.T) {
+	standIn := newWsStandIn(t, func(req wsStandInRequest) wsStandInReply {
+		if strings.Contains(string(req.Params), "block_number") {
+			return wsStandInReply{err: ErrTooManyBlocksBack}
+		}
+		return wsStandInReply{result: "1"}
+	})
+
+	provider, err := NewWebsocketProvider(standIn.url())
+	require.NoError(t, err)
+	defer provider.Close()
+
+	blockNumber := uint64(0)
+	_, _, err = provider.SubscribeNewHeads(context.Background(), &BlockID{Number: &blockNumber})
+	require.Equal(t, ErrTooManyBlocksBack, err)
+
+	heads, sub, err := provider.SubscribeNewHeads(context.Background(), nil)
+	require.NoError(t, err)
+
+	standIn.closeConn()
+	err = receive(t, sub.Err())
+	require.ErrorIs(t, err, ErrConnectionClosed)
+	_, ok := <-heads
+	require.False(t, ok)
+
+	_, _, err = provider.SubscribeNewHeads(context.Background(), nil)
+	require.ErrorIs(t, err, ErrConnectionClosed)
+}
@@ -2,8 +2,13 @@ package rpc
 
 import (
 	"context"
+	"errors"
 )
 
+// ErrQueryVersionBroadcast is returned by the Add*Transaction methods for a transaction with a query
+// version, such as TransactionV1WithQueryBit, which is only signed to estimate or simulate the transaction.
+var ErrQueryVersionBroadcast = errors.New("a transaction with a query version can only be estimated or simulated")
+
 // AddInvokeTransaction adds an invoke transaction to the provider.
 //
 // Parameters:
@@ -13,8 +18,11 @@ import (
 // - *AddInvokeTransactionResponse: the response of adding the invoke transaction
 // - error: an error if any
 func (provider *Provider) AddInvokeTransaction(ctx context.Context, invokeTxn BroadcastInvokeTxnType) (*AddInvokeTransactionResponse, error) {
+	if broadcastVersion(invokeTxn).IsQuery() {
+		return nil, ErrQueryVersionBroadcast
+	}
 	var output AddInvokeTransactionResponse
-	if err := do(ctx, provider.c, "starknet_addInvokeTransaction", &output, invokeTxn); err != nil {
+	if err := provider.do(ctx, "starknet_addInvokeTransaction", &output, invokeTxn); err != nil {
 		return nil, tryUnwrapToRPCErr(
 			err,
 			ErrInsufficientAccountBalance,
@@ -39,8 +47,11 @@ func (provider *Provider) AddInvokeTransaction(ctx context.Context, invokeTxn Br
 // - *AddDeclareTransactionResponse: The response of submitting the declare transaction
 // - error: an error if any
 func (provider *Provider) AddDeclareTransaction(ctx context.Context, declareTransaction BroadcastDeclareTxnType) (*AddDeclareTransactionResponse, error) {
+	if broadcastVersion(declareTransaction).IsQuery() {
+		return nil, ErrQueryVersionBroadcast
+	}
 	var result AddDeclareTransactionResponse
-	if err := do(ctx, provider.c, "starknet_addDeclareTransaction", &result, declareTransaction); err != nil {
+	if err := provider.do(ctx, "starknet_addDeclareTransaction", &result, declareTransaction); err != nil {
 		return nil, tryUnwrapToRPCErr(
 			err,
 			ErrClassAlreadyDeclared,
@@ -68,8 +79,11 @@ func (provider *Provider) AddDeclareTransaction(ctx context.Context, declareTran
 // Returns:
 // - *AddDeployAccountTransactionResponse: the response of adding the deploy account transaction or an error
 func (provider *Provider) AddDeployAccountTransaction(ctx context.Context, deployAccountTransaction BroadcastAddDeployTxnType) (*AddDeployAccountTransactionResponse, error) {
+	if broadcastVersion(deployAccountTransaction).IsQuery() {
+		return nil, ErrQueryVersionBroadcast
+	}
 	var result AddDeployAccountTransactionResponse
-	if err := do(ctx, provider.c, "starknet_addDeployAccountTransaction", &result, deployAccountTransaction); err != nil {
+	if err := provider.do(ctx, "starknet_addDeployAccountTransaction", &result, deployAccountTransaction); err != nil {
 		return nil, tryUnwrapToRPCErr(
 			err,
 			ErrInsufficientAccountBalance,
@@ -250,3 +250,36 @@ func TestAddDeployAccountTansaction(t *testing.T) {
 
 	}
 }
+
+// TestAddTransactionQueryVersion tests that the transactions with a query version are not broadcast.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestAddTransactionQueryVersion(t *testing.T) {
+	testConfig := beforeEach(t)
+	ctx := context.Background()
+
+	_, err := testConfig.provider.AddInvokeTransaction(ctx, BroadcastInvokev1Txn{InvokeTxnV1: InvokeTxnV1{Version: TransactionV1WithQueryBit}})
+	require.ErrorIs(t, err, ErrQueryVersionBroadcast)
+	_, err = testConfig.provider.AddInvokeTransaction(ctx, BroadcastInvokev3Txn{InvokeTxnV3: InvokeTxnV3{Version: TransactionV3WithQueryBit}})
+	require.ErrorIs(t, err, ErrQueryVersionBroadcast)
+	_, err = testConfig.provider.AddDeclareTransaction(ctx, BroadcastDeclareTxnV2{Version: TransactionV2WithQueryBit})
+	require.ErrorIs(t, err, ErrQueryVersionBroadcast)
+	_, err = testConfig.provider.AddDeclareTransaction(ctx, BroadcastDeclareTxnV3{DeclareTxnV3: DeclareTxnV3{Version: TransactionV3WithQueryBit}})
+	require.ErrorIs(t, err, ErrQueryVersionBroadcast)
+	_, err = testConfig.provider.AddDeployAccountTransaction(ctx, BroadcastDeployAccountTxn{DeployAccountTxn: DeployAccountTxn{Version: TransactionV1WithQueryBit}})
+	require.ErrorIs(t, err, ErrQueryVersionBroadcast)
+	_, err = testConfig.provider.AddDeployAccountTransaction(ctx, BroadcastDeployAccountTxnV3{DeployAccountTxnV3: DeployAccountTxnV3{Version: TransactionV3WithQueryBit}})
+	require.ErrorIs(t, err, ErrQueryVersionBroadcast)
+
+	// the pointers to the transactions implement the broadcast interfaces too
+	_, err = testConfig.provider.AddInvokeTransaction(ctx, &BroadcastInvokev3Txn{InvokeTxnV3: InvokeTxnV3{Version: TransactionV3WithQueryBit}})
+	require.ErrorIs(t, err, ErrQueryVersionBroadcast)
+	_, err = testConfig.provider.AddDeclareTransaction(ctx, &BroadcastDeclareTxnV3{DeclareTxnV3: DeclareTxnV3{Version: TransactionV3WithQueryBit}})
+	require.ErrorIs(t, err, ErrQueryVersionBroadcast)
+	_, err = testConfig.provider.AddDeployAccountTransaction(ctx, &BroadcastDeployAccountTxnV3{DeployAccountTxnV3: DeployAccountTxnV3{Version: TransactionV3WithQueryBit}})
+	require.ErrorIs(t, err, ErrQueryVersionBroadcast)
+}
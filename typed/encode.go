@@ -0,0 +1,460 @@
+package typed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// Revision is a revision of SNIP-12, the standard of the typed data.
+// https://github.com/starknet-io/SNIPs/blob/main/SNIPS/snip-12.md
+type Revision uint8
+
+const (
+	// Revision0 is the legacy revision, hashed with Pedersen, with the StarkNetDomain domain.
+	Revision0 Revision = 0
+	// Revision1 is the active revision, hashed with Poseidon, with the StarknetDomain domain
+	// and its revision field.
+	Revision1 Revision = 1
+)
+
+var (
+	ErrInvalidRevision = errors.New("invalid typed data revision")
+
+	starknetMessage = new(felt.Felt).SetBytes([]byte("StarkNet Message"))
+
+	// the basic types, hashed as felts in the revision 0 besides merkletree and selector
+	basicTypes = map[string]bool{
+		"felt": true, "bool": true, "string": true, "selector": true, "merkletree": true,
+		"shortstring": true, "u128": true, "i128": true, "ContractAddress": true, "ClassHash": true,
+		"timestamp": true, "enum": true,
+	}
+
+	// presetTypes are the types defined by the revision 1 for any typed data
+	presetTypes = map[string]TypeDef{
+		"u256": {Definitions: []Definition{{Name: "low", Type: "u128"}, {Name: "high", Type: "u128"}}},
+		"TokenAmount": {Definitions: []Definition{
+			{Name: "token_address", Type: "ContractAddress"}, {Name: "amount", Type: "u256"},
+		}},
+		"NftId": {Definitions: []Definition{
+			{Name: "collection_address", Type: "ContractAddress"}, {Name: "token_id", Type: "u256"},
+		}},
+	}
+
+	maxU128 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	maxI128 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1))
+	minI128 = new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 127))
+
+	hexRegexp = regexp.MustCompile(`^0[xX][0-9a-fA-F]*$`)
+)
+
+// revisionOf detects the revision of typed data: the revision 1 requires the StarknetDomain type
+// and a domain with the revision 1, the revision 0 a domain without revision or with the revision 0.
+func revisionOf(types map[string]TypeDef, dom Domain) (Revision, error) {
+	switch dom.Revision {
+	case "", "0":
+		return Revision0, nil
+	case "1":
+		if _, ok := types["StarknetDomain"]; !ok {
+			return Revision0, fmt.Errorf("%w: the revision 1 requires the StarknetDomain type", ErrInvalidRevision)
+		}
+		return Revision1, nil
+	}
+	return Revision0, fmt.Errorf("%w: %s", ErrInvalidRevision, dom.Revision)
+}
+
+// DomainType returns the name of the type of the domain of the revision of the typed data.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: StarknetDomain for the revision 1, StarkNetDomain for the revision 0
+func (td TypedData) DomainType() string {
+	if td.Revision == Revision1 {
+		return "StarknetDomain"
+	}
+	return "StarkNetDomain"
+}
+
+// allTypes returns the types of the typed data along with the preset types of its revision.
+func (td TypedData) allTypes() map[string]TypeDef {
+	if td.Revision != Revision1 {
+		return td.Types
+	}
+	types := make(map[string]TypeDef, len(td.Types)+len(presetTypes))
+	for name, typeDef := range td.Types {
+		types[name] = typeDef
+	}
+	for name, typeDef := range presetTypes {
+		types[name] = typeDef
+	}
+	return types
+}
+
+// isBasicType checks whether a type is a basic type, not defined by the typed data.
+func (td TypedData) isBasicType(inType string) bool {
+	return basicTypes[inType]
+}
+
+// MessageHash calculates the hash of the message of the typed data, MessageData, signed by an account:
+// the hash of the StarkNet Message prefix, the domain, the account address and the message, with
+// Pedersen for the revision 0 and Poseidon for the revision 1.
+//
+// Parameters:
+// - accountAddress: the address of the account signing the message
+// Returns:
+// - *felt.Felt: the message hash
+// - error: an error if the message does not match its types
+func (td TypedData) MessageHash(accountAddress *felt.Felt) (*felt.Felt, error) {
+	domainHash, err := td.StructHash(td.DomainType(), map[string]interface{}{
+		"name":     td.Domain.Name,
+		"version":  td.Domain.Version,
+		"chainId":  td.Domain.ChainId,
+		"revision": td.Domain.Revision,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not hash domain: %w", err)
+	}
+	messageHash, err := td.StructHash(td.PrimaryType, td.MessageData)
+	if err != nil {
+		return nil, fmt.Errorf("could not hash message: %w", err)
+	}
+	return td.hashElements([]*felt.Felt{starknetMessage, domainHash, accountAddress, messageHash}), nil
+}
+
+// StructHash calculates the hash of a struct of the typed data: the hash of its type hash and the
+// encoding of its fields.
+//
+// The values of the fields are a map[string]interface{} for a struct or an enum, a []interface{}
+// for an array, a merkletree or the parameters of an enum variant, and else a string (a number in
+// decimal or hexadecimal, or a short string), a bool, an integer, a json.Number, a *big.Int or a *felt.Felt.
+//
+// Parameters:
+// - inType: the type of the struct
+// - data: the values of the fields of the struct, by name
+// Returns:
+// - *felt.Felt: the hash of the struct
+// - error: an error if the data does not match the type
+func (td TypedData) StructHash(inType string, data map[string]interface{}) (*felt.Felt, error) {
+	return td.structHash(td.allTypes(), inType, data)
+}
+
+// structHash calculates the hash of a struct given all of the types of the typed data.
+func (td TypedData) structHash(types map[string]TypeDef, inType string, data map[string]interface{}) (*felt.Felt, error) {
+	typeDef, ok := types[inType]
+	if !ok {
+		return nil, fmt.Errorf("can't parse type %s from types %v", inType, td.Types)
+	}
+	typeHash := typeDef.Encoding
+	if typeHash == nil {
+		var err error
+		if typeHash, err = td.GetTypeHash(inType); err != nil {
+			return nil, err
+		}
+	}
+	elements := []*felt.Felt{utils.BigIntToFelt(typeHash)}
+	for _, def := range typeDef.Definitions {
+		value, ok := data[def.Name]
+		if !ok {
+			return nil, fmt.Errorf("missing data for %s of type %s", def.Name, inType)
+		}
+		element, err := td.encodeValue(types, def.Type, value, def)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s of type %s: %w", def.Name, inType, err)
+		}
+		elements = append(elements, element)
+	}
+	return td.hashElements(elements), nil
+}
+
+// encodeValue encodes a value of a type, def being the definition of the field holding the value.
+func (td TypedData) encodeValue(types map[string]TypeDef, inType string, value interface{}, def Definition) (*felt.Felt, error) {
+	if _, ok := types[inType]; ok {
+		data, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected an object of type %s, got %T", inType, value)
+		}
+		return td.structHash(types, inType, data)
+	}
+
+	if strings.HasSuffix(inType, "*") {
+		values, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected an array of type %s, got %T", inType, value)
+		}
+		elements := make([]*felt.Felt, len(values))
+		for i, v := range values {
+			element, err := td.encodeValue(types, strings.TrimSuffix(inType, "*"), v, def)
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = element
+		}
+		return td.hashElements(elements), nil
+	}
+
+	switch inType {
+	case "enum":
+		if td.Revision == Revision1 {
+			return td.encodeEnum(types, value, def)
+		}
+	case "merkletree":
+		leaves, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected the array of the leaves of a merkletree, got %T", value)
+		}
+		hashes := make([]*felt.Felt, len(leaves))
+		for i, leaf := range leaves {
+			hash, err := td.encodeValue(types, def.Contains, leaf, Definition{})
+			if err != nil {
+				return nil, err
+			}
+			hashes[i] = hash
+		}
+		return td.merkleRoot(hashes)
+	case "selector":
+		name, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected the name of a selector, got %T", value)
+		}
+		if hexRegexp.MatchString(name) {
+			return feltValue(name)
+		}
+		return utils.GetSelectorFromNameFelt(name), nil
+	case "string":
+		if td.Revision == Revision1 {
+			str, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected a string, got %T", value)
+			}
+			return td.hashElements(byteArray(str)), nil
+		}
+	case "i128":
+		if td.Revision == Revision1 {
+			n, err := bigValue(value)
+			if err != nil {
+				return nil, err
+			}
+			if n.Cmp(minI128) < 0 || n.Cmp(maxI128) > 0 {
+				return nil, fmt.Errorf("%s is out of the range of i128", n)
+			}
+			if n.Sign() < 0 {
+				n.Add(n, curve.Curve.P)
+			}
+			return utils.BigIntToFelt(n), nil
+		}
+	case "u128", "timestamp":
+		if td.Revision == Revision1 {
+			return rangeValue(value, inType, maxU128)
+		}
+	case "bool":
+		if td.Revision == Revision1 {
+			return rangeValue(value, inType, big.NewInt(1))
+		}
+	}
+	if !td.isBasicType(inType) {
+		return nil, fmt.Errorf("unsupported type %s", inType)
+	}
+	// the felts, the short strings, the addresses, the class hashes, and any basic value of the revision 0
+	return feltValue(value)
+}
+
+// encodeEnum encodes the variant of an enum, an object with the name of the variant as key and the
+// array of its parameters as value: the hash of the index of the variant and the encoding of its parameters.
+func (td TypedData) encodeEnum(types map[string]TypeDef, value interface{}, def Definition) (*felt.Felt, error) {
+	variant, ok := value.(map[string]interface{})
+	if !ok || len(variant) != 1 {
+		return nil, fmt.Errorf("expected an object with one variant of the enum %s, got %v", def.Contains, value)
+	}
+	for name, data := range variant {
+		for index, variantDef := range types[def.Contains].Definitions {
+			if variantDef.Name != name {
+				continue
+			}
+			paramTypes, ok := tupleElements(variantDef.Type)
+			if !ok {
+				return nil, fmt.Errorf("invalid variant %s of the enum %s: %s", name, def.Contains, variantDef.Type)
+			}
+			if len(paramTypes) == 1 && paramTypes[0] == "" {
+				paramTypes = nil
+			}
+			params, ok := data.([]interface{})
+			if !ok && data != nil {
+				return nil, fmt.Errorf("expected the array of the parameters of the variant %s, got %T", name, data)
+			}
+			if len(params) != len(paramTypes) {
+				return nil, fmt.Errorf("expected %d parameters for the variant %s, got %d", len(paramTypes), name, len(params))
+			}
+			elements := []*felt.Felt{new(felt.Felt).SetUint64(uint64(index))}
+			for i, paramType := range paramTypes {
+				element, err := td.encodeValue(types, paramType, params[i], Definition{})
+				if err != nil {
+					return nil, err
+				}
+				elements = append(elements, element)
+			}
+			return td.hashElements(elements), nil
+		}
+		return nil, fmt.Errorf("unknown variant %s of the enum %s", name, def.Contains)
+	}
+	return nil, nil
+}
+
+// hashElements hashes an array of felts, with Pedersen for the revision 0 and Poseidon for the revision 1.
+func (td TypedData) hashElements(elements []*felt.Felt) *felt.Felt {
+	if td.Revision == Revision1 {
+		return crypto.PoseidonArray(elements...)
+	}
+	return crypto.PedersenArray(elements...)
+}
+
+// merkleRoot computes the root of the merkle tree of some leaves, each node being the hash of its
+// sorted children, and the last node of a level with an odd number of nodes being hashed with 0.
+func (td TypedData) merkleRoot(leaves []*felt.Felt) (*felt.Felt, error) {
+	if len(leaves) == 0 {
+		return nil, errors.New("a merkletree requires at least one leaf")
+	}
+	hash := crypto.Pedersen
+	if td.Revision == Revision1 {
+		hash = crypto.Poseidon
+	}
+	for len(leaves) > 1 {
+		next := make([]*felt.Felt, 0, (len(leaves)+1)/2)
+		for i := 0; i < len(leaves); i += 2 {
+			a, b := leaves[i], &felt.Zero
+			if i+1 < len(leaves) {
+				b = leaves[i+1]
+			}
+			if a.Cmp(b) > 0 {
+				a, b = b, a
+			}
+			next = append(next, hash(a, b))
+		}
+		leaves = next
+	}
+	return leaves[0], nil
+}
+
+// byteArray serializes a string as a Cairo ByteArray: the number of its full 31 bytes words,
+// the words, the pending word and the length of the pending word.
+func byteArray(str string) []*felt.Felt {
+	data := []byte(str)
+	words := len(data) / 31
+	elements := []*felt.Felt{new(felt.Felt).SetUint64(uint64(words))}
+	for i := 0; i < words; i++ {
+		elements = append(elements, new(felt.Felt).SetBytes(data[i*31:(i+1)*31]))
+	}
+	pending := data[words*31:]
+	return append(elements, new(felt.Felt).SetBytes(pending), new(felt.Felt).SetUint64(uint64(len(pending))))
+}
+
+// rangeValue converts a value to a felt, checking that it is between 0 and max.
+func rangeValue(value interface{}, inType string, max *big.Int) (*felt.Felt, error) {
+	n, err := bigValue(value)
+	if err != nil {
+		return nil, err
+	}
+	if n.Sign() < 0 || n.Cmp(max) > 0 {
+		return nil, fmt.Errorf("%s is out of the range of %s", n, inType)
+	}
+	return utils.BigIntToFelt(n), nil
+}
+
+// feltValue converts a value to a felt, checking that it is in the range of the felts.
+func feltValue(value interface{}) (*felt.Felt, error) {
+	n, err := bigValue(value)
+	if err != nil {
+		return nil, err
+	}
+	if n.Sign() < 0 || n.Cmp(curve.Curve.P) >= 0 {
+		return nil, fmt.Errorf("%s is out of the range of felt", n)
+	}
+	return utils.BigIntToFelt(n), nil
+}
+
+// bigValue converts a value to an integer: a string is a decimal or a 0x, 0o or 0b prefixed number,
+// or else a short string of up to 31 ASCII characters.
+func bigValue(value interface{}) (*big.Int, error) {
+	switch v := value.(type) {
+	case string:
+		if n, ok := parseInt(v); ok {
+			return n, nil
+		}
+		if len(v) > 31 {
+			return nil, fmt.Errorf("the short string %q is longer than 31 characters", v)
+		}
+		for _, c := range v {
+			if c > unicodeMaxASCII {
+				return nil, fmt.Errorf("the short string %q has non-ASCII characters", v)
+			}
+		}
+		return new(big.Int).SetBytes([]byte(v)), nil
+	case json.Number:
+		if n, ok := parseInt(v.String()); ok {
+			return n, nil
+		}
+		return nil, fmt.Errorf("invalid integer %s", v)
+	case bool:
+		if v {
+			return big.NewInt(1), nil
+		}
+		return big.NewInt(0), nil
+	case int:
+		return big.NewInt(int64(v)), nil
+	case int64:
+		return big.NewInt(v), nil
+	case uint64:
+		return new(big.Int).SetUint64(v), nil
+	case float64:
+		if v != math.Trunc(v) || math.Abs(v) > 1<<53 {
+			return nil, fmt.Errorf("invalid integer %v", v)
+		}
+		return big.NewInt(int64(v)), nil
+	case *big.Int:
+		return new(big.Int).Set(v), nil
+	case *felt.Felt:
+		return utils.FeltToBigInt(v), nil
+	}
+	return nil, fmt.Errorf("invalid value %v of type %T", value, value)
+}
+
+// unicodeMaxASCII is the largest ASCII character.
+const unicodeMaxASCII = '\u007f'
+
+// parseInt parses an integer like the BigInt function of JavaScript: a decimal number, with an
+// optional sign, or a 0x, 0o or 0b prefixed number. The empty string is 0.
+func parseInt(s string) (*big.Int, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return new(big.Int), true
+	}
+	base := 10
+	if len(s) > 2 && s[0] == '0' {
+		switch s[1] {
+		case 'x', 'X':
+			base = 16
+		case 'o', 'O':
+			base = 8
+		case 'b', 'B':
+			base = 2
+		}
+		if base != 10 {
+			s = s[2:]
+			if s[0] == '+' || s[0] == '-' {
+				return nil, false
+			}
+		}
+	}
+	return new(big.Int).SetString(s, base)
+}
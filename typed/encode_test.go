@@ -0,0 +1,259 @@
+package typed
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// typedDataFromFile parses the JSON typed data of a file of the tests directory.
+func typedDataFromFile(t *testing.T, name string) TypedData {
+	data, err := os.ReadFile("tests/" + name)
+	require.NoError(t, err)
+	td, err := NewTypedDataFromJSON(data)
+	require.NoError(t, err)
+	return td
+}
+
+// TestMessageHash tests the type and message hashes of JSON typed data of both revisions against the
+// test vectors of starknet.js, and pins the hashes of the fixtures without such vectors.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging test output
+// Returns:
+//
+//	none
+func TestMessageHash(t *testing.T) {
+	type testSetType struct {
+		File                string
+		ExpectedRevision    Revision
+		ExpectedTypeHash    string
+		ExpectedMessageHash string
+	}
+	testSet := []testSetType{
+		{
+			File:                "baseExample.json",
+			ExpectedRevision:    Revision0,
+			ExpectedTypeHash:    "0x13d89452df9512bf750f539ba3001b945576243288137ddb6c788457d4b2f79",
+			ExpectedMessageHash: "0x6fcff244f63e38b9d88b9e3378d44757710d1b244282b435cb472053c8d78d0",
+		},
+		{
+			File:                "mail_StructArray.json",
+			ExpectedRevision:    Revision0,
+			ExpectedTypeHash:    "0x873b878e35e258fc99e3085d5aaad3a81a0c821f189c08b30def2cde55ff27",
+			ExpectedMessageHash: "0x5914ed2764eca2e6a41eb037feefd3d2e33d9af6225a9e7fe31ac943ff712c",
+		},
+		{
+			File:                "example_presetTypes.json",
+			ExpectedRevision:    Revision1,
+			ExpectedTypeHash:    "0x1a25a8bb84b761090b1fadaebe762c4b679b0d8883d2bedda695ea340839a55",
+			ExpectedMessageHash: "0x185b339d5c566a883561a88fb36da301051e2c0225deb325c91bb7aa2f3473a",
+		},
+		{
+			File:                "example_enum.json",
+			ExpectedRevision:    Revision1,
+			ExpectedTypeHash:    "0x380a54d417fb58913b904675d94a8a62e2abc3467f4b5439de0fd65fafdd1a8",
+			ExpectedMessageHash: "0x3df10475ad5a8f49db4345a04a5b09164d2e24b09f6e1e236bc1ccd87627cc",
+		},
+		// the hashes of the next fixtures are regression values computed by this package, not
+		// snapshots of starknet.js: their basic types and merkletree are tested by TestEncodeValue
+		{
+			File:                "example_baseTypes.json",
+			ExpectedRevision:    Revision1,
+			ExpectedTypeHash:    "0x1f94cd0be8b4097a41486170fdf09a4cd23aefbc74bb2344718562994c2c111",
+			ExpectedMessageHash: "0x727bab303f332af4ff58226726bdd5cd052470112e86402d9d786560a225dc",
+		},
+		{
+			File:                "session_MerkleTree.json",
+			ExpectedRevision:    Revision0,
+			ExpectedTypeHash:    "0x1aa0e1c56b45cf06a54534fa1707c54e520b842feb21d03b7deddb6f1e340c",
+			ExpectedMessageHash: "0x5d28fa1b31f92e63022f7d85271606e52bed89c046c925f16b09e644dc99794",
+		},
+	}
+	account := utils.TestHexToFelt(t, "0xcd2a3d9f938e13cd947ec05abc7fe734df8dd826")
+	for _, test := range testSet {
+		td := typedDataFromFile(t, test.File)
+		require.Equal(t, test.ExpectedRevision, td.Revision, test.File)
+		require.Equal(t, test.ExpectedTypeHash, utils.BigToHex(td.Types[td.PrimaryType].Encoding), test.File)
+
+		hash, err := td.MessageHash(account)
+		require.NoError(t, err, test.File)
+		require.Equal(t, test.ExpectedMessageHash, hash.String(), test.File)
+
+		// the typed data is the same once encoded back to JSON
+		data, err := json.Marshal(td)
+		require.NoError(t, err)
+		again, err := NewTypedDataFromJSON(data)
+		require.NoError(t, err)
+		hash, err = again.MessageHash(account)
+		require.NoError(t, err)
+		require.Equal(t, test.ExpectedMessageHash, hash.String(), test.File)
+	}
+}
+
+// TestEncodeType tests the encoding of the types of the revision 1, with quoted names, preset types and enums.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging test output
+// Returns:
+//
+//	none
+func TestEncodeType(t *testing.T) {
+	type testSetType struct {
+		File             string
+		Type             string
+		ExpectedEncoding string
+	}
+	testSet := []testSetType{
+		{
+			File:             "example_presetTypes.json",
+			Type:             "Example",
+			ExpectedEncoding: `"Example"("n0":"TokenAmount","n1":"NftId")"NftId"("collection_address":"ContractAddress","token_id":"u256")"TokenAmount"("token_address":"ContractAddress","amount":"u256")"u256"("low":"u128","high":"u128")`,
+		},
+		{
+			File:             "example_enum.json",
+			Type:             "Example",
+			ExpectedEncoding: `"Example"("someEnum":"MyEnum")"MyEnum"("Variant 1":(),"Variant 2":("u128","u128*"),"Variant 3":("u128"))`,
+		},
+		{
+			File:             "session_MerkleTree.json",
+			Type:             "Session",
+			ExpectedEncoding: "Session(key:felt,expires:felt,root:merkletree)",
+		},
+		{
+			File:             "example_baseTypes.json",
+			Type:             "StarknetDomain",
+			ExpectedEncoding: `"StarknetDomain"("name":"shortstring","version":"shortstring","chainId":"shortstring","revision":"shortstring")`,
+		},
+	}
+	for _, test := range testSet {
+		td := typedDataFromFile(t, test.File)
+		enc, err := td.EncodeType(test.Type)
+		require.NoError(t, err)
+		require.Equal(t, test.ExpectedEncoding, enc)
+	}
+
+	td := typedDataFromFile(t, "example_baseTypes.json")
+	require.Equal(t, "0x1f94cd0be8b4097a41486170fdf09a4cd23aefbc74bb2344718562994c2c111", utils.BigToHex(td.Types["Example"].Encoding))
+	require.Equal(t, "0x1ff2f602e42168014d405a94f75e8a93d640751d71d16311266e140d8b0a210", utils.BigToHex(td.Types["StarknetDomain"].Encoding))
+}
+
+// TestEncodeValue tests the encoding of the basic types of the revision 1 and the merkletrees.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging test output
+// Returns:
+//
+//	none
+func TestEncodeValue(t *testing.T) {
+	td := typedDataFromFile(t, "example_baseTypes.json")
+	types := td.allTypes()
+	encode := func(inType string, value interface{}) (*felt.Felt, error) {
+		return td.encodeValue(types, inType, value, Definition{})
+	}
+
+	value, err := encode("i128", "-170141183460469231731687303715884105727")
+	require.NoError(t, err)
+	expected := new(big.Int).Sub(curve.Curve.P, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1)))
+	require.Equal(t, utils.BigIntToFelt(expected), value)
+
+	value, err = encode("selector", "transfer")
+	require.NoError(t, err)
+	require.Equal(t, utils.GetSelectorFromNameFelt("transfer"), value)
+	value, err = encode("selector", "0x1234")
+	require.NoError(t, err)
+	require.Equal(t, new(felt.Felt).SetUint64(0x1234), value)
+
+	value, err = encode("shortstring", "transfer")
+	require.NoError(t, err)
+	require.Equal(t, new(felt.Felt).SetBytes([]byte("transfer")), value)
+	value, err = encode("timestamp", json.Number("1000"))
+	require.NoError(t, err)
+	require.Equal(t, new(felt.Felt).SetUint64(1000), value)
+
+	// a string is hashed as a ByteArray: its full words, the pending word and the length of the pending word
+	str := "Example long string, more than 31 characters."
+	value, err = encode("string", str)
+	require.NoError(t, err)
+	require.Equal(t, crypto.PoseidonArray(
+		new(felt.Felt).SetUint64(1),
+		new(felt.Felt).SetBytes([]byte(str[:31])),
+		new(felt.Felt).SetBytes([]byte(str[31:])),
+		new(felt.Felt).SetUint64(uint64(len(str)-31)),
+	), value)
+
+	_, err = encode("u128", "0x100000000000000000000000000000000")
+	require.Error(t, err)
+	_, err = encode("i128", "170141183460469231731687303715884105728")
+	require.Error(t, err)
+	_, err = encode("bool", 2)
+	require.Error(t, err)
+	_, err = encode("felt", "a short string of more than 31 characters")
+	require.Error(t, err)
+	_, err = encode("u64", "1")
+	require.Error(t, err)
+
+	// the leaves of a merkletree are hashed by sorted pairs, the last leaf of an odd level with 0
+	session := typedDataFromFile(t, "session_MerkleTree.json")
+	leaves := make([]*felt.Felt, 3)
+	for i, leaf := range session.MessageData["root"].([]interface{}) {
+		leaves[i], err = session.StructHash("Policy", leaf.(map[string]interface{}))
+		require.NoError(t, err)
+	}
+	pair := func(a, b *felt.Felt) *felt.Felt {
+		if a.Cmp(b) > 0 {
+			a, b = b, a
+		}
+		return crypto.Pedersen(a, b)
+	}
+	root, err := session.merkleRoot(leaves)
+	require.NoError(t, err)
+	require.Equal(t, pair(pair(leaves[0], leaves[1]), pair(leaves[2], &felt.Zero)), root)
+	sessionHash, err := session.StructHash("Session", session.MessageData)
+	require.NoError(t, err)
+	require.Equal(t, crypto.PedersenArray(utils.BigIntToFelt(session.Types["Session"].Encoding), &felt.Zero, &felt.Zero, root), sessionHash)
+	_, err = session.merkleRoot(nil)
+	require.Error(t, err)
+}
+
+// TestNewTypedDataFromJSONErrors tests that NewTypedDataFromJSON rejects the inconsistent typed data,
+// and that MessageHash rejects the messages which do not match their types.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging test output
+// Returns:
+//
+//	none
+func TestNewTypedDataFromJSONErrors(t *testing.T) {
+	domain := `"StarknetDomain":[{"name":"name","type":"shortstring"},{"name":"version","type":"shortstring"},{"name":"chainId","type":"shortstring"},{"name":"revision","type":"shortstring"}]`
+	for _, data := range []string{
+		`{"types":{` + domain + `,"Example":[{"name":"n0","type":"u64"}]},"primaryType":"Example","domain":{"name":"a","version":"1","chainId":"1","revision":"1"},"message":{}}`,
+		`{"types":{` + domain + `,"Example":[{"name":"n0","type":"felt"}]},"primaryType":"Other","domain":{"name":"a","version":"1","chainId":"1","revision":"1"},"message":{}}`,
+		`{"types":{` + domain + `,"Example":[{"name":"n0","type":"felt"}]},"primaryType":"Example","domain":{"name":"a","version":"1","chainId":"1","revision":"2"},"message":{}}`,
+		`{"types":{"Example":[{"name":"n0","type":"felt"}]},"primaryType":"Example","domain":{"name":"a","version":"1","chainId":"1","revision":"1"},"message":{}}`,
+		`{"types":{` + domain + `,"Example":[{"name":"n0","type":"enum"}]},"primaryType":"Example","domain":{"name":"a","version":"1","chainId":"1","revision":"1"},"message":{}}`,
+		`{"types":{` + domain + `,"Example":[{"name":"n0","type":"merkletree","contains":"felt*"}]},"primaryType":"Example","domain":{"name":"a","version":"1","chainId":"1","revision":"1"},"message":{}}`,
+	} {
+		_, err := NewTypedDataFromJSON([]byte(data))
+		require.Error(t, err, data)
+	}
+
+	account := utils.TestHexToFelt(t, "0x123")
+	td := typedDataFromFile(t, "example_enum.json")
+	td.MessageData = map[string]interface{}{"someEnum": map[string]interface{}{"Variant 4": []interface{}{}}}
+	_, err := td.MessageHash(account)
+	require.Error(t, err)
+	td.MessageData = map[string]interface{}{"someEnum": map[string]interface{}{"Variant 3": []interface{}{}}}
+	_, err = td.MessageHash(account)
+	require.Error(t, err)
+	td.MessageData = map[string]interface{}{}
+	_, err = td.MessageHash(account)
+	require.Error(t, err)
+}
@@ -0,0 +1,128 @@
+package typed
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// NewTypedDataFromJSON parses typed data in the JSON format of the wallets and starknet.js, of the revision 0 or 1.
+//
+// Parameters:
+// - data: the JSON typed data, with its types, primaryType, domain and message
+// Returns:
+// - TypedData: the typed data, with its message in MessageData
+// - error: an error if the JSON is invalid or its types are not consistent
+func NewTypedDataFromJSON(data []byte) (TypedData, error) {
+	var td TypedData
+	if err := json.Unmarshal(data, &td); err != nil {
+		return TypedData{}, err
+	}
+	return td, nil
+}
+
+// UnmarshalJSON parses JSON typed data, see NewTypedDataFromJSON. The numbers of the message
+// are kept as json.Number.
+//
+// Parameters:
+// - data: the JSON typed data
+// Returns:
+// - error: an error if any
+func (td *TypedData) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Types       map[string]TypeDef `json:"types"`
+		PrimaryType string             `json:"primaryType"`
+		Domain      Domain             `json:"domain"`
+		Message     json.RawMessage    `json:"message"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var message map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw.Message))
+	decoder.UseNumber()
+	if err := decoder.Decode(&message); err != nil {
+		return fmt.Errorf("invalid message: %w", err)
+	}
+
+	parsed, err := NewTypedData(raw.Types, raw.PrimaryType, raw.Domain)
+	if err != nil {
+		return err
+	}
+	if _, ok := parsed.Types[parsed.DomainType()]; !ok {
+		return fmt.Errorf("missing domain type %s", parsed.DomainType())
+	}
+	parsed.MessageData = message
+	*td = parsed
+	return nil
+}
+
+// MarshalJSON encodes the typed data in the JSON format of the wallets, with MessageData as message.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []byte: the JSON typed data
+// - error: an error if any
+func (td TypedData) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Types       map[string]TypeDef     `json:"types"`
+		PrimaryType string                 `json:"primaryType"`
+		Domain      Domain                 `json:"domain"`
+		Message     map[string]interface{} `json:"message"`
+	}{td.Types, td.PrimaryType, td.Domain, td.MessageData})
+}
+
+// UnmarshalJSON parses the JSON array of the definitions of a type.
+//
+// Parameters:
+// - data: the JSON definitions
+// Returns:
+// - error: an error if any
+func (typeDef *TypeDef) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &typeDef.Definitions)
+}
+
+// MarshalJSON encodes the definitions of a type as a JSON array.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []byte: the JSON definitions
+// - error: an error if any
+func (typeDef TypeDef) MarshalJSON() ([]byte, error) {
+	return json.Marshal(typeDef.Definitions)
+}
+
+// UnmarshalJSON parses a JSON domain, whose fields are strings or numbers, such as the chainId 1.
+//
+// Parameters:
+// - data: the JSON domain
+// Returns:
+// - error: an error if any
+func (dm *Domain) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	fields := map[string]*string{"name": &dm.Name, "version": &dm.Version, "chainId": &dm.ChainId, "revision": &dm.Revision}
+	for name, field := range fields {
+		value, ok := raw[name]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(value, field); err != nil {
+			var number json.Number
+			if err := json.Unmarshal(value, &number); err != nil {
+				return fmt.Errorf("invalid domain %s: %s", name, value)
+			}
+			*field = number.String()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,438 @@
+package typed
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// structTag is the key of the struct tags describing the fields of a message, such as
+// `starknet:"name,type=felt"` or `starknet:"root,type=merkletree,contains=Policy"`.
+const structTag = "starknet"
+
+var (
+	feltType   = reflect.TypeOf(felt.Felt{})
+	bigIntType = reflect.TypeOf(big.Int{})
+)
+
+// fieldTag is the parsed struct tag of a field of a message.
+type fieldTag struct {
+	name     string
+	typ      string
+	contains string
+	skip     bool
+}
+
+// TypesFromStruct derives the types of a message from its Go struct: a type named after the struct,
+// with a definition per exported field, and the types of its nested structs. The fields are described
+// by their starknet struct tag, `starknet:"name,type=felt,contains=Policy"`, with "-" to skip a field.
+// Without name, the name of the field is used. Without type, the type is inferred from the Go type:
+//   - felt.Felt, big.Int, the integers and the strings are felt
+//   - bool is bool
+//   - a struct is the type named after the struct, whose types are added
+//   - a slice or an array is the type of its elements followed by *
+//
+// The pointers are dereferenced. The type of a field can be any type of the revision of the typed data,
+// such as shortstring, u128, string, u256 or merkletree, whose leaves are given by contains. A u256 field
+// is either a struct with low and high fields or an integer, such as a big.Int, split into its low and
+// high 128 bits. A TokenAmount or NftId field is a struct with the fields of the preset type.
+//
+// Parameters:
+// - message: the message, a struct or a pointer to a struct
+// Returns:
+// - map[string]TypeDef: the types of the struct and of its nested structs, without the domain type
+// - string: the primary type, the name of the struct
+// - error: an error if the struct is anonymous or has fields of unsupported types
+func TypesFromStruct(message interface{}) (map[string]TypeDef, string, error) {
+	t := reflect.TypeOf(message)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, "", fmt.Errorf("expected a struct, got %T", message)
+	}
+	types := make(map[string]TypeDef)
+	if err := addStructType(t, types, make(map[string]reflect.Type)); err != nil {
+		return nil, "", err
+	}
+	return types, t.Name(), nil
+}
+
+// NewTypedDataFromStruct creates the typed data of a message given as a Go struct, whose types are
+// derived by TypesFromStruct and whose MessageData is the value of the struct. The domain type is
+// StarknetDomain for a domain with the revision 1, and StarkNetDomain otherwise.
+//
+// Parameters:
+// - domain: the domain of the typed data
+// - message: the message, a struct or a pointer to a struct
+// Returns:
+// - TypedData: the typed data
+// - error: an error if the struct cannot be encoded
+func NewTypedDataFromStruct(domain Domain, message interface{}) (TypedData, error) {
+	types, primaryType, err := TypesFromStruct(message)
+	if err != nil {
+		return TypedData{}, err
+	}
+	if domain.Revision == "1" {
+		types["StarknetDomain"] = TypeDef{Definitions: []Definition{
+			{Name: "name", Type: "shortstring"}, {Name: "version", Type: "shortstring"},
+			{Name: "chainId", Type: "shortstring"}, {Name: "revision", Type: "shortstring"},
+		}}
+	} else {
+		types["StarkNetDomain"] = TypeDef{Definitions: []Definition{
+			{Name: "name", Type: "felt"}, {Name: "version", Type: "felt"}, {Name: "chainId", Type: "felt"},
+		}}
+	}
+
+	td, err := NewTypedData(types, primaryType, domain)
+	if err != nil {
+		return TypedData{}, err
+	}
+	data, err := structData(reflect.ValueOf(message), "")
+	if err != nil {
+		return TypedData{}, err
+	}
+	td.MessageData = data.(map[string]interface{})
+	return td, nil
+}
+
+// HashStruct calculates the message hash of a message given as a Go struct, signed by an account.
+// See NewTypedDataFromStruct.
+//
+// Parameters:
+// - domain: the domain of the typed data
+// - message: the message, a struct or a pointer to a struct
+// - accountAddress: the address of the account signing the message
+// Returns:
+// - *felt.Felt: the message hash
+// - error: an error if the struct cannot be encoded
+func HashStruct(domain Domain, message interface{}, accountAddress *felt.Felt) (*felt.Felt, error) {
+	td, err := NewTypedDataFromStruct(domain, message)
+	if err != nil {
+		return nil, err
+	}
+	return td.MessageHash(accountAddress)
+}
+
+// structMessage is a TypedMessage encoding the fields of a Go struct.
+type structMessage struct {
+	types       map[string]TypeDef
+	primaryType string
+	data        map[string]interface{}
+}
+
+// NewStructMessage creates the TypedMessage of a message given as a Go struct, described by the
+// struct tags of TypesFromStruct, to hash it with GetMessageHash instead of implementing
+// FmtDefinitionEncoding. The fields of the nested structs are encoded in the order of their definitions.
+// GetMessageHash only encodes felt fields and nested structs of felt fields, so the structs with other
+// fields, such as slices, arrays or structs nested twice, are rejected: hash them with HashStruct instead.
+//
+// Parameters:
+// - message: the message, a struct or a pointer to a struct
+// Returns:
+// - TypedMessage: the typed message
+// - error: an error if the struct cannot be encoded
+func NewStructMessage(message interface{}) (TypedMessage, error) {
+	types, primaryType, err := TypesFromStruct(message)
+	if err != nil {
+		return nil, err
+	}
+	data, err := structData(reflect.ValueOf(message), "")
+	if err != nil {
+		return nil, err
+	}
+	msg := structMessage{types: types, primaryType: primaryType, data: data.(map[string]interface{})}
+	for _, def := range types[primaryType].Definitions {
+		if err := checkLegacyField(types, def); err != nil {
+			return nil, fmt.Errorf("invalid field %s: %w, use HashStruct", def.Name, err)
+		}
+		if _, err := msg.encode(def.Type, msg.data[def.Name]); err != nil {
+			return nil, fmt.Errorf("invalid field %s: %w", def.Name, err)
+		}
+	}
+	return msg, nil
+}
+
+// checkLegacyField checks that a field of the primary type is encoded by GetMessageHash: a felt, or a
+// nested struct whose fields are all felts.
+func checkLegacyField(types map[string]TypeDef, def Definition) error {
+	if def.Type == "felt" {
+		return nil
+	}
+	typeDef, ok := types[def.Type]
+	if !ok {
+		return fmt.Errorf("the type %s is not supported by GetMessageHash", def.Type)
+	}
+	for _, nested := range typeDef.Definitions {
+		if nested.Type != "felt" {
+			return fmt.Errorf("the field %s of the nested type %s is not a felt", nested.Name, def.Type)
+		}
+	}
+	return nil
+}
+
+// FmtDefinitionEncoding formats the encoding of a field of the struct: its value, or the values of
+// the fields of a nested struct.
+//
+// Parameters:
+// - field: the name of the field to format the encoding for
+// Returns:
+// - fmtEnc: a slice of big integers
+func (msg structMessage) FmtDefinitionEncoding(field string) (fmtEnc []*big.Int) {
+	for _, def := range msg.types[msg.primaryType].Definitions {
+		if def.Name == field {
+			// the fields are checked by NewStructMessage
+			fmtEnc, _ = msg.encode(def.Type, msg.data[field])
+		}
+	}
+	return fmtEnc
+}
+
+// encode flattens a value of a type into integers.
+func (msg structMessage) encode(inType string, value interface{}) ([]*big.Int, error) {
+	if typeDef, ok := msg.types[inType]; ok {
+		data, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected an object of type %s, got %T", inType, value)
+		}
+		var enc []*big.Int
+		for _, def := range typeDef.Definitions {
+			fieldEnc, err := msg.encode(def.Type, data[def.Name])
+			if err != nil {
+				return nil, err
+			}
+			enc = append(enc, fieldEnc...)
+		}
+		return enc, nil
+	}
+	n, err := feltValue(value)
+	if err != nil {
+		return nil, err
+	}
+	return []*big.Int{utils.FeltToBigInt(n)}, nil
+}
+
+// addStructType adds the type of a struct and the types of its nested structs, seen holding the
+// Go types of the names already added.
+func addStructType(t reflect.Type, types map[string]TypeDef, seen map[string]reflect.Type) error {
+	name := t.Name()
+	if name == "" {
+		return fmt.Errorf("the anonymous struct %s has no type name", t)
+	}
+	if previous, ok := seen[name]; ok {
+		if previous != t {
+			return fmt.Errorf("the structs %s and %s have the same type name", previous, t)
+		}
+		return nil
+	}
+	seen[name] = t
+
+	definitions := []Definition{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, err := parseFieldTag(field)
+		if err != nil {
+			return err
+		}
+		if tag.skip {
+			continue
+		}
+		inferred, nested, err := fieldType(field.Type)
+		inType := tag.typ
+		if inType == "" {
+			if err != nil {
+				return fmt.Errorf("field %s of %s: %w", field.Name, name, err)
+			}
+			inType = inferred
+		}
+		if err := checkPresetField(inType, field.Type); err != nil {
+			return fmt.Errorf("field %s of %s: %w", field.Name, name, err)
+		}
+		// the nested struct is a type of the message when it is referenced by the field, and not a preset type
+		if _, preset := presetTypes[strings.TrimRight(inType, "*")]; nested != nil && !preset &&
+			(strings.TrimRight(inType, "*") == nested.Name() || tag.contains == nested.Name()) {
+			if err := addStructType(nested, types, seen); err != nil {
+				return err
+			}
+		}
+		definitions = append(definitions, Definition{Name: tag.name, Type: inType, Contains: tag.contains})
+	}
+	types[name] = TypeDef{Definitions: definitions}
+	return nil
+}
+
+// fieldType infers the type of a field from its Go type, along with the nested struct of the field, if any.
+func fieldType(t reflect.Type) (string, reflect.Type, error) {
+	switch t {
+	case feltType, bigIntType:
+		return "felt", nil, nil
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return fieldType(t.Elem())
+	case reflect.Struct:
+		return t.Name(), t, nil
+	case reflect.Slice, reflect.Array:
+		elementType, nested, err := fieldType(t.Elem())
+		return elementType + "*", nested, err
+	case reflect.Bool:
+		return "bool", nil, nil
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "felt", nil, nil
+	}
+	return "", nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// checkPresetField checks that the Go type of a field of a preset struct type can hold its data: a struct,
+// or an integer for u256.
+func checkPresetField(inType string, t reflect.Type) error {
+	presetType := strings.TrimRight(inType, "*")
+	if _, ok := presetTypes[presetType]; !ok {
+		return nil
+	}
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct && t != feltType && t != bigIntType {
+		return nil
+	}
+	if presetType == "u256" && isInteger(t) {
+		return nil
+	}
+	return fmt.Errorf("the type %s cannot hold a value of the type %s", t, presetType)
+}
+
+// isInteger tells whether a Go type is an integer: felt.Felt, big.Int or an integer kind.
+func isInteger(t reflect.Type) bool {
+	switch t {
+	case feltType, bigIntType:
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// parseFieldTag parses the starknet struct tag of a field. The unexported fields are skipped.
+func parseFieldTag(field reflect.StructField) (fieldTag, error) {
+	tag := fieldTag{name: field.Name, skip: !field.IsExported()}
+	value, ok := field.Tag.Lookup(structTag)
+	if !ok {
+		return tag, nil
+	}
+	if value == "-" {
+		tag.skip = true
+		return tag, nil
+	}
+	parts := strings.Split(value, ",")
+	if parts[0] != "" {
+		tag.name = parts[0]
+	}
+	for _, option := range parts[1:] {
+		key, val, _ := strings.Cut(option, "=")
+		switch key {
+		case "type":
+			tag.typ = val
+		case "contains":
+			tag.contains = val
+		default:
+			return tag, fmt.Errorf("unknown option %q in the tag of the field %s", option, field.Name)
+		}
+	}
+	return tag, nil
+}
+
+// structData converts a Go value of a type of the message to the data of the message: the structs to
+// maps, the slices and arrays to slices, the integers of type u256 to their low and high parts, and the
+// other values to the values accepted by the encoding of the basic types.
+func structData(v reflect.Value, inType string) (interface{}, error) {
+	if inType == "u256" && isInteger(v.Type()) {
+		return u256Data(v)
+	}
+	switch v.Type() {
+	case feltType:
+		f := v.Interface().(felt.Felt)
+		return &f, nil
+	case bigIntType:
+		n := v.Interface().(big.Int)
+		return new(big.Int).Set(&n), nil
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil, fmt.Errorf("nil %s", v.Type())
+		}
+		return structData(v.Elem(), inType)
+	case reflect.Struct:
+		data := make(map[string]interface{}, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			tag, err := parseFieldTag(v.Type().Field(i))
+			if err != nil {
+				return nil, err
+			}
+			if tag.skip {
+				continue
+			}
+			value, err := structData(v.Field(i), tag.typ)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", v.Type().Field(i).Name, err)
+			}
+			data[tag.name] = value
+		}
+		return data, nil
+	case reflect.Slice, reflect.Array:
+		elements := make([]interface{}, v.Len())
+		for i := range elements {
+			element, err := structData(v.Index(i), strings.TrimSuffix(inType, "*"))
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = element
+		}
+		return elements, nil
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint(), nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", v.Type())
+}
+
+// u256Data splits an integer, a felt.Felt, a big.Int or an integer kind, into the low and high 128 bits
+// of the data of a u256.
+func u256Data(v reflect.Value) (map[string]interface{}, error) {
+	n := new(big.Int)
+	switch value := v.Interface().(type) {
+	case felt.Felt:
+		n = utils.FeltToBigInt(&value)
+	case big.Int:
+		n.Set(&value)
+	default:
+		if v.CanInt() {
+			n.SetInt64(v.Int())
+		} else {
+			n.SetUint64(v.Uint())
+		}
+	}
+	if n.Sign() < 0 || n.BitLen() > 256 {
+		return nil, fmt.Errorf("the value %s does not fit in a u256", n)
+	}
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	return map[string]interface{}{
+		"low":  new(big.Int).And(n, mask),
+		"high": new(big.Int).Rsh(n, 128),
+	}, nil
+}
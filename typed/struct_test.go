@@ -0,0 +1,296 @@
+package typed_test
+
+import (
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/typed"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHashStruct tests the types and the message hashes of messages given as Go structs against the
+// hashes of the same messages given as JSON typed data, for both revisions.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging test output
+// Returns:
+//
+//	none
+func TestHashStruct(t *testing.T) {
+	type U256 struct {
+		Low  *big.Int `starknet:"low,type=u128"`
+		High *big.Int `starknet:"high,type=u128"`
+	}
+	type Person struct {
+		Name   string `starknet:"name"`
+		Wallet string `starknet:"wallet"`
+	}
+	account := utils.TestHexToFelt(t, "0xcd2a3d9f938e13cd947ec05abc7fe734df8dd826")
+	mailDomain := typed.Domain{Name: "StarkNet Mail", Version: "1", ChainId: "1"}
+	cow := Person{Name: "Cow", Wallet: "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"}
+	bob := Person{Name: "Bob", Wallet: "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"}
+
+	t.Run("nested structs", func(t *testing.T) {
+		type Mail struct {
+			From     Person `starknet:"from"`
+			To       Person `starknet:"to"`
+			Contents string `starknet:"contents,type=felt"`
+			internal string
+		}
+		mail := Mail{From: cow, To: bob, Contents: "Hello, Bob!"}
+
+		td, err := typed.NewTypedDataFromStruct(mailDomain, mail)
+		require.NoError(t, err)
+		enc, err := td.EncodeType("Mail")
+		require.NoError(t, err)
+		require.Equal(t, "Mail(from:Person,to:Person,contents:felt)Person(name:felt,wallet:felt)", enc)
+
+		hash, err := typed.HashStruct(mailDomain, &mail, account)
+		require.NoError(t, err)
+		require.Equal(t, "0x6fcff244f63e38b9d88b9e3378d44757710d1b244282b435cb472053c8d78d0", hash.String())
+
+		// the same message through the TypedMessage of the struct
+		msg, err := typed.NewStructMessage(mail)
+		require.NoError(t, err)
+		legacyHash, err := td.GetMessageHash(utils.FeltToBigInt(account), msg, curve.Curve)
+		require.NoError(t, err)
+		require.Equal(t, hash, utils.BigIntToFelt(legacyHash))
+	})
+
+	t.Run("slice of structs", func(t *testing.T) {
+		type Post struct {
+			Title   string `starknet:"title"`
+			Content string `starknet:"content"`
+		}
+		type Mail struct {
+			From     Person `starknet:"from"`
+			To       Person `starknet:"to"`
+			PostsLen uint8  `starknet:"posts_len"`
+			Posts    []Post `starknet:"posts"`
+		}
+		mail := Mail{From: cow, To: bob, PostsLen: 2, Posts: []Post{
+			{Title: "Greeting", Content: "Hello, Bob!"},
+			{Title: "Farewell", Content: "Goodbye, Bob!"},
+		}}
+		hash, err := typed.HashStruct(mailDomain, mail, account)
+		require.NoError(t, err)
+		require.Equal(t, "0x5914ed2764eca2e6a41eb037feefd3d2e33d9af6225a9e7fe31ac943ff712c", hash.String())
+	})
+
+	t.Run("preset types", func(t *testing.T) {
+		type TokenAmount struct {
+			TokenAddress *felt.Felt `starknet:"token_address,type=ContractAddress"`
+			Amount       U256       `starknet:"amount,type=u256"`
+		}
+		type NftId struct {
+			CollectionAddress felt.Felt `starknet:"collection_address,type=ContractAddress"`
+			TokenID           U256      `starknet:"token_id,type=u256"`
+		}
+		type Example struct {
+			N0 TokenAmount `starknet:"n0"`
+			N1 *NftId      `starknet:"n1"`
+		}
+		token := utils.TestHexToFelt(t, "0x049d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7")
+		amount := U256{Low: big.NewInt(1000), High: big.NewInt(0)}
+		example := Example{
+			N0: TokenAmount{TokenAddress: token, Amount: amount},
+			N1: &NftId{CollectionAddress: *token, TokenID: amount},
+		}
+
+		types, primaryType, err := typed.TypesFromStruct(example)
+		require.NoError(t, err)
+		require.Equal(t, "Example", primaryType)
+		require.Len(t, types, 1)
+
+		domain := typed.Domain{Name: "StarkNet Mail", Version: "1", ChainId: "1", Revision: "1"}
+		hash, err := typed.HashStruct(domain, example, account)
+		require.NoError(t, err)
+		require.Equal(t, "0x185b339d5c566a883561a88fb36da301051e2c0225deb325c91bb7aa2f3473a", hash.String())
+
+		// the same message with the u256 given as integers
+		integerHash := func() *felt.Felt {
+			type TokenAmount struct {
+				TokenAddress *felt.Felt `starknet:"token_address,type=ContractAddress"`
+				Amount       *big.Int   `starknet:"amount,type=u256"`
+			}
+			type NftId struct {
+				CollectionAddress felt.Felt `starknet:"collection_address,type=ContractAddress"`
+				TokenID           uint64    `starknet:"token_id,type=u256"`
+			}
+			type Example struct {
+				N0 TokenAmount `starknet:"n0"`
+				N1 *NftId      `starknet:"n1"`
+			}
+			example := Example{
+				N0: TokenAmount{TokenAddress: token, Amount: big.NewInt(1000)},
+				N1: &NftId{CollectionAddress: *token, TokenID: 1000},
+			}
+			types, _, err := typed.TypesFromStruct(example)
+			require.NoError(t, err)
+			require.Len(t, types, 1)
+			hash, err := typed.HashStruct(domain, example, account)
+			require.NoError(t, err)
+			return hash
+		}()
+		require.Equal(t, hash, integerHash)
+	})
+
+	t.Run("u256", func(t *testing.T) {
+		domain := typed.Domain{Name: "StarkNet Mail", Version: "1", ChainId: "1", Revision: "1"}
+		// 2^128 + 5, whose high part is 1
+		amount := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(5))
+
+		type Transfer struct {
+			Amount  *big.Int    `starknet:"amount,type=u256"`
+			Amounts []felt.Felt `starknet:"amounts,type=u256*"`
+		}
+		transfer := Transfer{Amount: amount, Amounts: []felt.Felt{*utils.BigIntToFelt(amount), *new(felt.Felt).SetUint64(7)}}
+		td, err := typed.NewTypedDataFromStruct(domain, transfer)
+		require.NoError(t, err)
+		enc, err := td.EncodeType("Transfer")
+		require.NoError(t, err)
+		require.Equal(t, `"Transfer"("amount":"u256","amounts":"u256*")"u256"("low":"u128","high":"u128")`, enc)
+		hash, err := td.MessageHash(account)
+		require.NoError(t, err)
+
+		// the same message with the low and high parts given
+		splitHash := func() *felt.Felt {
+			type Transfer struct {
+				Amount  U256   `starknet:"amount,type=u256"`
+				Amounts []U256 `starknet:"amounts,type=u256*"`
+			}
+			transfer := Transfer{
+				Amount: U256{Low: big.NewInt(5), High: big.NewInt(1)},
+				Amounts: []U256{
+					{Low: big.NewInt(5), High: big.NewInt(1)},
+					{Low: big.NewInt(7), High: big.NewInt(0)},
+				},
+			}
+			hash, err := typed.HashStruct(domain, transfer, account)
+			require.NoError(t, err)
+			return hash
+		}()
+		require.Equal(t, splitHash, hash)
+	})
+
+	t.Run("merkletree", func(t *testing.T) {
+		type Policy struct {
+			ContractAddress uint64 `starknet:"contractAddress"`
+			Selector        string `starknet:"selector,type=selector"`
+		}
+		type Session struct {
+			Key     *felt.Felt `starknet:"key"`
+			Expires int        `starknet:"expires"`
+			Root    []Policy   `starknet:"root,type=merkletree,contains=Policy"`
+			Cache   []byte     `starknet:"-"`
+		}
+		session := Session{Key: new(felt.Felt), Root: []Policy{
+			{ContractAddress: 1, Selector: "transfer"},
+			{ContractAddress: 2, Selector: "transfer"},
+			{ContractAddress: 3, Selector: "transfer"},
+		}}
+		hash, err := typed.HashStruct(mailDomain, session, account)
+		require.NoError(t, err)
+
+		data, err := os.ReadFile("tests/session_MerkleTree.json")
+		require.NoError(t, err)
+		td, err := typed.NewTypedDataFromJSON(data)
+		require.NoError(t, err)
+		expected, err := td.MessageHash(account)
+		require.NoError(t, err)
+		require.Equal(t, expected, hash)
+	})
+}
+
+// TestHashStructErrors tests that the structs which cannot be encoded are rejected.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging test output
+// Returns:
+//
+//	none
+func TestHashStructErrors(t *testing.T) {
+	type Unsupported struct {
+		Values map[string]string
+	}
+	type UnknownOption struct {
+		Value string `starknet:"value,size=1"`
+	}
+	type NilPointer struct {
+		Value *felt.Felt
+	}
+	type OutOfRange struct {
+		Value *big.Int `starknet:"value,type=u128"`
+	}
+	type NegativeU256 struct {
+		Value int64 `starknet:"value,type=u256"`
+	}
+	type StringU256 struct {
+		Value string `starknet:"value,type=u256"`
+	}
+	type IntegerTokenAmount struct {
+		Value *big.Int `starknet:"value,type=TokenAmount"`
+	}
+	domain := typed.Domain{Name: "StarkNet Mail", Version: "1", ChainId: "1", Revision: "1"}
+	account := utils.TestHexToFelt(t, "0x123")
+
+	for _, message := range []interface{}{
+		"not a struct",
+		struct{ Value string }{"anonymous"},
+		Unsupported{},
+		UnknownOption{},
+		NilPointer{},
+		OutOfRange{Value: new(big.Int).Lsh(big.NewInt(1), 128)},
+		NegativeU256{Value: -1},
+		StringU256{Value: "1000"},
+		IntegerTokenAmount{Value: big.NewInt(1000)},
+	} {
+		_, err := typed.HashStruct(domain, message, account)
+		require.Error(t, err, message)
+	}
+}
+
+// TestNewStructMessageErrors tests that NewStructMessage rejects the fields which GetMessageHash cannot
+// encode: slices, arrays and structs nested twice.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging test output
+// Returns:
+//
+//	none
+func TestNewStructMessageErrors(t *testing.T) {
+	type Post struct {
+		Title string `starknet:"title"`
+	}
+	type Thread struct {
+		Post Post `starknet:"post"`
+	}
+	type WithSlice struct {
+		Posts []Post `starknet:"posts"`
+	}
+	type WithArray struct {
+		Values [2]uint64 `starknet:"values"`
+	}
+	type WithNestedSlice struct {
+		Post WithSlice `starknet:"post"`
+	}
+	type TwiceNested struct {
+		Thread Thread `starknet:"thread"`
+	}
+
+	for name, message := range map[string]interface{}{
+		"slice":        WithSlice{Posts: []Post{{Title: "Greeting"}}},
+		"array":        WithArray{Values: [2]uint64{1, 2}},
+		"nested slice": WithNestedSlice{Post: WithSlice{Posts: []Post{{Title: "Greeting"}}}},
+		"twice nested": TwiceNested{Thread: Thread{Post: Post{Title: "Greeting"}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := typed.NewStructMessage(message)
+			require.ErrorContains(t, err, "HashStruct")
+		})
+	}
+}
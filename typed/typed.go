@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"math/big"
 	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/starknet.go/curve"
@@ -17,12 +19,18 @@ type TypedData struct {
 	PrimaryType string
 	Domain      Domain
 	Message     TypedMessage
+	// Revision the revision of SNIP-12 of the typed data, detected from its domain
+	Revision Revision
+	// MessageData the message of the typed data, such as the message of the JSON typed data
+	// parsed by NewTypedDataFromJSON, hashed by MessageHash
+	MessageData map[string]interface{}
 }
 
 type Domain struct {
-	Name    string
-	Version string
-	ChainId string
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	ChainId  string `json:"chainId"`
+	Revision string `json:"revision,omitempty"`
 }
 
 type TypeDef struct {
@@ -31,8 +39,10 @@ type TypeDef struct {
 }
 
 type Definition struct {
-	Name string
-	Type string
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Contains the type of the variants of an enum, or of the leaves of a merkletree
+	Contains string `json:"contains,omitempty"`
 }
 
 type TypedMessage interface {
@@ -59,6 +69,8 @@ func (dm Domain) FmtDefinitionEncoding(field string) (fmtEnc []*big.Int) {
 		processStrToBig(dm.Version)
 	case "chainId":
 		processStrToBig(dm.ChainId)
+	case "revision":
+		processStrToBig(dm.Revision)
 	}
 	return fmtEnc
 }
@@ -91,6 +103,7 @@ func strToFelt(str string) *felt.Felt {
 
 // NewTypedData initializes a new TypedData object with the given types, primary type, and domain
 // for interacting and signing in accordance with https://github.com/0xs34n/starknet.js/tree/develop/src/utils/typedData
+// The revision of the typed data is 1 when the domain has the revision 1 and the types define StarknetDomain, and 0 otherwise.
 // If the primary type is invalid, it returns an error with the message "invalid primary type: {pType}".
 // If there is an error encoding the type hash, it returns an error with the message "error encoding type hash: {enc.String()} {err}".
 //
@@ -107,6 +120,9 @@ func NewTypedData(types map[string]TypeDef, pType string, dom Domain) (td TypedD
 		PrimaryType: pType,
 		Domain:      dom,
 	}
+	if td.Revision, err = revisionOf(types, dom); err != nil {
+		return td, err
+	}
 	if _, ok := td.Types[pType]; !ok {
 		return td, fmt.Errorf("invalid primary type: %s", pType)
 	}
@@ -114,7 +130,7 @@ func NewTypedData(types map[string]TypeDef, pType string, dom Domain) (td TypedD
 	for k, v := range td.Types {
 		enc, err := td.GetTypeHash(k)
 		if err != nil {
-			return td, fmt.Errorf("error encoding type hash: %s %w", enc.String(), err)
+			return td, fmt.Errorf("error encoding type hash: %s %w", k, err)
 		}
 		v.Encoding = enc
 		td.Types[k] = v
@@ -155,12 +171,13 @@ func (td TypedData) GetMessageHash(account *big.Int, msg TypedMessage, sc curve.
 // GetTypedMessageHash calculates the hash of a typed message using the provided StarkCurve.
 //
 // Parameters:
-//  - inType: the type of the message
-//  - msg: the typed message
-//  - sc: the StarkCurve used for hashing
+//   - inType: the type of the message
+//   - msg: the typed message
+//   - sc: the StarkCurve used for hashing
+//
 // Returns:
-//  - hash: the calculated hash
-//  - err: any error if any
+//   - hash: the calculated hash
+//   - err: any error if any
 func (td TypedData) GetTypedMessageHash(inType string, msg TypedMessage, sc curve.StarkCurve) (hash *big.Int, err error) {
 	prim := td.Types[inType]
 	elements := []*big.Int{prim.Encoding}
@@ -206,7 +223,8 @@ func (td TypedData) GetTypeHash(inType string) (ret *big.Int, err error) {
 	return sel, nil
 }
 
-// EncodeType encodes the given inType using the TypedData struct.
+// EncodeType encodes the given inType using the TypedData struct: the type followed by the types it depends on,
+// sorted by name. The names are quoted in the revision 1.
 //
 // Parameters:
 // - inType: the type to encode
@@ -214,39 +232,111 @@ func (td TypedData) GetTypeHash(inType string) (ret *big.Int, err error) {
 // - enc: the encoded type
 // - err: any error if any
 func (td TypedData) EncodeType(inType string) (enc string, err error) {
-	var typeDefs TypeDef
-	var ok bool
-	if typeDefs, ok = td.Types[inType]; !ok {
+	types := td.allTypes()
+	if _, ok := types[inType]; !ok {
 		return enc, fmt.Errorf("can't parse type %s from types %v", inType, td.Types)
 	}
-	var buf bytes.Buffer
-	customTypes := make(map[string]TypeDef)
-	buf.WriteString(inType)
-	buf.WriteString("(")
-	for i, def := range typeDefs.Definitions {
-		if def.Type != "felt" {
-			var customTypeDef TypeDef
-			if customTypeDef, ok = td.Types[def.Type]; !ok {
-				return enc, fmt.Errorf("can't parse type %s from types %v", def.Type, td.Types)
-			}
-			customTypes[def.Type] = customTypeDef
-		}
-		buf.WriteString(fmt.Sprintf("%s:%s", def.Name, def.Type))
-		if i != (len(typeDefs.Definitions) - 1) {
-			buf.WriteString(",")
-		}
+	dependencies := make(map[string]bool)
+	if err := td.addDependencies(types, inType, dependencies); err != nil {
+		return enc, err
+	}
+	delete(dependencies, inType)
+	names := make([]string, 0, len(dependencies)+1)
+	for name := range dependencies {
+		names = append(names, name)
 	}
-	buf.WriteString(")")
+	sort.Strings(names)
+	names = append([]string{inType}, names...)
 
-	for customTypeName, customType := range customTypes {
-		buf.WriteString(fmt.Sprintf("%s(", customTypeName))
-		for i, def := range customType.Definitions {
-			buf.WriteString(fmt.Sprintf("%s:%s", def.Name, def.Type))
-			if i != (len(customType.Definitions) - 1) {
+	var buf bytes.Buffer
+	for _, name := range names {
+		buf.WriteString(td.escape(name))
+		buf.WriteString("(")
+		for i, def := range types[name].Definitions {
+			if i > 0 {
 				buf.WriteString(",")
 			}
+			defType := def.Type
+			if defType == "enum" && td.Revision == Revision1 {
+				defType = def.Contains
+			}
+			buf.WriteString(td.escape(def.Name))
+			buf.WriteString(":")
+			if elements, ok := tupleElements(defType); ok {
+				for i, element := range elements {
+					if element != "" {
+						elements[i] = td.escape(element)
+					}
+				}
+				buf.WriteString("(" + strings.Join(elements, ",") + ")")
+			} else {
+				buf.WriteString(td.escape(defType))
+			}
 		}
 		buf.WriteString(")")
 	}
 	return buf.String(), nil
 }
+
+// addDependencies adds a type and the types it refers to, recursively, to the dependencies.
+// It checks that the types it refers to are either defined or basic types.
+func (td TypedData) addDependencies(types map[string]TypeDef, inType string, dependencies map[string]bool) error {
+	if dependencies[inType] {
+		return nil
+	}
+	dependencies[inType] = true
+	for _, def := range types[inType].Definitions {
+		var refs []string
+		switch {
+		case def.Type == "merkletree":
+			// the type of the leaves is not part of the encoding
+			if def.Contains == "" || strings.HasSuffix(def.Contains, "*") {
+				return fmt.Errorf("invalid merkletree %s of type %s: contains must be a type, not an array", def.Name, inType)
+			}
+			if _, ok := types[def.Contains]; !ok && !td.isBasicType(def.Contains) {
+				return fmt.Errorf("can't parse type %s from types %v", def.Contains, td.Types)
+			}
+		case def.Type == "enum" && td.Revision == Revision1:
+			if def.Contains == "" {
+				return fmt.Errorf("invalid enum %s of type %s: missing contains", def.Name, inType)
+			}
+			refs = []string{def.Contains}
+		default:
+			if elements, ok := tupleElements(def.Type); ok && td.Revision == Revision1 {
+				refs = elements
+			} else {
+				refs = []string{def.Type}
+			}
+		}
+		for _, ref := range refs {
+			ref = strings.TrimSuffix(ref, "*")
+			if ref == "" {
+				continue
+			}
+			if _, ok := types[ref]; ok {
+				if err := td.addDependencies(types, ref, dependencies); err != nil {
+					return err
+				}
+			} else if !td.isBasicType(ref) {
+				return fmt.Errorf("can't parse type %s from types %v", ref, td.Types)
+			}
+		}
+	}
+	return nil
+}
+
+// escape quotes a name of the type encoding in the revision 1.
+func (td TypedData) escape(name string) string {
+	if td.Revision == Revision1 {
+		return `"` + name + `"`
+	}
+	return name
+}
+
+// tupleElements splits the types of an enum variant, such as (u128,felt*).
+func tupleElements(inType string) ([]string, bool) {
+	if !strings.HasPrefix(inType, "(") || !strings.HasSuffix(inType, ")") {
+		return nil, false
+	}
+	return strings.Split(inType[1:len(inType)-1], ","), true
+}
@@ -52,11 +52,11 @@ func (mail Mail) FmtDefinitionEncoding(field string) (fmtEnc []*big.Int) {
 // - ttd: the generated TypedData object
 func MockTypedData() (ttd TypedData) {
 	exampleTypes := make(map[string]TypeDef)
-	domDefs := []Definition{{"name", "felt"}, {"version", "felt"}, {"chainId", "felt"}}
+	domDefs := []Definition{{Name: "name", Type: "felt"}, {Name: "version", Type: "felt"}, {Name: "chainId", Type: "felt"}}
 	exampleTypes["StarkNetDomain"] = TypeDef{Definitions: domDefs}
-	mailDefs := []Definition{{"from", "Person"}, {"to", "Person"}, {"contents", "felt"}}
+	mailDefs := []Definition{{Name: "from", Type: "Person"}, {Name: "to", Type: "Person"}, {Name: "contents", Type: "felt"}}
 	exampleTypes["Mail"] = TypeDef{Definitions: mailDefs}
-	persDefs := []Definition{{"name", "felt"}, {"wallet", "felt"}}
+	persDefs := []Definition{{Name: "name", Type: "felt"}, {Name: "wallet", Type: "felt"}}
 	exampleTypes["Person"] = TypeDef{Definitions: persDefs}
 
 	dm := Domain{
@@ -0,0 +1,261 @@
+package typed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+var (
+	// ValidSignatureMagic is the 'VALID' short string returned by the is_valid_signature function
+	// of the SRC-6 accounts for a valid signature.
+	ValidSignatureMagic = new(felt.Felt).SetBytes([]byte("VALID"))
+
+	ErrSignatureNotVerifiable = errors.New("the account cannot verify the signature and its public key is unknown")
+
+	ErrPublicKeyNotOwner = errors.New("the public key does not own the undeployed account")
+
+	errNoEntryPoint = errors.New("the account has no signature verification entry point")
+
+	// isValidSignatureEntryPoints are the entry points verifying a signature, of the Cairo 1 and Cairo 0 accounts
+	isValidSignatureEntryPoints = []string{"is_valid_signature", "isValidSignature"}
+
+	// invalidSignatureReverts are the revert reasons of the accounts rejecting a signature instead of returning 0
+	invalidSignatureReverts = []string{"invalid-signature", "invalid signature", "invalid-owner-sig", "invalid-guardian-sig"}
+)
+
+type verifyOptions struct {
+	publicKey  *felt.Felt
+	deployment *accountDeployment
+	blockID    rpc.BlockID
+}
+
+// accountDeployment is the deployment of an undeployed account, from which its address is derived.
+type accountDeployment struct {
+	classHash           *felt.Felt
+	salt                *felt.Felt
+	constructorCalldata []*felt.Felt
+}
+
+// funcVerifyOption wraps a function that modifies verifyOptions into an
+// implementation of the VerifyOption interface.
+type funcVerifyOption struct {
+	f func(*verifyOptions)
+}
+
+// apply applies the given verify options to the funcVerifyOption.
+//
+// Parameters:
+// - vo: a pointer to verifyOptions
+// Returns:
+//
+//	none
+func (fvo *funcVerifyOption) apply(vo *verifyOptions) {
+	fvo.f(vo)
+}
+
+// newFuncVerifyOption returns a new instance of funcVerifyOption.
+//
+// Parameters:
+// - f: a function of type func(*verifyOptions)
+// Returns:
+// - a pointer to funcVerifyOption
+func newFuncVerifyOption(f func(*verifyOptions)) *funcVerifyOption {
+	return &funcVerifyOption{
+		f: f,
+	}
+}
+
+// VerifyOption configures the verification of VerifyMessage.
+type VerifyOption interface {
+	apply(*verifyOptions)
+}
+
+// WithPublicKey sets the public key of the account, to verify the signature locally when the
+// account is not deployed yet or has no signature verification entry point.
+//
+// WARNING: the public key is trusted as the signer of a deployed account without signature verification
+// entry point, anyone knowing such an account can sign for it with their own key. For an undeployed account,
+// the public key is only used along with WithDeployment, when it derives the address of the account.
+//
+// Parameters:
+// - publicKey: the Stark public key of the account
+// Returns:
+// - a new instance of VerifyOption
+func WithPublicKey(publicKey *felt.Felt) VerifyOption {
+	return newFuncVerifyOption(func(o *verifyOptions) {
+		o.publicKey = publicKey
+	})
+}
+
+// WithDeployment sets the deployment of an undeployed account, which proves that the public key given with
+// WithPublicKey owns the account: the address derived from the deployment must be the address of the
+// account, and the public key must be in its constructor calldata.
+//
+// Parameters:
+// - classHash: the class hash the account is deployed with
+// - salt: the salt of the deployment
+// - constructorCalldata: the arguments of the constructor of the account
+// Returns:
+// - a new instance of VerifyOption
+func WithDeployment(classHash, salt *felt.Felt, constructorCalldata []*felt.Felt) VerifyOption {
+	return newFuncVerifyOption(func(o *verifyOptions) {
+		o.deployment = &accountDeployment{classHash: classHash, salt: salt, constructorCalldata: constructorCalldata}
+	})
+}
+
+// WithBlockID sets the block of the state of the account used to verify the signature, the pending block by default.
+//
+// Parameters:
+// - blockID: the block
+// Returns:
+// - a new instance of VerifyOption
+func WithBlockID(blockID rpc.BlockID) VerifyOption {
+	return newFuncVerifyOption(func(o *verifyOptions) {
+		o.blockID = blockID
+	})
+}
+
+// VerifyMessage verifies the signature of typed data by an account, such as the signature of a
+// login message. The signature is checked by the account itself, calling its is_valid_signature
+// function, or the isValidSignature function of the Cairo 0 accounts, which return either
+// ValidSignatureMagic or the boolean 1 for a valid signature. If the account is not deployed or has
+// none of these functions, the signature [r, s] is verified locally with the public key given with
+// WithPublicKey. The public key of an undeployed account must be proven to own the account with
+// WithDeployment.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - provider: the provider calling the account
+// - accountAddress: the address of the account which signed the typed data
+// - typedData: the typed data, with its message in MessageData, or in Message for the revision 0
+// - signature: the signature of the message hash of the typed data
+// - opts: the options of the verification
+// Returns:
+// - bool: true if the signature is valid
+// - error: ErrSignatureNotVerifiable if the account cannot verify the signature without public key,
+// ErrPublicKeyNotOwner if the deployment of an undeployed account does not match the public key, or an error if any
+func VerifyMessage(ctx context.Context, provider rpc.RpcProvider, accountAddress *felt.Felt, typedData TypedData, signature []*felt.Felt, opts ...VerifyOption) (bool, error) {
+	options := verifyOptions{blockID: rpc.BlockID{Tag: "pending"}}
+	for _, opt := range opts {
+		opt.apply(&options)
+	}
+
+	hash, err := messageHashOf(typedData, accountAddress)
+	if err != nil {
+		return false, err
+	}
+
+	valid, err := isValidSignature(ctx, provider, accountAddress, hash, signature, options.blockID)
+	if err == nil {
+		return valid, nil
+	}
+	var rpcErr *rpc.RPCError
+	notDeployed := errors.As(err, &rpcErr) && rpcErr.Code == rpc.ErrContractNotFound.Code
+	if !notDeployed && !errors.Is(err, errNoEntryPoint) {
+		return false, err
+	}
+	if options.publicKey == nil || (notDeployed && options.deployment == nil) {
+		return false, fmt.Errorf("%w: %v", ErrSignatureNotVerifiable, err)
+	}
+	if notDeployed {
+		if err := checkDeployment(accountAddress, options.publicKey, options.deployment); err != nil {
+			return false, err
+		}
+	}
+	return verifyLocally(hash, signature, options.publicKey), nil
+}
+
+// messageHashOf computes the message hash of typed data, from its MessageData or else from its Message.
+func messageHashOf(typedData TypedData, accountAddress *felt.Felt) (*felt.Felt, error) {
+	if typedData.MessageData != nil {
+		return typedData.MessageHash(accountAddress)
+	}
+	if typedData.Message == nil {
+		return nil, fmt.Errorf("the typed data has no message")
+	}
+	hash, err := typedData.GetMessageHash(utils.FeltToBigInt(accountAddress), typedData.Message, curve.Curve)
+	if err != nil {
+		return nil, err
+	}
+	return utils.BigIntToFelt(hash), nil
+}
+
+// isValidSignature calls the first signature verification entry point of the account.
+// It returns errNoEntryPoint if the account has none, and the error of the provider if the account is not deployed.
+func isValidSignature(ctx context.Context, provider rpc.RpcProvider, accountAddress, hash *felt.Felt, signature []*felt.Felt, blockID rpc.BlockID) (bool, error) {
+	calldata := append([]*felt.Felt{hash, new(felt.Felt).SetUint64(uint64(len(signature)))}, signature...)
+	for _, entryPoint := range isValidSignatureEntryPoints {
+		result, err := provider.Call(ctx, rpc.FunctionCall{
+			ContractAddress:    accountAddress,
+			EntryPointSelector: utils.GetSelectorFromNameFelt(entryPoint),
+			Calldata:           calldata,
+		}, blockID)
+		if err == nil {
+			return len(result) > 0 && (result[0].Equal(ValidSignatureMagic) || result[0].Equal(new(felt.Felt).SetUint64(1))), nil
+		}
+
+		var rpcErr *rpc.RPCError
+		if !errors.As(err, &rpcErr) {
+			return false, err
+		}
+		reason := strings.ToLower(fmt.Sprint(rpcErr.Data))
+		switch {
+		case strings.Contains(reason, "entry point") && strings.Contains(reason, "not found"),
+			strings.Contains(reason, "entrypoint_not_found"):
+			continue
+		case containsAny(reason, invalidSignatureReverts):
+			return false, nil
+		}
+		return false, err
+	}
+	return false, errNoEntryPoint
+}
+
+// checkDeployment checks that the deployment of an undeployed account derives its address, from the
+// zero deployer address of the DEPLOY_ACCOUNT transactions, and that its constructor receives the public key.
+func checkDeployment(accountAddress, publicKey *felt.Felt, deployment *accountDeployment) error {
+	address, err := contracts.PrecomputeAddress(&felt.Zero, deployment.salt, deployment.classHash, deployment.constructorCalldata)
+	if err != nil {
+		return err
+	}
+	if !address.Equal(accountAddress) {
+		return fmt.Errorf("%w: the deployment derives the address %s", ErrPublicKeyNotOwner, address)
+	}
+	for _, arg := range deployment.constructorCalldata {
+		if arg.Equal(publicKey) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: the public key is not in the constructor calldata", ErrPublicKeyNotOwner)
+}
+
+// verifyLocally verifies a signature [r, s] of a hash with a public key.
+func verifyLocally(hash *felt.Felt, signature []*felt.Felt, publicKey *felt.Felt) bool {
+	if len(signature) != 2 {
+		return false
+	}
+	pubX := utils.FeltToBigInt(publicKey)
+	pubY := curve.Curve.GetYCoordinate(pubX)
+	if pubY == nil {
+		return false
+	}
+	return curve.Curve.Verify(utils.FeltToBigInt(hash), utils.FeltToBigInt(signature[0]), utils.FeltToBigInt(signature[1]), pubX, pubY)
+}
+
+// containsAny reports whether a string contains any of the substrings.
+func containsAny(s string, substrings []string) bool {
+	for _, substring := range substrings {
+		if strings.Contains(s, substring) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package typed
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/rpc/rpctest"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// TestVerifyMessage tests VerifyMessage with deployed accounts answering with the 'VALID' magic value,
+// a boolean or a revert, with Cairo 0 accounts, and with undeployed accounts verified with their public key
+// and their deployment.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging test output
+// Returns:
+//
+//	none
+func TestVerifyMessage(t *testing.T) {
+	server := rpctest.NewServer()
+	defer server.Close()
+	provider, err := server.Provider()
+	require.NoError(t, err)
+
+	privateKey, err := curve.Curve.GetRandomPrivateKey()
+	require.NoError(t, err)
+	pubX, _, err := curve.Curve.PrivateToPoint(privateKey)
+	require.NoError(t, err)
+	publicKey := utils.BigIntToFelt(pubX)
+
+	td := typedDataFromFile(t, "example_enum.json")
+	classHash := utils.TestHexToFelt(t, "0x1234")
+	constructorCalldata := []*felt.Felt{publicKey}
+	accountAddress, err := contracts.PrecomputeAddress(&felt.Zero, publicKey, classHash, constructorCalldata)
+	require.NoError(t, err)
+	hash, err := td.MessageHash(accountAddress)
+	require.NoError(t, err)
+	r, s, err := curve.Curve.SignFelt(hash, utils.BigIntToFelt(privateKey))
+	require.NoError(t, err)
+	signature := []*felt.Felt{r, s}
+	wrongSignature := []*felt.Felt{s, r}
+
+	isValidSignature := utils.GetSelectorFromNameFelt("is_valid_signature")
+	isValidSignatureCairo0 := utils.GetSelectorFromNameFelt("isValidSignature")
+	ctx := context.Background()
+
+	// undeployed account
+	_, err = VerifyMessage(ctx, provider, accountAddress, td, signature)
+	require.ErrorIs(t, err, ErrSignatureNotVerifiable)
+	_, err = VerifyMessage(ctx, provider, accountAddress, td, signature, WithPublicKey(publicKey))
+	require.ErrorIs(t, err, ErrSignatureNotVerifiable)
+	deployment := WithDeployment(classHash, publicKey, constructorCalldata)
+	valid, err := VerifyMessage(ctx, provider, accountAddress, td, signature, WithPublicKey(publicKey), deployment)
+	require.NoError(t, err)
+	require.True(t, valid)
+	valid, err = VerifyMessage(ctx, provider, accountAddress, td, wrongSignature, WithPublicKey(publicKey), deployment)
+	require.NoError(t, err)
+	require.False(t, valid)
+
+	// undeployed account with the deployment of another account, or of another signer
+	otherKey := new(felt.Felt).SetUint64(1)
+	_, err = VerifyMessage(ctx, provider, accountAddress, td, signature, WithPublicKey(otherKey), deployment)
+	require.ErrorIs(t, err, ErrPublicKeyNotOwner)
+	_, err = VerifyMessage(ctx, provider, accountAddress, td, signature, WithPublicKey(publicKey),
+		WithDeployment(classHash, otherKey, constructorCalldata))
+	require.ErrorIs(t, err, ErrPublicKeyNotOwner)
+	otherAddress, err := contracts.PrecomputeAddress(&felt.Zero, publicKey, classHash, []*felt.Felt{otherKey})
+	require.NoError(t, err)
+	_, err = VerifyMessage(ctx, provider, otherAddress, td, signature, WithPublicKey(publicKey),
+		WithDeployment(classHash, publicKey, []*felt.Felt{otherKey}))
+	require.ErrorIs(t, err, ErrPublicKeyNotOwner)
+
+	// deployed account without signature verification entry point
+	server.AddContract(accountAddress, new(felt.Felt).SetUint64(1))
+	_, err = VerifyMessage(ctx, provider, accountAddress, td, signature)
+	require.ErrorIs(t, err, ErrSignatureNotVerifiable)
+	valid, err = VerifyMessage(ctx, provider, accountAddress, td, signature, WithPublicKey(publicKey))
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	// Cairo 0 account returning a boolean
+	server.SetCallResult(accountAddress, isValidSignatureCairo0, new(felt.Felt).SetUint64(1))
+	valid, err = VerifyMessage(ctx, provider, accountAddress, td, signature)
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	// SRC-6 account returning 'VALID' or 0, the public key is ignored
+	server.SetCallResult(accountAddress, isValidSignature, ValidSignatureMagic)
+	valid, err = VerifyMessage(ctx, provider, accountAddress, td, wrongSignature, WithPublicKey(publicKey))
+	require.NoError(t, err)
+	require.True(t, valid)
+	server.SetCallResult(accountAddress, isValidSignature, new(felt.Felt))
+	valid, err = VerifyMessage(ctx, provider, accountAddress, td, signature, WithPublicKey(publicKey))
+	require.NoError(t, err)
+	require.False(t, valid)
+
+	// account reverting on an invalid signature, or failing
+	server.FailNext("starknet_call", &rpc.RPCError{Code: rpc.ErrContractError.Code, Message: rpc.ErrContractError.Message, Data: map[string]string{"revert_error": "argent/invalid-signature"}})
+	valid, err = VerifyMessage(ctx, provider, accountAddress, td, wrongSignature)
+	require.NoError(t, err)
+	require.False(t, valid)
+	server.FailNext("starknet_call", rpc.ErrBlockNotFound)
+	_, err = VerifyMessage(ctx, provider, accountAddress, td, signature, WithPublicKey(publicKey))
+	require.Error(t, err)
+}